@@ -0,0 +1,120 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitoAberto é retornado sem executar a chamada enquanto o circuito está aberto.
+var ErrCircuitoAberto = errors.New("circuito aberto: serviço remoto indisponível")
+
+// Estado representa a situação atual do circuito.
+type Estado int
+
+// Os possíveis estados do circuito.
+const (
+	Fechado    Estado = iota // Chamadas passam normalmente.
+	Aberto                   // Chamadas falham imediatamente com ErrCircuitoAberto.
+	MeioAberto               // Uma chamada de teste decide se o circuito fecha ou reabre.
+)
+
+// CircuitBreaker protege chamadas a um serviço remoto: após uma sequência de
+// falhas ele abre e passa a falhar rápido, até que o tempo de espera termine.
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	estado           Estado
+	falhas           int
+	abertoEm         time.Time
+	testeEmAndamento bool
+
+	limiteFalhas int
+	tempoAberto  time.Duration
+}
+
+// New cria um circuito que abre após limiteFalhas falhas consecutivas e
+// permanece aberto por tempoAberto antes de permitir uma chamada de teste.
+func New(limiteFalhas int, tempoAberto time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		limiteFalhas: limiteFalhas,
+		tempoAberto:  tempoAberto,
+	}
+}
+
+// Executar roda fn se o circuito permitir. Um erro retornado por fn conta como
+// falha; erros que não indicam indisponibilidade (ex.: 404) não devem ser
+// retornados por fn, e sim comunicados de outra forma.
+//
+// ctx é o contexto de quem chama: se ele foi cancelado ou expirou, o erro de fn
+// é culpa de quem chamou (ex.: o usuário desistiu da requisição), não do
+// serviço remoto, e a chamada não conta nem como falha nem como sucesso.
+func (cb *CircuitBreaker) Executar(ctx context.Context, fn func() error) error {
+	if !cb.permitir() {
+		return ErrCircuitoAberto
+	}
+
+	err := fn()
+	if err != nil && ctx.Err() != nil {
+		cb.desconsiderar()
+		return err
+	}
+	cb.registrar(err == nil)
+	return err
+}
+
+// Estado retorna o estado atual do circuito.
+func (cb *CircuitBreaker) Estado() Estado {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.estado
+}
+
+func (cb *CircuitBreaker) permitir() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.estado {
+	case Aberto:
+		if time.Since(cb.abertoEm) < cb.tempoAberto {
+			return false
+		}
+		cb.estado = MeioAberto
+		cb.testeEmAndamento = true
+		return true
+	case MeioAberto:
+		// Apenas uma chamada de teste por vez.
+		if cb.testeEmAndamento {
+			return false
+		}
+		cb.testeEmAndamento = true
+		return true
+	default:
+		return true
+	}
+}
+
+// desconsiderar libera a vaga de teste sem mudar o estado nem a contagem de falhas.
+func (cb *CircuitBreaker) desconsiderar() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.testeEmAndamento = false
+}
+
+func (cb *CircuitBreaker) registrar(sucesso bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.testeEmAndamento = false
+	if sucesso {
+		cb.estado = Fechado
+		cb.falhas = 0
+		return
+	}
+
+	cb.falhas++
+	if cb.estado == MeioAberto || cb.falhas >= cb.limiteFalhas {
+		cb.estado = Aberto
+		cb.abertoEm = time.Now()
+	}
+}
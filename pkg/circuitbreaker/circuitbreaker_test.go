@@ -0,0 +1,70 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExecutar(t *testing.T) {
+	errRemoto := errors.New("serviço respondeu com status 500")
+	cancelado, cancelar := context.WithCancel(context.Background())
+	cancelar()
+
+	casos := []struct {
+		nome   string
+		ctx    context.Context
+		erro   error
+		estado Estado
+	}{
+		{"falhas do serviço abrem o circuito", context.Background(), errRemoto, Aberto},
+		{"sucessos mantêm o circuito fechado", context.Background(), nil, Fechado},
+		{"contexto cancelado por quem chamou não conta como falha", cancelado, context.Canceled, Fechado},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			cb := New(3, time.Minute)
+			for range 3 {
+				if err := cb.Executar(c.ctx, func() error { return c.erro }); !errors.Is(err, c.erro) {
+					t.Fatalf("Executar retornou %v, esperado %v", err, c.erro)
+				}
+			}
+			if got := cb.Estado(); got != c.estado {
+				t.Errorf("estado = %d, esperado %d", got, c.estado)
+			}
+		})
+	}
+}
+
+func TestExecutarComCircuitoAberto(t *testing.T) {
+	cb := New(1, time.Minute)
+	cb.Executar(context.Background(), func() error { return errors.New("falha") })
+
+	chamou := false
+	err := cb.Executar(context.Background(), func() error { chamou = true; return nil })
+	if !errors.Is(err, ErrCircuitoAberto) || chamou {
+		t.Errorf("com o circuito aberto, Executar deveria falhar sem chamar fn: err = %v, chamou = %v", err, chamou)
+	}
+}
+
+func TestCancelamentoNaChamadaDeTesteNaoFechaOCircuito(t *testing.T) {
+	cb := New(1, time.Millisecond)
+	cb.Executar(context.Background(), func() error { return errors.New("falha") })
+	time.Sleep(2 * time.Millisecond)
+
+	ctx, cancelar := context.WithCancel(context.Background())
+	cancelar()
+	cb.Executar(ctx, func() error { return context.Canceled })
+
+	if got := cb.Estado(); got != MeioAberto {
+		t.Fatalf("estado = %d, esperado MeioAberto", got)
+	}
+	// A vaga de teste foi liberada: a próxima chamada é executada e decide o estado.
+	if err := cb.Executar(context.Background(), func() error { return nil }); err != nil {
+		t.Fatalf("chamada de teste recusada: %v", err)
+	}
+	if got := cb.Estado(); got != Fechado {
+		t.Errorf("estado = %d, esperado Fechado", got)
+	}
+}
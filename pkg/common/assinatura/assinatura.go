@@ -0,0 +1,80 @@
+// Package assinatura autentica as chamadas entre os serviços com um segredo
+// compartilhado: quem envia assina timestamp + "." + corpo com HMAC-SHA256 e
+// quem recebe refaz a conta, como nos webhooks de pedidos.
+package assinatura
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cabeçalhos de uma requisição assinada.
+const (
+	CabecalhoAssinatura = "X-Signature"           // "sha256=" + HMAC em hexadecimal.
+	CabecalhoTimestamp  = "X-Signature-Timestamp" // Segundos Unix do envio.
+)
+
+// TamanhoMinimoSegredo é o menor segredo aceito por NewSegredo.
+const TamanhoMinimoSegredo = 32
+
+// tolerancia é a diferença máxima entre o timestamp assinado e o relógio de
+// quem recebe: uma requisição capturada não pode ser repetida depois disso.
+const tolerancia = 5 * time.Minute
+
+// ErrAssinaturaInvalida indica uma requisição sem assinatura, com assinatura
+// que não confere ou com timestamp fora da tolerância.
+var ErrAssinaturaInvalida = errors.New("requisição sem assinatura válida")
+
+// Segredo é a chave compartilhada pelos serviços.
+type Segredo []byte
+
+// NewSegredo confere o tamanho mínimo do segredo, lido do ambiente.
+func NewSegredo(texto string) (Segredo, error) {
+	if len(texto) < TamanhoMinimoSegredo {
+		return nil, fmt.Errorf("o segredo precisa ter ao menos %d caracteres", TamanhoMinimoSegredo)
+	}
+	return Segredo(texto), nil
+}
+
+// Calcular devolve, em hexadecimal, o HMAC-SHA256 de timestamp + "." + corpo.
+func Calcular(segredo []byte, timestamp string, corpo []byte) string {
+	mac := hmac.New(sha256.New, segredo)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(corpo)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Assinar acrescenta a req os cabeçalhos da assinatura de corpo, que deve ser
+// o corpo enviado.
+func (s Segredo) Assinar(req *http.Request, corpo []byte, agora time.Time) {
+	timestamp := strconv.FormatInt(agora.Unix(), 10)
+	req.Header.Set(CabecalhoTimestamp, timestamp)
+	req.Header.Set(CabecalhoAssinatura, "sha256="+Calcular(s, timestamp, corpo))
+}
+
+// Conferir retorna ErrAssinaturaInvalida se os cabeçalhos de r não forem a
+// assinatura de corpo feita com o segredo há no máximo cinco minutos.
+func (s Segredo) Conferir(r *http.Request, corpo []byte, agora time.Time) error {
+	timestamp := r.Header.Get(CabecalhoTimestamp)
+	segundos, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: timestamp ausente ou inválido", ErrAssinaturaInvalida)
+	}
+	if diferenca := agora.Sub(time.Unix(segundos, 0)).Abs(); diferenca > tolerancia {
+		return fmt.Errorf("%w: timestamp fora da tolerância", ErrAssinaturaInvalida)
+	}
+
+	recebida, ok := strings.CutPrefix(r.Header.Get(CabecalhoAssinatura), "sha256=")
+	if !ok || !hmac.Equal([]byte(recebida), []byte(Calcular(s, timestamp, corpo))) {
+		return ErrAssinaturaInvalida
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package assinatura
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNewSegredo(t *testing.T) {
+	if _, err := NewSegredo("curto"); err == nil {
+		t.Error("segredo curto aceito")
+	}
+	if _, err := NewSegredo(""); err == nil {
+		t.Error("segredo vazio aceito")
+	}
+	if _, err := NewSegredo("segredo-de-teste-com-32-caracteres"); err != nil {
+		t.Errorf("segredo válido recusado: %v", err)
+	}
+}
+
+func TestConferir(t *testing.T) {
+	segredo := Segredo("segredo-de-teste-com-32-caracteres")
+	corpo := []byte(`{"id":"evento-1","tipo":"cliente.mesclado"}`)
+	agora := time.Unix(1_700_000_000, 0)
+
+	casos := []struct {
+		nome    string
+		alterar func(r *http.Request)
+		corpo   []byte
+		agora   time.Time
+		valida  bool
+	}{
+		{nome: "assinada agora", valida: true},
+		{nome: "relógio do receptor 4 minutos adiantado", agora: agora.Add(4 * time.Minute), valida: true},
+		{nome: "relógio do receptor 4 minutos atrasado", agora: agora.Add(-4 * time.Minute), valida: true},
+		{nome: "repetida depois da tolerância", agora: agora.Add(6 * time.Minute)},
+		{nome: "corpo alterado", corpo: []byte(`{"id":"evento-1","tipo":"cliente.mesclado","x":1}`)},
+		{nome: "sem assinatura", alterar: func(r *http.Request) { r.Header.Del(CabecalhoAssinatura) }},
+		{nome: "sem timestamp", alterar: func(r *http.Request) { r.Header.Del(CabecalhoTimestamp) }},
+		{nome: "assinatura sem prefixo", alterar: func(r *http.Request) {
+			r.Header.Set(CabecalhoAssinatura, Calcular(segredo, r.Header.Get(CabecalhoTimestamp), corpo))
+		}},
+		{nome: "outro segredo", alterar: func(r *http.Request) {
+			Segredo("outro-segredo-de-teste-com-32-caracteres").Assinar(r, corpo, agora)
+		}},
+		{nome: "timestamp trocado", alterar: func(r *http.Request) {
+			r.Header.Set(CabecalhoTimestamp, strconv.FormatInt(agora.Unix()+1, 10))
+		}},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/eventos/clientes", nil)
+			segredo.Assinar(r, corpo, agora)
+			if c.alterar != nil {
+				c.alterar(r)
+			}
+			recebido, conferidoEm := corpo, agora
+			if c.corpo != nil {
+				recebido = c.corpo
+			}
+			if !c.agora.IsZero() {
+				conferidoEm = c.agora
+			}
+
+			err := segredo.Conferir(r, recebido, conferidoEm)
+			if c.valida && err != nil {
+				t.Errorf("esperado válida, erro = %v", err)
+			}
+			if !c.valida && !errors.Is(err, ErrAssinaturaInvalida) {
+				t.Errorf("esperado ErrAssinaturaInvalida, erro = %v", err)
+			}
+		})
+	}
+}
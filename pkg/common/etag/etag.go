@@ -0,0 +1,78 @@
+package etag
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrIfMatchInvalido é retornado quando o If-Match não traz uma ETag emitida pela API.
+var ErrIfMatchInvalido = errors.New(`If-Match inválido: informe uma única ETag recebida, como "3", ou *`)
+
+// Formatar devolve a ETag de um recurso na versão informada. A versão muda a
+// cada alteração do recurso, então ela identifica a representação por inteiro.
+func Formatar(versao int) string {
+	return `"` + strconv.Itoa(versao) + `"`
+}
+
+// VersaoEsperada lê a versão exigida pelo If-Match. Sem o header, ou com "*",
+// retorna zero: a alteração não depende da versão atual.
+//
+// ETags fracas nunca satisfazem o If-Match (RFC 9110, comparação forte) e
+// listas com várias ETags não são suportadas; os dois casos são erro.
+func VersaoEsperada(r *http.Request) (int, error) {
+	valor := strings.TrimSpace(r.Header.Get("If-Match"))
+	if valor == "" || valor == "*" {
+		return 0, nil
+	}
+
+	versao, ok := versaoDa(valor)
+	if !ok {
+		return 0, ErrIfMatchInvalido
+	}
+	return versao, nil
+}
+
+// NaoModificado informa se o If-None-Match da requisição já contém a ETag
+// atual, caso em que o GET pode responder 304 sem corpo. A comparação é a
+// fraca: W/"3" e "3" são equivalentes.
+func NaoModificado(r *http.Request, etag string) bool {
+	valor := r.Header.Get("If-None-Match")
+	if valor == "" {
+		return false
+	}
+	if strings.TrimSpace(valor) == "*" {
+		return true
+	}
+
+	for _, candidata := range strings.Split(valor, ",") {
+		candidata = strings.TrimPrefix(strings.TrimSpace(candidata), "W/")
+		if candidata == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusConflito é o status para uma alteração recusada por conflito de
+// versão: 412 quando o cliente enviou If-Match, 409 quando o recurso foi
+// alterado por outra requisição enquanto esta executava.
+func StatusConflito(r *http.Request) int {
+	if r.Header.Get("If-Match") != "" {
+		return http.StatusPreconditionFailed
+	}
+	return http.StatusConflict
+}
+
+// versaoDa extrai a versão de uma ETag forte no formato de Formatar.
+func versaoDa(etag string) (int, bool) {
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return 0, false
+	}
+	versao, err := strconv.Atoi(etag[1 : len(etag)-1])
+	if err != nil || versao <= 0 {
+		return 0, false
+	}
+	return versao, true
+}
@@ -0,0 +1,53 @@
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ContentType é o media type de um JSON Merge Patch (RFC 7396).
+const ContentType = "application/merge-patch+json"
+
+// ErrPatchInvalido é retornado quando o patch não é um JSON válido.
+var ErrPatchInvalido = errors.New("JSON Merge Patch inválido")
+
+// Aplicar aplica um JSON Merge Patch (RFC 7396) ao documento original:
+// campos do patch substituem os do original, objetos são mesclados
+// recursivamente e campos com valor null são removidos.
+func Aplicar(original, patch []byte) ([]byte, error) {
+	var doc any
+	if len(original) > 0 {
+		if err := json.Unmarshal(original, &doc); err != nil {
+			return nil, err
+		}
+	}
+
+	var p any
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, ErrPatchInvalido
+	}
+
+	return json.Marshal(mesclar(doc, p))
+}
+
+func mesclar(alvo, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		// Um patch que não é objeto substitui o alvo por inteiro.
+		return patch
+	}
+
+	alvoObj, ok := alvo.(map[string]any)
+	if !ok {
+		alvoObj = map[string]any{}
+	}
+
+	for chave, valor := range patchObj {
+		if valor == nil {
+			delete(alvoObj, chave)
+			continue
+		}
+		alvoObj[chave] = mesclar(alvoObj[chave], valor)
+	}
+	return alvoObj
+}
@@ -0,0 +1,40 @@
+package problema
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Idiomas das mensagens de validação.
+const (
+	IdiomaPortugues = "pt-BR"
+	IdiomaIngles    = "en"
+)
+
+// Idioma escolhe o idioma das mensagens pelo Accept-Language da requisição:
+// o suportado de maior preferência (q), ou português quando nenhum é.
+func Idioma(r *http.Request) string {
+	idioma, melhor := IdiomaPortugues, 0.0
+	for _, item := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, parametros, _ := strings.Cut(strings.TrimSpace(item), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(parametros), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= melhor {
+			continue
+		}
+
+		switch prefixo, _, _ := strings.Cut(strings.ToLower(tag), "-"); prefixo {
+		case "pt":
+			idioma, melhor = IdiomaPortugues, q
+		case "en":
+			idioma, melhor = IdiomaIngles, q
+		}
+	}
+	return idioma
+}
@@ -0,0 +1,189 @@
+// Package problema responde erros da API no formato application/problem+json
+// (RFC 9457, que substitui a RFC 7807).
+//
+// Cada serviço monta um Catalogo que associa seus erros de domínio a um
+// status HTTP e a um código estável, que os clientes da API podem usar em vez
+// de interpretar a mensagem. Erros fora do catálogo viram 500 com uma
+// mensagem genérica: a causa fica só no log, junto do trace ID devolvido na
+// resposta.
+package problema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// TipoConteudo é o Content-Type das respostas de erro.
+const TipoConteudo = "application/problem+json"
+
+// Códigos dos problemas comuns a todos os serviços.
+const (
+	CodigoErroInterno       = "erro_interno"
+	CodigoCorpoInvalido     = "corpo_invalido"
+	CodigoCorpoGrandeDemais = "corpo_grande_demais"
+	CodigoValidacao         = "validacao_falhou"
+	CodigoNaoEncontrado     = "recurso_nao_encontrado"
+	CodigoMetodoInvalido    = "metodo_nao_permitido"
+)
+
+// Problema é o corpo de uma resposta de erro.
+type Problema struct {
+	Tipo      string      `json:"type"`             // URI que identifica o problema: "urn:problema:" + código.
+	Titulo    string      `json:"title"`            // Resumo fixo do problema, igual para todas as ocorrências.
+	Status    int         `json:"status"`           // Status HTTP da resposta.
+	Detalhe   string      `json:"detail,omitempty"` // Explicação desta ocorrência.
+	Instancia string      `json:"instance,omitempty"`
+	Codigo    string      `json:"codigo"` // Código estável, como "pedido_nao_encontrado".
+	TraceID   string      `json:"trace_id,omitempty"`
+	Campos    []ErroCampo `json:"campos,omitempty"` // Erros de validação, um por campo.
+}
+
+// ErroCampo é um erro de validação de um campo do corpo ou da query.
+// Campo usa o nome do JSON, com o caminho para campos aninhados (itens[0].quantidade).
+type ErroCampo struct {
+	Campo    string `json:"campo"`
+	Regra    string `json:"regra"` // Código estável da regra violada, como "obrigatorio".
+	Mensagem string `json:"mensagem"`
+}
+
+// ErrosDeCampo é um erro de validação com os problemas de cada campo. As
+// mensagens são montadas no idioma pedido pelo cliente (ver Idioma).
+//
+// Sozinho, é respondido como 422 com os campos no corpo; embrulhado em um
+// erro do catálogo, completa o problema daquele erro.
+type ErrosDeCampo interface {
+	error
+	CamposNoIdioma(idioma string) []ErroCampo
+}
+
+// Erros dos corpos de requisição, comuns a todos os serviços.
+var (
+	ErrCorpoInvalido     = errors.New("corpo da requisição inválido")
+	ErrCorpoGrandeDemais = errors.New("corpo da requisição grande demais")
+)
+
+// Escrever envia o problema como resposta, completando o tipo, a instância
+// e o trace ID quando ausentes.
+func Escrever(w http.ResponseWriter, r *http.Request, p Problema) {
+	if p.Tipo == "" {
+		p.Tipo = "urn:problema:" + p.Codigo
+	}
+	if p.Instancia == "" {
+		p.Instancia = r.URL.Path
+	}
+	if p.TraceID == "" {
+		p.TraceID = TraceID(r)
+	}
+
+	w.Header().Set("Content-Type", TipoConteudo)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// NaoEncontrado responde 404 para rotas inexistentes (chi.Router.NotFound).
+func NaoEncontrado(w http.ResponseWriter, r *http.Request) {
+	Escrever(w, r, Problema{
+		Status: http.StatusNotFound,
+		Codigo: CodigoNaoEncontrado,
+		Titulo: "Recurso não encontrado",
+	})
+}
+
+// MetodoNaoPermitido responde 405 para métodos sem rota (chi.Router.MethodNotAllowed).
+func MetodoNaoPermitido(w http.ResponseWriter, r *http.Request) {
+	Escrever(w, r, Problema{
+		Status:  http.StatusMethodNotAllowed,
+		Codigo:  CodigoMetodoInvalido,
+		Titulo:  "Método não permitido",
+		Detalhe: fmt.Sprintf("%s não é aceito em %s", r.Method, r.URL.Path),
+	})
+}
+
+// Tipo descreve como um erro de domínio é respondido.
+type Tipo struct {
+	Status int
+	Codigo string
+	Titulo string
+}
+
+type entrada struct {
+	err  error
+	tipo Tipo
+}
+
+// Catalogo associa erros de domínio a tipos de problema.
+type Catalogo struct {
+	entradas []entrada
+}
+
+// NewCatalogo cria um catálogo que já conhece os erros de corpo de requisição.
+func NewCatalogo() *Catalogo {
+	return (&Catalogo{}).
+		Registrar(ErrCorpoInvalido, http.StatusBadRequest, CodigoCorpoInvalido, "Corpo da requisição inválido").
+		Registrar(ErrCorpoGrandeDemais, http.StatusRequestEntityTooLarge, CodigoCorpoGrandeDemais, "Corpo da requisição grande demais")
+}
+
+// Registrar associa err (e os erros que o embrulham) a um tipo de problema.
+// Quando um erro corresponde a mais de uma entrada, vale a registrada primeiro.
+//
+// Para status 4xx a mensagem do erro vai no detail e deve ser segura para o
+// cliente; para 5xx ela só vai para o log.
+func (c *Catalogo) Registrar(err error, status int, codigo, titulo string) *Catalogo {
+	c.entradas = append(c.entradas, entrada{err: err, tipo: Tipo{Status: status, Codigo: codigo, Titulo: titulo}})
+	return c
+}
+
+// Problema monta o problema correspondente a err. Erros de validação
+// (ErrosDeCampo) embrulhados no erro vão nos campos. Erros desconhecidos
+// viram 500 e são registrados no log com o trace ID da requisição.
+func (c *Catalogo) Problema(r *http.Request, err error) Problema {
+	var campos []ErroCampo
+	var errosDeCampo ErrosDeCampo
+	if errors.As(err, &errosDeCampo) {
+		campos = errosDeCampo.CamposNoIdioma(Idioma(r))
+	}
+
+	for _, e := range c.entradas {
+		if !errors.Is(err, e.err) {
+			continue
+		}
+		p := Problema{Status: e.tipo.Status, Codigo: e.tipo.Codigo, Titulo: e.tipo.Titulo, Campos: campos}
+		if p.Status < http.StatusInternalServerError {
+			p.Detalhe = err.Error()
+		} else {
+			registrar(r, err)
+		}
+		return p
+	}
+
+	if campos != nil {
+		return Problema{
+			Status:  http.StatusUnprocessableEntity,
+			Codigo:  CodigoValidacao,
+			Titulo:  "Dados inválidos",
+			Detalhe: "Um ou mais campos não passaram na validação",
+			Campos:  campos,
+		}
+	}
+
+	registrar(r, err)
+	return Problema{
+		Status:  http.StatusInternalServerError,
+		Codigo:  CodigoErroInterno,
+		Titulo:  "Erro interno",
+		Detalhe: "Ocorreu um erro inesperado; informe o trace_id ao suporte",
+	}
+}
+
+// Escrever responde err como problem+json.
+func (c *Catalogo) Escrever(w http.ResponseWriter, r *http.Request, err error) {
+	Escrever(w, r, c.Problema(r, err))
+}
+
+func registrar(r *http.Request, err error) {
+	log.Printf("Erro em %s %s (trace %s): %v", r.Method, r.URL.Path, TraceID(r), err)
+}
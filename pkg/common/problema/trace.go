@@ -0,0 +1,54 @@
+package problema
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// CabecalhoTraceID devolve ao cliente o trace ID da requisição.
+const CabecalhoTraceID = "X-Trace-Id"
+
+type chaveTraceID struct{}
+
+// Rastreamento é o middleware que dá a cada requisição um trace ID, lido do
+// traceparent (W3C, enviado pelo Cloud Run e por proxies com tracing) ou do
+// X-Request-Id, ou gerado quando nenhum dos dois vem. O ID vai no contexto,
+// no cabeçalho X-Trace-Id da resposta e nos problemas respondidos.
+func Rastreamento(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := traceIDDosCabecalhos(r)
+		if id == "" {
+			b := make([]byte, 16)
+			rand.Read(b)
+			id = hex.EncodeToString(b)
+		}
+
+		w.Header().Set(CabecalhoTraceID, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), chaveTraceID{}, id)))
+	})
+}
+
+// TraceID retorna o trace ID da requisição. Sem o middleware Rastreamento,
+// usa os cabeçalhos recebidos e pode ser vazio.
+func TraceID(r *http.Request) string {
+	if id, ok := r.Context().Value(chaveTraceID{}).(string); ok {
+		return id
+	}
+	return traceIDDosCabecalhos(r)
+}
+
+// traceIDDosCabecalhos extrai o trace-id de "versão-traceid-spanid-flags"
+// ou, na falta dele, usa o X-Request-Id.
+func traceIDDosCabecalhos(r *http.Request) string {
+	partes := strings.Split(r.Header.Get("traceparent"), "-")
+	if len(partes) == 4 && len(partes[1]) == 32 {
+		return partes[1]
+	}
+	if id := r.Header.Get("X-Request-Id"); id != "" && len(id) <= 128 {
+		return id
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+package validacao
+
+import (
+	"bytes"
+	"ecommerce/pkg/common/problema"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// TamanhoMaximoCorpo é o maior corpo de requisição aceito pelos decodificadores.
+const TamanhoMaximoCorpo = 1 << 20 // 1 MiB
+
+// ErrCorpoVazio é o problema.ErrCorpoInvalido de uma requisição sem corpo,
+// para os handlers em que o corpo é opcional.
+var ErrCorpoVazio = fmt.Errorf("%w: corpo vazio", problema.ErrCorpoInvalido)
+
+// Decodificar lê o corpo JSON da requisição em destino, recusando corpos
+// acima de TamanhoMaximoCorpo, campos desconhecidos e conteúdo depois do
+// objeto. Se destino tiver o método Validar, ele é chamado em seguida.
+//
+// Os erros embrulham problema.ErrCorpoInvalido (400) ou
+// problema.ErrCorpoGrandeDemais (413); os de Validar são devolvidos como estão.
+func Decodificar(w http.ResponseWriter, r *http.Request, destino any) error {
+	corpo, err := LerCorpo(w, r)
+	if err != nil {
+		return err
+	}
+	return DecodificarJSON(corpo, destino)
+}
+
+// LerCorpo lê o corpo da requisição até TamanhoMaximoCorpo.
+func LerCorpo(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	corpo, err := io.ReadAll(http.MaxBytesReader(w, r.Body, TamanhoMaximoCorpo))
+	var excedido *http.MaxBytesError
+	if errors.As(err, &excedido) {
+		return nil, fmt.Errorf("%w: o limite é de %d bytes", problema.ErrCorpoGrandeDemais, excedido.Limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", problema.ErrCorpoInvalido, err)
+	}
+	return corpo, nil
+}
+
+// DecodificarJSON é o Decodificar de um JSON já lido.
+func DecodificarJSON(dados []byte, destino any) error {
+	if len(bytes.TrimSpace(dados)) == 0 {
+		return ErrCorpoVazio
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(dados))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(destino); err != nil {
+		return erroDeDecodificacao(err)
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: conteúdo após o fim do JSON", problema.ErrCorpoInvalido)
+	}
+
+	if validavel, ok := destino.(interface{ Validar() error }); ok {
+		return validavel.Validar()
+	}
+	return nil
+}
+
+// erroDeDecodificacao descreve um erro de json.Decoder, apontando o campo
+// quando ele é desconhecido ou tem o tipo errado.
+func erroDeDecodificacao(err error) error {
+	var tipo *json.UnmarshalTypeError
+	if errors.As(err, &tipo) && tipo.Field != "" {
+		return fmt.Errorf("%w: %w", problema.ErrCorpoInvalido, Erros{
+			{Campo: tipo.Field, Regra: RegraTipo, Args: []any{tipoJSON(tipo)}},
+		})
+	}
+	// O encoding/json não tem um tipo para este erro, só a mensagem.
+	if campo, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return fmt.Errorf("%w: %w", problema.ErrCorpoInvalido, Erros{
+			{Campo: strings.Trim(campo, `"`), Regra: RegraCampoDesconhecido},
+		})
+	}
+	var sintaxe *json.SyntaxError
+	if errors.As(err, &sintaxe) {
+		return fmt.Errorf("%w: JSON malformado na posição %d", problema.ErrCorpoInvalido, sintaxe.Offset)
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: JSON incompleto", problema.ErrCorpoInvalido)
+	}
+	return fmt.Errorf("%w: %v", problema.ErrCorpoInvalido, err)
+}
+
+// tipoJSON é o tipo JSON correspondente ao tipo Go esperado.
+func tipoJSON(err *json.UnmarshalTypeError) string {
+	switch err.Type.Kind().String() {
+	case "string":
+		return "string"
+	case "bool":
+		return "boolean"
+	case "slice", "array":
+		return "array"
+	case "map", "struct":
+		return "object"
+	default:
+		return "number"
+	}
+}
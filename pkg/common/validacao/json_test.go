@@ -0,0 +1,119 @@
+package validacao
+
+import (
+	"ecommerce/pkg/common/problema"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// entrada é um DTO de exemplo com Validar, como os dos serviços.
+type entrada struct {
+	Nome  string `json:"nome"`
+	Email string `json:"email"`
+	Itens []int  `json:"itens"`
+}
+
+func (in entrada) Validar() error {
+	v := New()
+	v.Obrigatorio("nome", in.Nome)
+	v.Email("email", in.Email)
+	v.ItensEntre("itens", len(in.Itens), 1, 10)
+	return v.Erro()
+}
+
+func TestDecodificar(t *testing.T) {
+	casos := []struct {
+		nome   string
+		corpo  string
+		status int
+		campos []problema.ErroCampo
+	}{
+		{nome: "válido", corpo: `{"nome":"Maria","email":"maria@exemplo.com","itens":[1]}`},
+		{nome: "vazio", corpo: "  ", status: http.StatusBadRequest},
+		{nome: "malformado", corpo: `{"nome":`, status: http.StatusBadRequest},
+		{
+			nome:   "campo desconhecido",
+			corpo:  `{"nome":"Maria","email":"maria@exemplo.com","itens":[1],"idade":30}`,
+			status: http.StatusBadRequest,
+			campos: []problema.ErroCampo{{Campo: "idade", Regra: RegraCampoDesconhecido, Mensagem: "campo desconhecido"}},
+		},
+		{
+			nome:   "tipo errado",
+			corpo:  `{"nome":1}`,
+			status: http.StatusBadRequest,
+			campos: []problema.ErroCampo{{Campo: "nome", Regra: RegraTipo, Mensagem: "deve ser do tipo JSON string"}},
+		},
+		{nome: "conteúdo após o JSON", corpo: `{"nome":"Maria","email":"maria@exemplo.com","itens":[1]} {}`, status: http.StatusBadRequest},
+		{nome: "acima do limite", corpo: `{"nome":"` + strings.Repeat("a", TamanhoMaximoCorpo) + `"}`, status: http.StatusRequestEntityTooLarge},
+		{
+			nome:   "vários campos inválidos",
+			corpo:  `{"nome":"","email":"maria","itens":[]}`,
+			status: http.StatusUnprocessableEntity,
+			campos: []problema.ErroCampo{
+				{Campo: "nome", Regra: RegraObrigatorio, Mensagem: "é obrigatório"},
+				{Campo: "email", Regra: RegraEmail, Mensagem: "deve ser um e-mail válido"},
+				{Campo: "itens", Regra: RegraItensEntre, Mensagem: "deve ter entre 1 e 10 itens"},
+			},
+		},
+	}
+	catalogo := problema.NewCatalogo()
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(c.corpo))
+			var destino entrada
+			err := Decodificar(httptest.NewRecorder(), r, &destino)
+			if c.status == 0 {
+				if err != nil {
+					t.Fatalf("erro = %v", err)
+				}
+				if destino.Nome != "Maria" {
+					t.Errorf("nome = %q, esperado Maria", destino.Nome)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("esperado erro")
+			}
+
+			p := catalogo.Problema(r, err)
+			if p.Status != c.status {
+				t.Errorf("status = %d, esperado %d (erro: %v)", p.Status, c.status, err)
+			}
+			if len(p.Campos) != len(c.campos) {
+				t.Fatalf("campos = %+v, esperado %+v", p.Campos, c.campos)
+			}
+			for i := range c.campos {
+				if p.Campos[i] != c.campos[i] {
+					t.Errorf("campo %d = %+v, esperado %+v", i, p.Campos[i], c.campos[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodificarRespondeNoIdiomaPedido(t *testing.T) {
+	casos := []struct {
+		acceptLanguage string
+		mensagem       string
+	}{
+		{"", "é obrigatório"},
+		{"en-US,en;q=0.9", "is required"},
+		{"fr-FR, en;q=0.5", "is required"},
+		{"en;q=0.4, pt-BR;q=0.8", "é obrigatório"},
+		{"de", "é obrigatório"},
+	}
+	for _, c := range casos {
+		t.Run(c.acceptLanguage, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"nome":" ","email":"maria@exemplo.com","itens":[1]}`))
+			r.Header.Set("Accept-Language", c.acceptLanguage)
+			err := Decodificar(httptest.NewRecorder(), r, &entrada{})
+
+			p := problema.NewCatalogo().Problema(r, err)
+			if p.Status != http.StatusUnprocessableEntity || len(p.Campos) != 1 || p.Campos[0].Mensagem != c.mensagem {
+				t.Errorf("problema = %+v, esperado 422 com a mensagem %q", p, c.mensagem)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package validacao
+
+import (
+	"ecommerce/pkg/common/problema"
+	"fmt"
+	"sync"
+)
+
+// Regras deste pacote. Os nomes vão no campo "regra" das respostas e fazem
+// parte do contrato da API.
+const (
+	RegraObrigatorio       = "obrigatorio"
+	RegraTamanhoMaximo     = "tamanho_maximo"
+	RegraEmail             = "email"
+	RegraCEP               = "cep"
+	RegraUUID              = "uuid"
+	RegraUmDe              = "um_de"
+	RegraMinimo            = "minimo"
+	RegraMaximo            = "maximo"
+	RegraPositivo          = "positivo"
+	RegraItensEntre        = "itens_entre"
+	RegraCampoDesconhecido = "campo_desconhecido"
+	RegraTipo              = "tipo"
+)
+
+// traducao é o modelo de mensagem de uma regra em cada idioma, com os
+// parâmetros da falha no formato de fmt.
+type traducao struct {
+	portugues, ingles string
+}
+
+var (
+	mu        sync.RWMutex
+	mensagens = map[string]traducao{
+		RegraObrigatorio:       {"é obrigatório", "is required"},
+		RegraTamanhoMaximo:     {"deve ter no máximo %d caracteres", "must have at most %d characters"},
+		RegraEmail:             {"deve ser um e-mail válido", "must be a valid email address"},
+		RegraCEP:               {"deve ser um CEP com 8 dígitos, como 01310-100", "must be a CEP with 8 digits, such as 01310-100"},
+		RegraUUID:              {"deve ser um UUID", "must be a UUID"},
+		RegraUmDe:              {"deve ser um de: %s", "must be one of: %s"},
+		RegraMinimo:            {"deve ser no mínimo %d", "must be at least %d"},
+		RegraMaximo:            {"deve ser no máximo %d", "must be at most %d"},
+		RegraPositivo:          {"deve ser maior que zero", "must be greater than zero"},
+		RegraItensEntre:        {"deve ter entre %d e %d itens", "must have between %d and %d items"},
+		RegraCampoDesconhecido: {"campo desconhecido", "unknown field"},
+		RegraTipo:              {"deve ser do tipo JSON %s", "must be a JSON %s"},
+	}
+)
+
+// RegistrarMensagens define as mensagens de uma regra própria de um serviço,
+// usada com Validador.Falhar. Deve ser chamada na inicialização do pacote.
+func RegistrarMensagens(regra, portugues, ingles string) {
+	mu.Lock()
+	defer mu.Unlock()
+	mensagens[regra] = traducao{portugues: portugues, ingles: ingles}
+}
+
+// CamposNoIdioma implementa problema.ErrosDeCampo.
+func (e Erros) CamposNoIdioma(idioma string) []problema.ErroCampo {
+	campos := make([]problema.ErroCampo, len(e))
+	for i, f := range e {
+		campos[i] = problema.ErroCampo{Campo: f.Campo, Regra: f.Regra, Mensagem: mensagem(f, idioma)}
+	}
+	return campos
+}
+
+func mensagem(f Falha, idioma string) string {
+	mu.RLock()
+	t, ok := mensagens[f.Regra]
+	mu.RUnlock()
+	if !ok {
+		return f.Regra
+	}
+
+	modelo := t.portugues
+	if idioma == problema.IdiomaIngles {
+		modelo = t.ingles
+	}
+	if len(f.Args) == 0 {
+		return modelo
+	}
+	return fmt.Sprintf(modelo, f.Args...)
+}
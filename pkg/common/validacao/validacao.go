@@ -0,0 +1,156 @@
+// Package validacao confere os DTOs de entrada da API antes que eles cheguem
+// ao domínio, juntando os erros de todos os campos em uma só resposta.
+//
+// Cada DTO tem um método Validar escrito com um Validador:
+//
+//	func (in ClienteInput) Validar() error {
+//		v := validacao.New()
+//		v.Obrigatorio("nome", in.Nome)
+//		v.Email("email", in.Email)
+//		return v.Erro()
+//	}
+//
+// O erro retornado é um problema.ErrosDeCampo: respondido pelo pacote
+// problema como 422, com uma mensagem por campo em português ou inglês,
+// conforme o Accept-Language.
+package validacao
+
+import (
+	"ecommerce/pkg/common/problema"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Falha é uma regra violada por um campo.
+type Falha struct {
+	Campo string
+	Regra string
+	Args  []any // Parâmetros da mensagem da regra, como o tamanho máximo.
+}
+
+// Erros são as falhas de validação de um DTO.
+type Erros []Falha
+
+// Error junta as mensagens em português.
+func (e Erros) Error() string {
+	mensagens := make([]string, len(e))
+	for i, f := range e {
+		mensagens[i] = f.Campo + ": " + mensagem(f, problema.IdiomaPortugues)
+	}
+	return strings.Join(mensagens, "; ")
+}
+
+// Validador acumula as falhas de validação de um DTO. As regras de texto
+// ignoram espaços nas pontas; as de formato não se aplicam a campos vazios,
+// que são tratados por Obrigatorio.
+type Validador struct {
+	falhas Erros
+}
+
+// New cria um Validador sem falhas.
+func New() *Validador {
+	return &Validador{}
+}
+
+// Erro retorna as falhas acumuladas, ou nil se não houver nenhuma.
+func (v *Validador) Erro() error {
+	if len(v.falhas) == 0 {
+		return nil
+	}
+	return v.falhas
+}
+
+// Falhar registra uma falha. As regras deste pacote usam Falhar; regras
+// próprias de um serviço precisam ter mensagens registradas com RegistrarMensagens.
+func (v *Validador) Falhar(campo, regra string, args ...any) {
+	v.falhas = append(v.falhas, Falha{Campo: campo, Regra: regra, Args: args})
+}
+
+// Verificar registra a falha quando ok é falso e retorna ok.
+func (v *Validador) Verificar(ok bool, campo, regra string, args ...any) bool {
+	if !ok {
+		v.Falhar(campo, regra, args...)
+	}
+	return ok
+}
+
+// Obrigatorio exige um texto não vazio.
+func (v *Validador) Obrigatorio(campo, valor string) bool {
+	return v.Verificar(strings.TrimSpace(valor) != "", campo, RegraObrigatorio)
+}
+
+// TamanhoMaximo limita o número de caracteres do texto.
+func (v *Validador) TamanhoMaximo(campo, valor string, maximo int) bool {
+	return v.Verificar(utf8.RuneCountInString(strings.TrimSpace(valor)) <= maximo, campo, RegraTamanhoMaximo, maximo)
+}
+
+// Email exige um endereço de e-mail simples, como nome@dominio.com.
+func (v *Validador) Email(campo, valor string) bool {
+	valor = strings.TrimSpace(valor)
+	if valor == "" {
+		return true
+	}
+	endereco, err := mail.ParseAddress(valor)
+	ok := err == nil && endereco.Address == valor && strings.Contains(valor[strings.LastIndex(valor, "@"):], ".")
+	return v.Verificar(ok, campo, RegraEmail)
+}
+
+var formatoCEP = regexp.MustCompile(`^\d{5}-?\d{3}$`)
+
+// CEP exige 8 dígitos, com ou sem hífen (01310-100 ou 01310100).
+func (v *Validador) CEP(campo, valor string) bool {
+	valor = strings.TrimSpace(valor)
+	if valor == "" {
+		return true
+	}
+	return v.Verificar(formatoCEP.MatchString(valor), campo, RegraCEP)
+}
+
+// UUID exige um identificador no formato UUID.
+func (v *Validador) UUID(campo, valor string) bool {
+	valor = strings.TrimSpace(valor)
+	if valor == "" {
+		return true
+	}
+	return v.Verificar(uuid.Validate(valor) == nil, campo, RegraUUID)
+}
+
+// UmDe exige um dos valores informados.
+func (v *Validador) UmDe(campo, valor string, opcoes ...string) bool {
+	for _, opcao := range opcoes {
+		if valor == opcao {
+			return true
+		}
+	}
+	return v.Verificar(false, campo, RegraUmDe, strings.Join(opcoes, ", "))
+}
+
+// Minimo exige um número maior ou igual a minimo.
+func (v *Validador) Minimo(campo string, valor, minimo int64) bool {
+	return v.Verificar(valor >= minimo, campo, RegraMinimo, minimo)
+}
+
+// Maximo exige um número menor ou igual a maximo.
+func (v *Validador) Maximo(campo string, valor, maximo int64) bool {
+	return v.Verificar(valor <= maximo, campo, RegraMaximo, maximo)
+}
+
+// Positivo exige um número maior que zero.
+func (v *Validador) Positivo(campo string, valor int64) bool {
+	return v.Verificar(valor > 0, campo, RegraPositivo)
+}
+
+// ItensEntre exige uma lista com entre minimo e maximo itens.
+func (v *Validador) ItensEntre(campo string, quantidade, minimo, maximo int) bool {
+	return v.Verificar(quantidade >= minimo && quantidade <= maximo, campo, RegraItensEntre, minimo, maximo)
+}
+
+// Indice monta o nome de um campo de um item de lista, como itens[0].quantidade.
+func Indice(lista string, i int, campo string) string {
+	return fmt.Sprintf("%s[%d].%s", lista, i, campo)
+}
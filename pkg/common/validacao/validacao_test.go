@@ -0,0 +1,93 @@
+package validacao
+
+import (
+	"ecommerce/pkg/common/problema"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidadorJuntaAsFalhasDeTodosOsCampos(t *testing.T) {
+	v := New()
+	v.Obrigatorio("nome", "   ")
+	v.TamanhoMaximo("apelido", "Maria", 10)
+	v.TamanhoMaximo("sobrenome", "Conceição", 5)
+	v.Email("email", "maria@exemplo")
+	v.Email("email_secundario", "")
+	v.CEP("cep", "1310-100")
+	v.UUID("cliente_id", "123")
+	v.UmDe("tipo", "cobranca", "entrega", "residencial")
+	v.Minimo(Indice("itens", 0, "quantidade"), 0, 1)
+	v.Maximo(Indice("itens", 1, "quantidade"), 1000, 999)
+	v.Positivo("preco.valor", -1)
+	v.ItensEntre("itens", 0, 1, 50)
+
+	var erros Erros
+	if !errors.As(v.Erro(), &erros) {
+		t.Fatalf("Erro() = %v, esperado validacao.Erros", v.Erro())
+	}
+	esperado := Erros{
+		{Campo: "nome", Regra: RegraObrigatorio},
+		{Campo: "sobrenome", Regra: RegraTamanhoMaximo, Args: []any{5}},
+		{Campo: "email", Regra: RegraEmail},
+		{Campo: "cep", Regra: RegraCEP},
+		{Campo: "cliente_id", Regra: RegraUUID},
+		{Campo: "tipo", Regra: RegraUmDe, Args: []any{"entrega, residencial"}},
+		{Campo: "itens[0].quantidade", Regra: RegraMinimo, Args: []any{int64(1)}},
+		{Campo: "itens[1].quantidade", Regra: RegraMaximo, Args: []any{int64(999)}},
+		{Campo: "preco.valor", Regra: RegraPositivo},
+		{Campo: "itens", Regra: RegraItensEntre, Args: []any{1, 50}},
+	}
+	if !reflect.DeepEqual(erros, esperado) {
+		t.Errorf("falhas =\n%#v\nesperado\n%#v", erros, esperado)
+	}
+}
+
+func TestValidadorSemFalhas(t *testing.T) {
+	v := New()
+	v.Obrigatorio("nome", "Maria")
+	v.Email("email", "maria@exemplo.com")
+	v.CEP("cep", "01310100")
+	v.CEP("cep_com_hifen", "01310-100")
+	v.UUID("cliente_id", "6f1c1f4e-8a3b-4c55-9a0e-1d2f3a4b5c6d")
+	v.UmDe("tipo", "entrega", "entrega", "cobranca")
+	v.Positivo("preco.valor", 1)
+	if err := v.Erro(); err != nil {
+		t.Errorf("Erro() = %v, esperado nil", err)
+	}
+}
+
+func TestMensagensPorIdioma(t *testing.T) {
+	RegistrarMensagens("teste_par", "deve ser par", "must be even")
+	erros := Erros{
+		{Campo: "nome", Regra: RegraObrigatorio},
+		{Campo: "itens", Regra: RegraItensEntre, Args: []any{1, 50}},
+		{Campo: "quantidade", Regra: "teste_par"},
+		{Campo: "outro", Regra: "regra_sem_mensagem"},
+	}
+
+	casos := []struct {
+		idioma    string
+		mensagens []string
+	}{
+		{problema.IdiomaPortugues, []string{"é obrigatório", "deve ter entre 1 e 50 itens", "deve ser par", "regra_sem_mensagem"}},
+		{problema.IdiomaIngles, []string{"is required", "must have between 1 and 50 items", "must be even", "regra_sem_mensagem"}},
+	}
+	for _, c := range casos {
+		t.Run(c.idioma, func(t *testing.T) {
+			campos := erros.CamposNoIdioma(c.idioma)
+			if len(campos) != len(erros) {
+				t.Fatalf("campos = %d, esperado %d", len(campos), len(erros))
+			}
+			for i, campo := range campos {
+				if campo.Campo != erros[i].Campo || campo.Regra != erros[i].Regra || campo.Mensagem != c.mensagens[i] {
+					t.Errorf("campo %d = %+v, esperado mensagem %q", i, campo, c.mensagens[i])
+				}
+			}
+		})
+	}
+
+	if got, want := erros[:2].Error(), "nome: é obrigatório; itens: deve ter entre 1 e 50 itens"; got != want {
+		t.Errorf("Error() = %q, esperado %q", got, want)
+	}
+}
@@ -0,0 +1,293 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrSchemaDesatualizado indica que há migrações embutidas ainda não aplicadas no banco.
+var ErrSchemaDesatualizado = errors.New("schema do banco de dados desatualizado")
+
+// tabelaMigracoes guarda as versões já aplicadas. Cada serviço tem seu próprio banco.
+const tabelaMigracoes = "schema_migrations"
+
+// Migracao é um par de scripts SQL versionados: Up aplica e Down reverte.
+type Migracao struct {
+	Versao int64
+	Nome   string
+	Up     string
+	Down   string
+}
+
+// StatusMigracao informa se uma migração já foi aplicada e quando.
+type StatusMigracao struct {
+	Migracao
+	Aplicada   bool
+	AplicadaEm time.Time
+}
+
+// Migrador aplica as migrações de um serviço. Toda execução roda em uma única
+// transação protegida por advisory lock, então várias instâncias subindo ao
+// mesmo tempo não aplicam a mesma migração duas vezes.
+type Migrador struct {
+	db        *sql.DB
+	migracoes []Migracao
+	lockID    int64
+}
+
+// NewMigrador lê as migrações de fsys, no formato <versao>_<nome>.up.sql e
+// <versao>_<nome>.down.sql (ex.: 0001_schema_inicial.up.sql). servico
+// identifica o advisory lock usado pelo serviço.
+func NewMigrador(db *sql.DB, fsys fs.FS, servico string) (*Migrador, error) {
+	migracoes, err := lerMigracoes(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte("migracoes:" + servico))
+
+	return &Migrador{db: db, migracoes: migracoes, lockID: int64(h.Sum64())}, nil
+}
+
+// Up aplica todas as migrações pendentes, em ordem, e retorna quantas foram aplicadas.
+func (m *Migrador) Up(ctx context.Context) (int, error) {
+	aplicadas := 0
+	err := m.emTransacao(ctx, func(tx *sql.Tx, feitas map[int64]time.Time) error {
+		for _, mig := range m.migracoes {
+			if _, ok := feitas[mig.Versao]; ok {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+				return fmt.Errorf("falha ao aplicar a migração %d (%s): %w", mig.Versao, mig.Nome, err)
+			}
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO `+tabelaMigracoes+` (versao, nome, aplicada_em) VALUES ($1, $2, now())`,
+				mig.Versao, mig.Nome,
+			); err != nil {
+				return err
+			}
+			aplicadas++
+		}
+		return nil
+	})
+	return aplicadas, err
+}
+
+// Down reverte a última migração aplicada. Retorna false se não havia nada a reverter.
+func (m *Migrador) Down(ctx context.Context) (bool, error) {
+	revertida := false
+	err := m.emTransacao(ctx, func(tx *sql.Tx, feitas map[int64]time.Time) error {
+		for i := len(m.migracoes) - 1; i >= 0; i-- {
+			mig := m.migracoes[i]
+			if _, ok := feitas[mig.Versao]; !ok {
+				continue
+			}
+			if strings.TrimSpace(mig.Down) == "" {
+				return fmt.Errorf("a migração %d (%s) não tem script de reversão", mig.Versao, mig.Nome)
+			}
+			if _, err := tx.ExecContext(ctx, mig.Down); err != nil {
+				return fmt.Errorf("falha ao reverter a migração %d (%s): %w", mig.Versao, mig.Nome, err)
+			}
+			if _, err := tx.ExecContext(ctx, `DELETE FROM `+tabelaMigracoes+` WHERE versao = $1`, mig.Versao); err != nil {
+				return err
+			}
+			revertida = true
+			return nil
+		}
+		return nil
+	})
+	return revertida, err
+}
+
+// Status lista todas as migrações conhecidas e se já foram aplicadas.
+func (m *Migrador) Status(ctx context.Context) ([]StatusMigracao, error) {
+	var status []StatusMigracao
+	err := m.emTransacao(ctx, func(_ *sql.Tx, feitas map[int64]time.Time) error {
+		for _, mig := range m.migracoes {
+			aplicadaEm, ok := feitas[mig.Versao]
+			status = append(status, StatusMigracao{Migracao: mig, Aplicada: ok, AplicadaEm: aplicadaEm})
+		}
+		return nil
+	})
+	return status, err
+}
+
+// VerificarAtualizado retorna ErrSchemaDesatualizado se houver migrações pendentes.
+// Os serviços chamam esta função na inicialização e se recusam a subir sem o schema em dia.
+func (m *Migrador) VerificarAtualizado(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	var pendentes []string
+	for _, s := range status {
+		if !s.Aplicada {
+			pendentes = append(pendentes, fmt.Sprintf("%d_%s", s.Versao, s.Nome))
+		}
+	}
+	if len(pendentes) > 0 {
+		return fmt.Errorf("%w: migrações pendentes %s (execute 'migrate up')", ErrSchemaDesatualizado, strings.Join(pendentes, ", "))
+	}
+	return nil
+}
+
+// ExecutarComando interpreta os argumentos do subcomando "migrate" (up, down ou status)
+// e escreve o resultado em saida.
+func (m *Migrador) ExecutarComando(ctx context.Context, args []string, saida io.Writer) error {
+	if len(args) != 1 {
+		return errors.New("uso: migrate up|down|status")
+	}
+
+	switch args[0] {
+	case "up":
+		aplicadas, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(saida, "%d migração(ões) aplicada(s)\n", aplicadas)
+	case "down":
+		revertida, err := m.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if !revertida {
+			fmt.Fprintln(saida, "Nenhuma migração para reverter")
+			return nil
+		}
+		fmt.Fprintln(saida, "Última migração revertida")
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range status {
+			situacao := "pendente"
+			if s.Aplicada {
+				situacao = "aplicada em " + s.AplicadaEm.Format(time.RFC3339)
+			}
+			fmt.Fprintf(saida, "%04d  %-40s %s\n", s.Versao, s.Nome, situacao)
+		}
+	default:
+		return fmt.Errorf("comando de migração desconhecido %q: use up, down ou status", args[0])
+	}
+	return nil
+}
+
+// emTransacao abre a transação, obtém o advisory lock do serviço, garante a
+// tabela de controle e entrega a fn as versões já aplicadas.
+func (m *Migrador) emTransacao(ctx context.Context, fn func(tx *sql.Tx, feitas map[int64]time.Time) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// O lock é liberado automaticamente no fim da transação.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, m.lockID); err != nil {
+		return fmt.Errorf("falha ao obter o lock de migração: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+tabelaMigracoes+` (
+		versao      BIGINT PRIMARY KEY,
+		nome        TEXT NOT NULL,
+		aplicada_em TIMESTAMPTZ NOT NULL
+	)`)
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT versao, aplicada_em FROM `+tabelaMigracoes)
+	if err != nil {
+		return err
+	}
+	feitas := make(map[int64]time.Time)
+	for rows.Next() {
+		var versao int64
+		var aplicadaEm time.Time
+		if err := rows.Scan(&versao, &aplicadaEm); err != nil {
+			rows.Close()
+			return err
+		}
+		feitas[versao] = aplicadaEm
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if err := fn(tx, feitas); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// lerMigracoes monta a lista ordenada de migrações a partir dos arquivos .sql.
+func lerMigracoes(fsys fs.FS) ([]Migracao, error) {
+	arquivos, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	porVersao := make(map[int64]*Migracao)
+	for _, arquivo := range arquivos {
+		base := path.Base(arquivo)
+
+		var direcao string
+		switch {
+		case strings.HasSuffix(base, ".up.sql"):
+			direcao = "up"
+		case strings.HasSuffix(base, ".down.sql"):
+			direcao = "down"
+		default:
+			return nil, fmt.Errorf("arquivo de migração %q deve terminar em .up.sql ou .down.sql", base)
+		}
+
+		nomeCompleto := strings.TrimSuffix(base, "."+direcao+".sql")
+		versaoTexto, nome, ok := strings.Cut(nomeCompleto, "_")
+		versao, err := strconv.ParseInt(versaoTexto, 10, 64)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("arquivo de migração %q deve começar com <versao>_", base)
+		}
+
+		conteudo, err := fs.ReadFile(fsys, arquivo)
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := porVersao[versao]
+		if !ok {
+			mig = &Migracao{Versao: versao, Nome: nome}
+			porVersao[versao] = mig
+		}
+		if mig.Nome != nome {
+			return nil, fmt.Errorf("a versão %d aparece com nomes diferentes: %q e %q", versao, mig.Nome, nome)
+		}
+		if direcao == "up" {
+			mig.Up = string(conteudo)
+		} else {
+			mig.Down = string(conteudo)
+		}
+	}
+
+	migracoes := make([]Migracao, 0, len(porVersao))
+	for _, mig := range porVersao {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("a migração %d (%s) não tem script .up.sql", mig.Versao, mig.Nome)
+		}
+		migracoes = append(migracoes, *mig)
+	}
+	sort.Slice(migracoes, func(i, j int) bool { return migracoes[i].Versao < migracoes[j].Versao })
+	return migracoes, nil
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// Querier é o conjunto de operações comum ao pool e a uma transação pgx.
+// Os repositórios escrevem suas queries contra ele, sem saber se estão
+// dentro de uma transação aberta por WithTx.
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	CopyFrom(ctx context.Context, tabela pgx.Identifier, colunas []string, fonte pgx.CopyFromSource) (int64, error)
+}
+
+// Pool é o pool nativo do pgx acrescido do controle de transações por contexto.
+type Pool struct {
+	*pgxpool.Pool
+}
+
+// NewPool cria o pool de conexões pgx.
+//
+// A configuração parte dos valores padrão, recebe as opções do código e, por
+// último, as variáveis de ambiente DB_* (ver lerAmbiente), para que cada
+// serviço ajuste o pool sem alterar código. Se o banco não responder, a conexão
+// é tentada novamente com backoff exponencial até ctx ou o tempo máximo expirar.
+// Em pgxpool as conexões ociosas são controladas pelo tempo ocioso.
+func NewPool(ctx context.Context, opcoes ...Opcao) (*Pool, error) {
+	dsn, err := lerDSN()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := montarConfig(opcoes)
+	if err != nil {
+		return nil, err
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao interpretar DATABASE_URL: %w", err)
+	}
+	cfg.aplicarParametros(poolConfig.ConnConfig)
+	poolConfig.ConnConfig.OnNotice = registrarAviso
+	if cfg.maxConexoesAbertas > 0 {
+		poolConfig.MaxConns = int32(min(cfg.maxConexoesAbertas, math.MaxInt32))
+	}
+	if cfg.tempoDeVida > 0 {
+		poolConfig.MaxConnLifetime = cfg.tempoDeVida
+	}
+	if cfg.tempoOcioso > 0 {
+		poolConfig.MaxConnIdleTime = cfg.tempoOcioso
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar o pool de conexões: %w", err)
+	}
+
+	if err := pingarComRetentativas(ctx, pool.Ping, cfg); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("falha ao pingar o DB: %w", err)
+	}
+
+	fmt.Println("Conexão com o banco de dados estabelecida com sucesso!")
+	return &Pool{Pool: pool}, nil
+}
+
+// registrarAviso leva ao log os WARNING enviados pelo Postgres, como os das
+// migrações que deixam um passo para depois; as NOTICE são ignoradas. A
+// severidade é comparada sem tradução, que depende de lc_messages.
+func registrarAviso(_ *pgconn.PgConn, aviso *pgconn.Notice) {
+	if aviso.SeverityUnlocalized != "WARNING" {
+		return
+	}
+	if aviso.Hint != "" {
+		log.Printf("Aviso do banco de dados: %s (%s)", aviso.Message, aviso.Hint)
+		return
+	}
+	log.Printf("Aviso do banco de dados: %s", aviso.Message)
+}
+
+// Querier retorna a transação aberta por WithTx em ctx ou, fora de uma
+// transação, o próprio pool.
+func (p *Pool) Querier(ctx context.Context) Querier {
+	if estado := estadoDaTx(ctx); estado != nil {
+		return estado.tx
+	}
+	return p.Pool
+}
+
+// SQLDB expõe o pool como *sql.DB, para código que depende de database/sql
+// (ex.: o Migrador). Fechar o *sql.DB retornado não fecha o pool.
+func (p *Pool) SQLDB() *sql.DB {
+	return stdlib.OpenDBFromPool(p.Pool)
+}
@@ -1,30 +1,214 @@
 package db
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"os"
+	"strconv"
+	"time"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5"
 )
 
-// NewConnection cria e retorna uma nova conexão com o banco de dados PostgreSQL.
-func NewConnection() (*sql.DB, error) {
-	// Pega a string de conexão da variável de ambiente injetada pelo Cloud Run.
+// config reúne os ajustes do pool e da conexão inicial.
+type config struct {
+	maxConexoesAbertas int
+	tempoDeVida        time.Duration
+	tempoOcioso        time.Duration
+	statementTimeout   time.Duration
+	applicationName    string
+
+	// Tentativas de conexão na inicialização: a espera entre elas dobra a cada
+	// falha, de esperaInicial até esperaMaxima, e tudo termina em tempoMaximo.
+	tempoMaximo   time.Duration
+	esperaInicial time.Duration
+	esperaMaxima  time.Duration
+}
+
+// configPadrao é pensada para o Cloud Run: poucas conexões por instância e
+// tolerância a um Postgres lento no cold start.
+func configPadrao() config {
+	return config{
+		maxConexoesAbertas: 10,
+		tempoDeVida:        30 * time.Minute,
+		tempoOcioso:        5 * time.Minute,
+		statementTimeout:   30 * time.Second,
+		tempoMaximo:        30 * time.Second,
+		esperaInicial:      250 * time.Millisecond,
+		esperaMaxima:       5 * time.Second,
+	}
+}
+
+// Opcao ajusta a configuração usada por NewPool.
+type Opcao func(*config)
+
+// ComMaxConexoesAbertas limita o número de conexões abertas. O pgxpool não
+// tem pool sem limite: 0 mantém o padrão dele, o maior entre 4 e o número de CPUs.
+func ComMaxConexoesAbertas(n int) Opcao {
+	return func(c *config) { c.maxConexoesAbertas = n }
+}
+
+// ComTempoDeVida define por quanto tempo uma conexão pode ser reutilizada.
+func ComTempoDeVida(d time.Duration) Opcao {
+	return func(c *config) { c.tempoDeVida = d }
+}
+
+// ComTempoOcioso define por quanto tempo uma conexão pode ficar ociosa no pool.
+func ComTempoOcioso(d time.Duration) Opcao {
+	return func(c *config) { c.tempoOcioso = d }
+}
+
+// ComStatementTimeout define o statement_timeout da sessão (0 = sem limite).
+func ComStatementTimeout(d time.Duration) Opcao {
+	return func(c *config) { c.statementTimeout = d }
+}
+
+// ComApplicationName define o application_name, visível em pg_stat_activity.
+func ComApplicationName(nome string) Opcao {
+	return func(c *config) { c.applicationName = nome }
+}
+
+// ComTentativas configura as tentativas de conexão na inicialização: a espera
+// começa em esperaInicial e dobra até esperaMaxima, por no máximo tempoMaximo.
+func ComTentativas(tempoMaximo, esperaInicial, esperaMaxima time.Duration) Opcao {
+	return func(c *config) {
+		c.tempoMaximo = tempoMaximo
+		c.esperaInicial = esperaInicial
+		c.esperaMaxima = esperaMaxima
+	}
+}
+
+// lerDSN pega a string de conexão da variável de ambiente injetada pelo Cloud Run.
+func lerDSN() (string, error) {
 	dsn, ok := os.LookupEnv("DATABASE_URL")
 	if !ok {
-		return nil, fmt.Errorf("a variável de ambiente DATABASE_URL não foi definida")
+		return "", fmt.Errorf("a variável de ambiente DATABASE_URL não foi definida")
+	}
+	return dsn, nil
+}
+
+// montarConfig aplica sobre os valores padrão as opções do código e depois o ambiente.
+func montarConfig(opcoes []Opcao) (config, error) {
+	cfg := configPadrao()
+	for _, opcao := range opcoes {
+		opcao(&cfg)
+	}
+	if err := lerAmbiente(&cfg); err != nil {
+		return config{}, err
+	}
+	return cfg, nil
+}
+
+// aplicarParametros define os parâmetros de sessão enviados ao abrir cada conexão.
+func (c config) aplicarParametros(connConfig *pgx.ConnConfig) {
+	if c.statementTimeout > 0 {
+		connConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(c.statementTimeout.Milliseconds(), 10)
+	}
+	if c.applicationName != "" {
+		connConfig.RuntimeParams["application_name"] = c.applicationName
+	}
+}
+
+// pingarComRetentativas chama ping até conseguir, dobrando a espera a cada
+// falha. Desiste quando ctx é cancelado ou o tempo máximo termina.
+func pingarComRetentativas(ctx context.Context, ping func(context.Context) error, cfg config) error {
+	if cfg.tempoMaximo > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.tempoMaximo)
+		defer cancel()
+	}
+
+	espera := cfg.esperaInicial
+	if espera <= 0 {
+		espera = configPadrao().esperaInicial
 	}
+	for tentativa := 1; ; tentativa++ {
+		err := ping(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return errors.Join(err, ctx.Err())
+		}
 
-	db, err := sql.Open("pgx", dsn)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao abrir conexão com o DB: %w", err)
+		log.Printf("Banco de dados indisponível (tentativa %d), nova tentativa em %s: %v", tentativa, espera, err)
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(espera):
+		}
+
+		espera *= 2
+		if cfg.esperaMaxima > 0 && espera > cfg.esperaMaxima {
+			espera = cfg.esperaMaxima
+		}
 	}
+}
+
+// obsoletas são as variáveis do antigo pool de database/sql, com o que usar no lugar.
+var obsoletas = map[string]string{
+	"DB_MAX_IDLE_CONNS": "use DB_CONN_MAX_IDLE_TIME para limitar as conexões ociosas",
+}
 
-	if err = db.Ping(); err != nil {
-		return nil, fmt.Errorf("falha ao pingar o DB: %w", err)
+// lerAmbiente sobrescreve cfg com as variáveis de ambiente definidas:
+//
+//	DB_MAX_OPEN_CONNS        máximo de conexões abertas (0 = padrão do pgxpool)
+//	DB_CONN_MAX_LIFETIME     tempo de vida de uma conexão (ex.: 30m)
+//	DB_CONN_MAX_IDLE_TIME    tempo máximo ociosa (ex.: 5m)
+//	DB_STATEMENT_TIMEOUT     statement_timeout da sessão (ex.: 30s)
+//	DB_APPLICATION_NAME      application_name da sessão
+//	DB_CONNECT_TIMEOUT       tempo máximo para conectar na inicialização
+//	DB_CONNECT_BACKOFF       espera inicial entre tentativas (ex.: 250ms)
+//	DB_CONNECT_BACKOFF_MAX   espera máxima entre tentativas (ex.: 5s)
+//
+// Variáveis que não se aplicam ao pool pgx (ver obsoletas) são recusadas, para
+// que um ajuste sem efeito não passe despercebido.
+func lerAmbiente(cfg *config) error {
+	for nome, alternativa := range obsoletas {
+		if _, ok := os.LookupEnv(nome); ok {
+			return fmt.Errorf("%s não se aplica ao pool de conexões: remova a variável e %s", nome, alternativa)
+		}
 	}
 
-	fmt.Println("Conexão com o banco de dados estabelecida com sucesso!")
-	return db, nil
+	inteiros := map[string]*int{
+		"DB_MAX_OPEN_CONNS": &cfg.maxConexoesAbertas,
+	}
+	for nome, destino := range inteiros {
+		texto, ok := os.LookupEnv(nome)
+		if !ok {
+			continue
+		}
+		valor, err := strconv.Atoi(texto)
+		if err != nil || valor < 0 {
+			return fmt.Errorf("valor inválido em %s: %q", nome, texto)
+		}
+		*destino = valor
+	}
+
+	duracoes := map[string]*time.Duration{
+		"DB_CONN_MAX_LIFETIME":   &cfg.tempoDeVida,
+		"DB_CONN_MAX_IDLE_TIME":  &cfg.tempoOcioso,
+		"DB_STATEMENT_TIMEOUT":   &cfg.statementTimeout,
+		"DB_CONNECT_TIMEOUT":     &cfg.tempoMaximo,
+		"DB_CONNECT_BACKOFF":     &cfg.esperaInicial,
+		"DB_CONNECT_BACKOFF_MAX": &cfg.esperaMaxima,
+	}
+	for nome, destino := range duracoes {
+		texto, ok := os.LookupEnv(nome)
+		if !ok {
+			continue
+		}
+		valor, err := time.ParseDuration(texto)
+		if err != nil || valor < 0 {
+			return fmt.Errorf("valor inválido em %s: %q (use durações como 500ms, 30s, 5m)", nome, texto)
+		}
+		*destino = valor
+	}
+
+	if nome, ok := os.LookupEnv("DB_APPLICATION_NAME"); ok {
+		cfg.applicationName = nome
+	}
+	return nil
 }
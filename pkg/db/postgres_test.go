@@ -0,0 +1,101 @@
+package db
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// variaveisDB são as variáveis lidas por lerAmbiente, removidas antes de cada
+// caso para que o ambiente de quem roda os testes não interfira.
+var variaveisDB = []string{
+	"DB_MAX_OPEN_CONNS",
+	"DB_MAX_IDLE_CONNS",
+	"DB_CONN_MAX_LIFETIME",
+	"DB_CONN_MAX_IDLE_TIME",
+	"DB_STATEMENT_TIMEOUT",
+	"DB_APPLICATION_NAME",
+	"DB_CONNECT_TIMEOUT",
+	"DB_CONNECT_BACKOFF",
+	"DB_CONNECT_BACKOFF_MAX",
+}
+
+func limparAmbiente(t *testing.T) {
+	t.Helper()
+	for _, nome := range variaveisDB {
+		// t.Setenv restaura o valor original ao fim do teste.
+		t.Setenv(nome, "")
+		os.Unsetenv(nome)
+	}
+}
+
+func TestMontarConfigLeOAmbiente(t *testing.T) {
+	limparAmbiente(t)
+	t.Setenv("DB_MAX_OPEN_CONNS", "25")
+	t.Setenv("DB_CONN_MAX_LIFETIME", "1h")
+	t.Setenv("DB_CONN_MAX_IDLE_TIME", "90s")
+	t.Setenv("DB_STATEMENT_TIMEOUT", "0s")
+	t.Setenv("DB_APPLICATION_NAME", "pedidos")
+	t.Setenv("DB_CONNECT_TIMEOUT", "1m")
+	t.Setenv("DB_CONNECT_BACKOFF", "100ms")
+	t.Setenv("DB_CONNECT_BACKOFF_MAX", "2s")
+
+	// O ambiente vence as opções do código.
+	cfg, err := montarConfig([]Opcao{ComMaxConexoesAbertas(5), ComApplicationName("outro")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperado := config{
+		maxConexoesAbertas: 25,
+		tempoDeVida:        time.Hour,
+		tempoOcioso:        90 * time.Second,
+		statementTimeout:   0,
+		applicationName:    "pedidos",
+		tempoMaximo:        time.Minute,
+		esperaInicial:      100 * time.Millisecond,
+		esperaMaxima:       2 * time.Second,
+	}
+	if cfg != esperado {
+		t.Errorf("config =\n%+v\nesperado\n%+v", cfg, esperado)
+	}
+}
+
+func TestMontarConfigSemAmbiente(t *testing.T) {
+	limparAmbiente(t)
+
+	cfg, err := montarConfig([]Opcao{ComMaxConexoesAbertas(5), ComStatementTimeout(time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperado := configPadrao()
+	esperado.maxConexoesAbertas = 5
+	esperado.statementTimeout = time.Second
+	if cfg != esperado {
+		t.Errorf("config =\n%+v\nesperado\n%+v", cfg, esperado)
+	}
+}
+
+func TestMontarConfigRecusaValoresInvalidos(t *testing.T) {
+	casos := []struct {
+		nome, valor, mensagem string
+	}{
+		{"DB_MAX_IDLE_CONNS", "5", "DB_CONN_MAX_IDLE_TIME"},
+		{"DB_MAX_IDLE_CONNS", "", "não se aplica"},
+		{"DB_MAX_OPEN_CONNS", "dez", "DB_MAX_OPEN_CONNS"},
+		{"DB_MAX_OPEN_CONNS", "-1", "DB_MAX_OPEN_CONNS"},
+		{"DB_CONN_MAX_LIFETIME", "30", "use durações"},
+		{"DB_CONNECT_BACKOFF", "-1s", "DB_CONNECT_BACKOFF"},
+	}
+	for _, c := range casos {
+		t.Run(c.nome+"="+c.valor, func(t *testing.T) {
+			limparAmbiente(t)
+			t.Setenv(c.nome, c.valor)
+
+			_, err := montarConfig(nil)
+			if err == nil || !strings.Contains(err.Error(), c.mensagem) {
+				t.Errorf("erro = %v, esperado um erro mencionando %q", err, c.mensagem)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATEs que indicam que a transação pode simplesmente ser repetida.
+const (
+	codigoFalhaSerializacao = "40001" // serialization_failure
+	codigoDeadlock          = "40P01" // deadlock_detected
+)
+
+// maxTentativasTx limita quantas vezes WithTx repete uma transação abortada
+// por conflito de serialização ou deadlock.
+const maxTentativasTx = 5
+
+// chaveTx é a chave do contexto que carrega a transação corrente.
+type chaveTx struct{}
+
+// estadoTx é a transação corrente e as funções a executar depois do commit.
+// Em chamadas aninhadas, tx é um savepoint.
+type estadoTx struct {
+	tx         pgx.Tx
+	aposCommit []func()
+}
+
+func estadoDaTx(ctx context.Context) *estadoTx {
+	estado, _ := ctx.Value(chaveTx{}).(*estadoTx)
+	return estado
+}
+
+// WithTx executa fn dentro de uma transação com as opções padrão do banco.
+// Ver WithTxOpcoes.
+func (p *Pool) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return p.WithTxOpcoes(ctx, pgx.TxOptions{}, fn)
+}
+
+// WithTxOpcoes executa fn dentro de uma transação. A transação viaja no
+// contexto recebido por fn, e os repositórios a obtêm com Querier, então várias
+// operações compõem uma única unidade de trabalho.
+//
+// Se fn retornar erro, tudo é desfeito. Se ctx já estiver dentro de uma
+// transação, fn roda em um savepoint: um erro desfaz apenas o que fn fez e as
+// opções são ignoradas. Na transação mais externa, conflitos de serialização e
+// deadlocks repetem fn inteira, então fn não deve ter efeitos fora do banco;
+// esses efeitos vão em AposCommit.
+func (p *Pool) WithTxOpcoes(ctx context.Context, opcoes pgx.TxOptions, fn func(ctx context.Context) error) error {
+	if pai := estadoDaTx(ctx); pai != nil {
+		return executarSavepoint(ctx, pai, fn)
+	}
+
+	for tentativa := 1; ; tentativa++ {
+		estado, err := p.executarTx(ctx, opcoes, fn)
+		if err == nil {
+			for _, f := range estado.aposCommit {
+				f()
+			}
+			return nil
+		}
+		if tentativa == maxTentativasTx || !podeRepetir(err) {
+			return err
+		}
+
+		// Espera curta e aleatória para que as transações em conflito não colidam de novo.
+		espera := time.Duration(tentativa) * (10*time.Millisecond + rand.N(10*time.Millisecond))
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(espera):
+		}
+	}
+}
+
+// AposCommit agenda f para depois do commit da transação mais externa em ctx.
+// Se a transação (ou o savepoint em que f foi agendada) for desfeita, f não
+// roda. Fora de uma transação, f roda imediatamente.
+func AposCommit(ctx context.Context, f func()) {
+	estado := estadoDaTx(ctx)
+	if estado == nil {
+		f()
+		return
+	}
+	estado.aposCommit = append(estado.aposCommit, f)
+}
+
+func (p *Pool) executarTx(ctx context.Context, opcoes pgx.TxOptions, fn func(ctx context.Context) error) (*estadoTx, error) {
+	tx, err := p.BeginTx(ctx, opcoes)
+	if err != nil {
+		return nil, err
+	}
+	// Após o commit o Rollback não faz nada; ele cobre retornos com erro e panics.
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	estado := &estadoTx{tx: tx}
+	if err := fn(context.WithValue(ctx, chaveTx{}, estado)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return estado, nil
+}
+
+func executarSavepoint(ctx context.Context, pai *estadoTx, fn func(ctx context.Context) error) error {
+	// Begin dentro de uma transação pgx cria um savepoint.
+	savepoint, err := pai.tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer savepoint.Rollback(context.WithoutCancel(ctx))
+
+	estado := &estadoTx{tx: savepoint}
+	if err := fn(context.WithValue(ctx, chaveTx{}, estado)); err != nil {
+		return err
+	}
+	if err := savepoint.Commit(ctx); err != nil {
+		return err
+	}
+
+	// O savepoint foi liberado: suas funções passam a depender da transação de fora.
+	pai.aposCommit = append(pai.aposCommit, estado.aposCommit...)
+	return nil
+}
+
+// podeRepetir informa se o erro vem de um conflito que se resolve repetindo a transação.
+func podeRepetir(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == codigoFalhaSerializacao || pgErr.Code == codigoDeadlock
+}
@@ -0,0 +1,257 @@
+package idempotencia
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"ecommerce/pkg/common/problema"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cabecalho é o header HTTP com a chave de idempotência escolhida pelo cliente.
+const Cabecalho = "Idempotency-Key"
+
+// CabecalhoReproduzida marca as respostas devolvidas do armazenamento, sem
+// executar a requisição de novo.
+const CabecalhoReproduzida = "Idempotent-Replayed"
+
+// tempoDeReserva é por quanto tempo a chave fica reservada para a requisição
+// em andamento. Se ela não terminar nesse prazo (ex.: a instância caiu no
+// meio), a chave volta a ser aceita; só a resposta concluída fica pelo ttl.
+const tempoDeReserva = time.Minute
+
+// margemDaReserva é o tempo da reserva deixado para guardar a resposta: o
+// contexto do handler é cancelado essa margem antes de a reserva expirar, para
+// que uma repetição nunca execute enquanto a primeira ainda está em andamento.
+const margemDaReserva = 5 * time.Second
+
+// Limites da chave e do corpo aceitos pelo middleware.
+const (
+	tamanhoMaximoChave = 255
+	tamanhoMaximoCorpo = 1 << 20 // 1 MiB
+)
+
+// ErrChaveEmUso é retornado por Armazenamento.Concluir quando a reserva da
+// chave não pertence mais a quem a fez (ex.: expirou e foi reusada).
+var ErrChaveEmUso = errors.New("chave de idempotência reservada por outra requisição")
+
+// Resposta é o que fica guardado para ser reproduzido nas repetições.
+type Resposta struct {
+	Status     int
+	Cabecalhos http.Header
+	Corpo      []byte
+}
+
+// Registro é a situação de uma chave já vista. Resposta é nil enquanto a
+// primeira requisição com a chave ainda está em andamento.
+type Registro struct {
+	Impressao string
+	Resposta  *Resposta
+}
+
+// Armazenamento guarda as chaves de idempotência e suas respostas. dono
+// identifica a requisição que fez a reserva: só ela pode concluí-la ou
+// liberá-la.
+type Armazenamento interface {
+	// Reservar registra a chave como em andamento para dono, válida até
+	// expiraEm. Se a chave já existir e não tiver expirado, nada é alterado e
+	// o registro existente é retornado; nil indica que a reserva foi feita agora.
+	Reservar(ctx context.Context, escopo, chave, dono, impressao string, expiraEm time.Time) (*Registro, error)
+	// Concluir guarda a resposta da requisição que fez a reserva, que passa a
+	// valer até expiraEm.
+	Concluir(ctx context.Context, escopo, chave, dono string, resposta Resposta, expiraEm time.Time) error
+	// Liberar desfaz a reserva, para que a requisição possa ser repetida.
+	Liberar(ctx context.Context, escopo, chave, dono string) error
+}
+
+// Idempotencia é o middleware que torna repetições de uma requisição seguras:
+// a primeira com uma Idempotency-Key executa normalmente e tem a resposta
+// guardada; as seguintes recebem a mesma resposta sem executar de novo.
+type Idempotencia struct {
+	armazenamento Armazenamento
+	ttl           time.Duration
+	reserva       time.Duration
+}
+
+// New cria o middleware. ttl é por quanto tempo a resposta de uma chave é
+// lembrada; depois disso a mesma chave volta a executar a requisição.
+func New(armazenamento Armazenamento, ttl time.Duration) *Idempotencia {
+	return &Idempotencia{armazenamento: armazenamento, ttl: ttl, reserva: tempoDeReserva}
+}
+
+// Middleware é o middleware no formato do chi (func(http.Handler) http.Handler).
+// Requisições sem Idempotency-Key passam direto.
+//
+//   - Chave repetida com o mesmo corpo: a resposta guardada é devolvida, com
+//     Idempotent-Replayed: true.
+//   - Chave repetida com outro corpo: 422.
+//   - Chave repetida enquanto a primeira ainda executa: 409 com Retry-After.
+//     A reserva dura tempoDeReserva: se a primeira não terminar (ex.: a
+//     instância caiu), a chave volta a ser aceita depois disso. O handler
+//     roda com um contexto que vence antes da reserva.
+//
+// Respostas 5xx não são guardadas: a reserva é desfeita e o cliente pode repetir.
+func (i *Idempotencia) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chave := r.Header.Get(Cabecalho)
+		if chave == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(chave) > tamanhoMaximoChave {
+			problema.Escrever(w, r, problema.Problema{
+				Status:  http.StatusBadRequest,
+				Codigo:  "idempotency_key_invalida",
+				Titulo:  "Idempotency-Key inválida",
+				Detalhe: "Idempotency-Key deve ter no máximo " + strconv.Itoa(tamanhoMaximoChave) + " caracteres",
+			})
+			return
+		}
+
+		corpo, err := io.ReadAll(http.MaxBytesReader(w, r.Body, tamanhoMaximoCorpo))
+		var excedido *http.MaxBytesError
+		if errors.As(err, &excedido) {
+			problema.Escrever(w, r, problema.Problema{
+				Status:  http.StatusRequestEntityTooLarge,
+				Codigo:  problema.CodigoCorpoGrandeDemais,
+				Titulo:  "Corpo da requisição grande demais",
+				Detalhe: "Requisições com Idempotency-Key aceitam corpos de até " + strconv.Itoa(tamanhoMaximoCorpo) + " bytes",
+			})
+			return
+		}
+		if err != nil {
+			problema.Escrever(w, r, problema.Problema{
+				Status:  http.StatusBadRequest,
+				Codigo:  problema.CodigoCorpoInvalido,
+				Titulo:  "Corpo da requisição inválido",
+				Detalhe: "Não foi possível ler o corpo da requisição: " + err.Error(),
+			})
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(corpo))
+
+		escopo := escopoDa(r)
+		impressao := impressaoDa(r, corpo)
+
+		dono := uuid.NewString()
+		reservadaAte := time.Now().Add(i.reserva)
+		registro, err := i.armazenamento.Reservar(r.Context(), escopo, chave, dono, impressao, reservadaAte)
+		if err != nil {
+			log.Printf("Erro ao reservar a chave de idempotência (trace %s): %v", problema.TraceID(r), err)
+			problema.Escrever(w, r, problema.Problema{
+				Status:  http.StatusInternalServerError,
+				Codigo:  problema.CodigoErroInterno,
+				Titulo:  "Erro interno",
+				Detalhe: "Não foi possível verificar a Idempotency-Key; tente novamente",
+			})
+			return
+		}
+		if registro != nil {
+			reproduzir(w, r, registro, impressao)
+			return
+		}
+
+		gravador := &gravador{ResponseWriter: w, status: http.StatusOK}
+		concluida := false
+		defer func() {
+			// Panic ou erro do servidor: libera a chave para uma nova tentativa.
+			if concluida {
+				return
+			}
+			if err := i.armazenamento.Liberar(context.WithoutCancel(r.Context()), escopo, chave, dono); err != nil {
+				log.Printf("Erro ao liberar a chave de idempotência: %v", err)
+			}
+		}()
+
+		// O handler não pode passar da reserva: depois dela, uma repetição com
+		// a mesma chave executaria de novo. Vencido o contexto, banco e
+		// chamadas a outros serviços desistem, e o erro libera a chave.
+		ctx, cancelar := context.WithDeadline(r.Context(), reservadaAte.Add(-margemDaReserva))
+		defer cancelar()
+		next.ServeHTTP(gravador, r.WithContext(ctx))
+
+		if gravador.status >= http.StatusInternalServerError {
+			return
+		}
+		resposta := Resposta{Status: gravador.status, Cabecalhos: w.Header().Clone(), Corpo: gravador.corpo.Bytes()}
+		if err := i.armazenamento.Concluir(context.WithoutCancel(r.Context()), escopo, chave, dono, resposta, time.Now().Add(i.ttl)); err != nil {
+			// A resposta já foi enviada; sem o registro, uma repetição executa de novo.
+			log.Printf("Erro ao guardar a resposta idempotente: %v", err)
+			return
+		}
+		concluida = true
+	})
+}
+
+// reproduzir responde a uma chave já vista.
+func reproduzir(w http.ResponseWriter, r *http.Request, registro *Registro, impressao string) {
+	switch {
+	case registro.Impressao != impressao:
+		problema.Escrever(w, r, problema.Problema{
+			Status:  http.StatusUnprocessableEntity,
+			Codigo:  "idempotency_key_reutilizada",
+			Titulo:  "Idempotency-Key já usada com outra requisição",
+			Detalhe: "Use uma nova Idempotency-Key para requisições com outro corpo",
+		})
+	case registro.Resposta == nil:
+		w.Header().Set("Retry-After", "1")
+		problema.Escrever(w, r, problema.Problema{
+			Status:  http.StatusConflict,
+			Codigo:  "idempotency_key_em_andamento",
+			Titulo:  "Requisição com esta Idempotency-Key ainda em andamento",
+			Detalhe: "Repita a requisição depois que a primeira terminar",
+		})
+	default:
+		for nome, valores := range registro.Resposta.Cabecalhos {
+			w.Header()[nome] = valores
+		}
+		w.Header().Set(CabecalhoReproduzida, "true")
+		w.WriteHeader(registro.Resposta.Status)
+		w.Write(registro.Resposta.Corpo)
+	}
+}
+
+// escopoDa separa as chaves por rota e por chamador, para que duas
+// aplicações que escolham a mesma chave não vejam a resposta uma da outra.
+// O Kong preenche X-Consumer-Username após a autenticação.
+func escopoDa(r *http.Request) string {
+	return r.Method + " " + r.URL.Path + " " + r.Header.Get("X-Consumer-Username")
+}
+
+// impressaoDa resume a requisição para detectar a mesma chave com outro corpo.
+func impressaoDa(r *http.Request, corpo []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(corpo)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// gravador repassa a resposta ao cliente e guarda uma cópia dela.
+type gravador struct {
+	http.ResponseWriter
+	status      int
+	corpo       bytes.Buffer
+	cabecalhoOK bool
+}
+
+func (g *gravador) WriteHeader(status int) {
+	if !g.cabecalhoOK {
+		g.status = status
+		g.cabecalhoOK = true
+	}
+	g.ResponseWriter.WriteHeader(status)
+}
+
+func (g *gravador) Write(p []byte) (int, error) {
+	g.cabecalhoOK = true
+	g.corpo.Write(p)
+	return g.ResponseWriter.Write(p)
+}
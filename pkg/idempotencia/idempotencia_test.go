@@ -0,0 +1,161 @@
+package idempotencia
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// handlerContador responde com status e conta quantas vezes executou.
+func handlerContador(status int, execucoes *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*execucoes++
+		w.WriteHeader(status)
+		w.Write([]byte(`{"id":"1"}`))
+	})
+}
+
+func requisicao(chave, corpo string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/pedidos", strings.NewReader(corpo))
+	r.Header.Set(Cabecalho, chave)
+	return r
+}
+
+func TestMiddleware(t *testing.T) {
+	casos := []struct {
+		nome          string
+		statusHandler int
+		segundoCorpo  string
+		status        int
+		execucoes     int
+		reproduzida   bool
+	}{
+		{"repetição reproduz a resposta guardada", http.StatusCreated, `{"a":1}`, http.StatusCreated, 1, true},
+		{"mesma chave com outro corpo é recusada", http.StatusCreated, `{"a":2}`, http.StatusUnprocessableEntity, 1, false},
+		{"erro do servidor libera a chave", http.StatusInternalServerError, `{"a":1}`, http.StatusInternalServerError, 2, false},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			var execucoes int
+			h := New(NewArmazenamentoEmMemoria(), time.Hour).Middleware(handlerContador(c.statusHandler, &execucoes))
+
+			h.ServeHTTP(httptest.NewRecorder(), requisicao("k1", `{"a":1}`))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, requisicao("k1", c.segundoCorpo))
+
+			if w.Code != c.status {
+				t.Errorf("status = %d, esperado %d", w.Code, c.status)
+			}
+			if execucoes != c.execucoes {
+				t.Errorf("handler executou %d vezes, esperado %d", execucoes, c.execucoes)
+			}
+			if got := w.Header().Get(CabecalhoReproduzida) == "true"; got != c.reproduzida {
+				t.Errorf("%s = %v, esperado %v", CabecalhoReproduzida, got, c.reproduzida)
+			}
+		})
+	}
+}
+
+func TestReservaInterrompidaExpiraAntesDoTTL(t *testing.T) {
+	armazenamento := NewArmazenamentoEmMemoria()
+	var execucoes int
+	idem := New(armazenamento, 24*time.Hour)
+	idem.reserva = 20 * time.Millisecond
+	h := idem.Middleware(handlerContador(http.StatusCreated, &execucoes))
+
+	// Simula uma requisição que reservou a chave e nunca terminou (a instância caiu).
+	r := requisicao("k1", `{"a":1}`)
+	reservaPresa := time.Now().Add(idem.reserva)
+	if _, err := armazenamento.Reservar(context.Background(), escopoDa(r), "k1", "outra-instancia", impressaoDa(r, []byte(`{"a":1}`)), reservaPresa); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, requisicao("k1", `{"a":1}`))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("durante a reserva: status = %d, esperado 409", w.Code)
+	}
+
+	time.Sleep(2 * idem.reserva)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, requisicao("k1", `{"a":1}`))
+	if w.Code != http.StatusCreated || execucoes != 1 {
+		t.Fatalf("depois da reserva: status = %d e %d execuções, esperado 201 e 1", w.Code, execucoes)
+	}
+
+	// A resposta concluída vale pelo TTL, não pelo prazo da reserva.
+	time.Sleep(2 * idem.reserva)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, requisicao("k1", `{"a":1}`))
+	if w.Header().Get(CabecalhoReproduzida) != "true" || execucoes != 1 {
+		t.Errorf("resposta concluída não foi reproduzida: %d execuções", execucoes)
+	}
+}
+
+func TestConcluirExigeODonoDaReserva(t *testing.T) {
+	armazenamento := NewArmazenamentoEmMemoria()
+	ctx := context.Background()
+	expira := time.Now().Add(time.Minute)
+	armazenamento.Reservar(ctx, "POST /pedidos", "k1", "dono-1", "impressao", expira)
+
+	if err := armazenamento.Concluir(ctx, "POST /pedidos", "k1", "dono-2", Resposta{Status: http.StatusCreated}, expira); err != ErrChaveEmUso {
+		t.Errorf("Concluir por outro dono: erro = %v, esperado ErrChaveEmUso", err)
+	}
+	armazenamento.Liberar(ctx, "POST /pedidos", "k1", "dono-2")
+	if registro, _ := armazenamento.Reservar(ctx, "POST /pedidos", "k1", "dono-3", "impressao", expira); registro == nil {
+		t.Error("Liberar por outro dono não deveria soltar a reserva")
+	}
+}
+
+func TestHandlerTerminaAntesDaReserva(t *testing.T) {
+	var prazo time.Time
+	h := New(NewArmazenamentoEmMemoria(), time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prazo, _ = r.Context().Deadline()
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	inicio := time.Now()
+	h.ServeHTTP(httptest.NewRecorder(), requisicao("k1", `{"a":1}`))
+
+	if prazo.IsZero() {
+		t.Fatal("o handler rodou sem prazo")
+	}
+	if limite := inicio.Add(tempoDeReserva - margemDaReserva); prazo.After(limite.Add(time.Second)) {
+		t.Errorf("prazo do handler = %s depois do início, esperado até %s antes do fim da reserva", prazo.Sub(inicio), margemDaReserva)
+	}
+}
+
+// corpoComErro falha na leitura sem exceder o limite de tamanho.
+type corpoComErro struct{}
+
+func (corpoComErro) Read([]byte) (int, error) { return 0, errors.New("conexão encerrada") }
+
+func TestCorpoIlegivel(t *testing.T) {
+	casos := []struct {
+		nome   string
+		corpo  io.Reader
+		status int
+	}{
+		{"corpo acima do limite", strings.NewReader(strings.Repeat("a", tamanhoMaximoCorpo+1)), http.StatusRequestEntityTooLarge},
+		{"falha de leitura", corpoComErro{}, http.StatusBadRequest},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			var execucoes int
+			h := New(NewArmazenamentoEmMemoria(), time.Hour).Middleware(handlerContador(http.StatusCreated, &execucoes))
+			r := httptest.NewRequest(http.MethodPost, "/pedidos", c.corpo)
+			r.Header.Set(Cabecalho, "k1")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != c.status || execucoes != 0 {
+				t.Errorf("status = %d com %d execuções, esperado %d sem executar", w.Code, execucoes, c.status)
+			}
+		})
+	}
+}
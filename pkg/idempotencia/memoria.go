@@ -0,0 +1,65 @@
+package idempotencia
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ArmazenamentoEmMemoria guarda as chaves em memória, para testes e
+// desenvolvimento local. Não é compartilhado entre instâncias do serviço.
+type ArmazenamentoEmMemoria struct {
+	mu        sync.Mutex
+	registros map[string]*registroEmMemoria
+}
+
+type registroEmMemoria struct {
+	Registro
+	dono     string
+	expiraEm time.Time
+}
+
+// NewArmazenamentoEmMemoria cria o armazenamento vazio.
+func NewArmazenamentoEmMemoria() *ArmazenamentoEmMemoria {
+	return &ArmazenamentoEmMemoria{registros: make(map[string]*registroEmMemoria)}
+}
+
+// Reservar implementa Armazenamento.
+func (a *ArmazenamentoEmMemoria) Reservar(_ context.Context, escopo, chave, dono, impressao string, expiraEm time.Time) (*Registro, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	id := escopo + "\x00" + chave
+	if existente, ok := a.registros[id]; ok && time.Now().Before(existente.expiraEm) {
+		copia := existente.Registro
+		return &copia, nil
+	}
+	a.registros[id] = &registroEmMemoria{Registro: Registro{Impressao: impressao}, dono: dono, expiraEm: expiraEm}
+	return nil, nil
+}
+
+// Concluir implementa Armazenamento.
+func (a *ArmazenamentoEmMemoria) Concluir(_ context.Context, escopo, chave, dono string, resposta Resposta, expiraEm time.Time) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	registro, ok := a.registros[escopo+"\x00"+chave]
+	if !ok || registro.dono != dono || registro.Resposta != nil {
+		return ErrChaveEmUso
+	}
+	registro.Resposta = &resposta
+	registro.expiraEm = expiraEm
+	return nil
+}
+
+// Liberar implementa Armazenamento.
+func (a *ArmazenamentoEmMemoria) Liberar(_ context.Context, escopo, chave, dono string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	id := escopo + "\x00" + chave
+	if registro, ok := a.registros[id]; ok && registro.dono == dono && registro.Resposta == nil {
+		delete(a.registros, id)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package idempotencia
+
+import (
+	"context"
+	"ecommerce/pkg/db"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PostgresArmazenamento guarda as chaves na tabela chaves_idempotencia, que
+// cada serviço cria em uma migração própria.
+// A chave primária (escopo, chave) faz com que, entre requisições simultâneas
+// com a mesma chave, apenas uma consiga a reserva.
+type PostgresArmazenamento struct {
+	db *db.Pool
+}
+
+// NewPostgresArmazenamento cria o armazenamento sobre o pool do serviço.
+func NewPostgresArmazenamento(pool *db.Pool) *PostgresArmazenamento {
+	return &PostgresArmazenamento{db: pool}
+}
+
+// Reservar implementa Armazenamento. Uma chave expirada, inclusive uma reserva
+// cuja requisição não terminou, é reaproveitada como nova.
+func (a *PostgresArmazenamento) Reservar(ctx context.Context, escopo, chave, dono, impressao string, expiraEm time.Time) (*Registro, error) {
+	q := a.db.Querier(ctx)
+
+	// O ON CONFLICT só sobrescreve a linha existente se ela já expirou;
+	// sem linha retornada, a chave está viva e pertence a outra requisição.
+	var reservada bool
+	err := q.QueryRow(ctx,
+		`INSERT INTO chaves_idempotencia (escopo, chave, dono, impressao, criado_em, expira_em)
+		 VALUES ($1, $2, $3, $4, now(), $5)
+		 ON CONFLICT (escopo, chave) DO UPDATE
+		 SET dono = EXCLUDED.dono, impressao = EXCLUDED.impressao, status = NULL, cabecalhos = NULL, corpo = NULL,
+		     criado_em = EXCLUDED.criado_em, expira_em = EXCLUDED.expira_em
+		 WHERE chaves_idempotencia.expira_em <= now()
+		 RETURNING true`,
+		escopo, chave, dono, impressao, expiraEm,
+	).Scan(&reservada)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	var registro Registro
+	var status *int
+	var cabecalhos []byte
+	var corpo []byte
+	err = q.QueryRow(ctx,
+		`SELECT impressao, status, cabecalhos, corpo FROM chaves_idempotencia
+		 WHERE escopo = $1 AND chave = $2 AND expira_em > now()`,
+		escopo, chave,
+	).Scan(&registro.Impressao, &status, &cabecalhos, &corpo)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Liberada ou expirada entre as duas queries: tenta reservar de novo.
+		return a.Reservar(ctx, escopo, chave, dono, impressao, expiraEm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if status != nil {
+		registro.Resposta = &Resposta{Status: *status, Corpo: corpo}
+		if err := json.Unmarshal(cabecalhos, &registro.Resposta.Cabecalhos); err != nil {
+			return nil, err
+		}
+	}
+	return &registro, nil
+}
+
+// Concluir implementa Armazenamento. A reserva precisa ainda ser de dono:
+// se ela expirou e outra requisição pegou a chave, nada é gravado.
+func (a *PostgresArmazenamento) Concluir(ctx context.Context, escopo, chave, dono string, resposta Resposta, expiraEm time.Time) error {
+	cabecalhos, err := json.Marshal(resposta.Cabecalhos)
+	if err != nil {
+		return err
+	}
+
+	tag, err := a.db.Querier(ctx).Exec(ctx,
+		`UPDATE chaves_idempotencia SET status = $4, cabecalhos = $5, corpo = $6, expira_em = $7
+		 WHERE escopo = $1 AND chave = $2 AND dono = $3 AND status IS NULL`,
+		escopo, chave, dono, resposta.Status, cabecalhos, resposta.Corpo, expiraEm,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrChaveEmUso
+	}
+	return nil
+}
+
+// Liberar implementa Armazenamento. Só remove a reserva de dono ainda sem resposta.
+func (a *PostgresArmazenamento) Liberar(ctx context.Context, escopo, chave, dono string) error {
+	_, err := a.db.Querier(ctx).Exec(ctx,
+		`DELETE FROM chaves_idempotencia WHERE escopo = $1 AND chave = $2 AND dono = $3 AND status IS NULL`,
+		escopo, chave, dono,
+	)
+	return err
+}
+
+// RemoverExpiradas apaga as chaves que passaram do TTL.
+func (a *PostgresArmazenamento) RemoverExpiradas(ctx context.Context) (int64, error) {
+	tag, err := a.db.Querier(ctx).Exec(ctx, `DELETE FROM chaves_idempotencia WHERE expira_em <= now()`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// IniciarLimpeza roda RemoverExpiradas periodicamente até o contexto ser cancelado.
+func (a *PostgresArmazenamento) IniciarLimpeza(ctx context.Context, intervalo time.Duration) {
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := a.RemoverExpiradas(ctx); err != nil {
+				log.Printf("Erro ao remover chaves de idempotência expiradas: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MoedaPadrao é a moeda assumida quando o valor chega sem código ISO,
+// como no contrato antigo em que o preço era um número decimal.
+const MoedaPadrao = "BRL"
+
+// Erros que podem ser retornados pelas operações com dinheiro.
+var (
+	ErrMoedasDiferentes  = errors.New("operação entre moedas diferentes")
+	ErrValorInvalido     = errors.New("valor monetário inválido")
+	ErrMoedaDesconhecida = errors.New("moeda desconhecida")
+)
+
+// maxExpoente limita o expoente aceito em números JSON como 1.99e1: além
+// disso o valor não cabe em int64 ou tem casas demais de qualquer forma.
+const maxExpoente = 30
+
+// casasDecimais informa quantas casas decimais cada moeda aceita usa
+// (ISO 4217). Valores em moedas fora da tabela são recusados na entrada.
+var casasDecimais = map[string]int{
+	"BRL": 2,
+	"USD": 2,
+	"EUR": 2,
+	"JPY": 0,
+	"CLP": 0,
+	"KWD": 3,
+}
+
+// Money representa um valor monetário em unidades menores (ex.: centavos),
+// evitando os erros de arredondamento de float64.
+type Money struct {
+	Valor int64  `json:"valor"` // Em unidades menores da moeda (centavos para BRL).
+	Moeda string `json:"moeda"` // Código ISO 4217, ex.: "BRL".
+}
+
+// New cria um valor a partir das unidades menores e do código da moeda.
+func New(valor int64, moeda string) Money {
+	return Money{Valor: valor, Moeda: strings.ToUpper(moeda)}
+}
+
+// Zero retorna o valor zero na moeda informada.
+func Zero(moeda string) Money {
+	return New(0, moeda)
+}
+
+// FromDecimal converte um decimal em texto (ex.: "19.90") para Money sem passar por float.
+// O texto tem só dígitos, com um "-" opcional na frente e, se houver ".", ao
+// menos um dígito depois dele. Casas decimais além das suportadas pela moeda
+// são rejeitadas em vez de arredondadas.
+func FromDecimal(decimal string, moeda string) (Money, error) {
+	moeda = strings.ToUpper(moeda)
+	casas, ok := casasDecimais[moeda]
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrMoedaDesconhecida, moeda)
+	}
+
+	texto := strings.TrimSpace(decimal)
+	texto, negativo := strings.CutPrefix(texto, "-")
+
+	inteira, fracao, temPonto := strings.Cut(texto, ".")
+	if !soDigitos(inteira) || (temPonto && !soDigitos(fracao)) || len(fracao) > casas {
+		return Money{}, fmt.Errorf("%w: %q", ErrValorInvalido, decimal)
+	}
+	fracao += strings.Repeat("0", casas-len(fracao))
+
+	valor, err := strconv.ParseInt(inteira+fracao, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrValorInvalido, decimal)
+	}
+	if negativo {
+		valor = -valor
+	}
+
+	return Money{Valor: valor, Moeda: moeda}, nil
+}
+
+// soDigitos informa se o texto tem ao menos um caractere e só dígitos ASCII.
+func soDigitos(texto string) bool {
+	if texto == "" {
+		return false
+	}
+	for _, c := range texto {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Add soma dois valores da mesma moeda.
+func (m Money) Add(outro Money) (Money, error) {
+	if m.Moeda != outro.Moeda {
+		return Money{}, fmt.Errorf("%w: %s e %s", ErrMoedasDiferentes, m.Moeda, outro.Moeda)
+	}
+	return Money{Valor: m.Valor + outro.Valor, Moeda: m.Moeda}, nil
+}
+
+// Mul multiplica o valor por uma quantidade inteira.
+func (m Money) Mul(quantidade int) Money {
+	return Money{Valor: m.Valor * int64(quantidade), Moeda: m.Moeda}
+}
+
+// IsZero informa se o valor é zero.
+func (m Money) IsZero() bool {
+	return m.Valor == 0
+}
+
+// Decimal formata o valor como decimal, ex.: "19.90".
+func (m Money) Decimal() string {
+	casas := casasDaMoeda(m.Moeda)
+	valor := m.Valor
+	sinal := ""
+	if valor < 0 {
+		sinal = "-"
+		valor = -valor
+	}
+	if casas == 0 {
+		return sinal + strconv.FormatInt(valor, 10)
+	}
+
+	texto := strconv.FormatInt(valor, 10)
+	if len(texto) <= casas {
+		texto = strings.Repeat("0", casas-len(texto)+1) + texto
+	}
+	corte := len(texto) - casas
+	return sinal + texto[:corte] + "." + texto[corte:]
+}
+
+// String formata o valor para exibição, ex.: "BRL 19.90".
+func (m Money) String() string {
+	return m.Moeda + " " + m.Decimal()
+}
+
+// UnmarshalJSON aceita o formato novo ({"valor": 1990, "moeda": "BRL"}) e,
+// por compatibilidade, o formato antigo com número decimal (19.90 ou 1.99e1),
+// assumido em MoedaPadrao. Moedas fora de casasDecimais são recusadas.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '{' {
+		// Um tipo auxiliar evita a recursão infinita em UnmarshalJSON.
+		type moneyJSON Money
+		var aux moneyJSON
+		if err := json.Unmarshal(data, &aux); err != nil {
+			return err
+		}
+		if aux.Moeda == "" {
+			aux.Moeda = MoedaPadrao
+		}
+		if _, ok := casasDecimais[strings.ToUpper(aux.Moeda)]; !ok {
+			return fmt.Errorf("%w: %q", ErrMoedaDesconhecida, aux.Moeda)
+		}
+		*m = New(aux.Valor, aux.Moeda)
+		return nil
+	}
+
+	var numero json.Number
+	if err := json.Unmarshal(data, &numero); err != nil {
+		return fmt.Errorf("%w: %s", ErrValorInvalido, data)
+	}
+	decimal, err := semExpoente(numero.String())
+	if err != nil {
+		return err
+	}
+	convertido, err := FromDecimal(decimal, MoedaPadrao)
+	if err != nil {
+		return err
+	}
+	*m = convertido
+	return nil
+}
+
+// semExpoente reescreve um número JSON com expoente (1.99e1, 1990E-2) como
+// decimal simples ("19.9", "19.90"); sem expoente, o texto volta igual.
+func semExpoente(numero string) (string, error) {
+	i := strings.IndexAny(numero, "eE")
+	if i < 0 {
+		return numero, nil
+	}
+	mantissa, textoExpoente := numero[:i], numero[i+1:]
+	expoente, err := strconv.Atoi(textoExpoente)
+	if err != nil || expoente > maxExpoente || expoente < -maxExpoente {
+		return "", fmt.Errorf("%w: %s", ErrValorInvalido, numero)
+	}
+
+	mantissa, negativo := strings.CutPrefix(mantissa, "-")
+	inteira, fracao, _ := strings.Cut(mantissa, ".")
+	digitos := inteira + fracao
+	ponto := len(inteira) + expoente
+	switch {
+	case ponto <= 0:
+		digitos = "0." + strings.Repeat("0", -ponto) + digitos
+	case ponto >= len(digitos):
+		digitos += strings.Repeat("0", ponto-len(digitos))
+	default:
+		digitos = digitos[:ponto] + "." + digitos[ponto:]
+	}
+	if negativo {
+		digitos = "-" + digitos
+	}
+	return digitos, nil
+}
+
+// casasDaMoeda assume 2 casas para moedas fora da tabela, que só chegam aqui
+// por New.
+func casasDaMoeda(moeda string) int {
+	if casas, ok := casasDecimais[moeda]; ok {
+		return casas
+	}
+	return 2
+}
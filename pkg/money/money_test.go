@@ -0,0 +1,174 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestFromDecimal(t *testing.T) {
+	casos := []struct {
+		decimal string
+		moeda   string
+		valor   int64
+		erro    error
+	}{
+		{"19.90", "BRL", 1990, nil},
+		{"19.9", "brl", 1990, nil},
+		{"19", "BRL", 1900, nil},
+		{" 0.05 ", "BRL", 5, nil},
+		{"-5", "BRL", -500, nil},
+		{"-0.01", "BRL", -1, nil},
+		{"1500", "JPY", 1500, nil},
+		{"1.234", "KWD", 1234, nil},
+		{"19.999", "BRL", 0, ErrValorInvalido},
+		{"1.5", "JPY", 0, ErrValorInvalido},
+		{"--5", "BRL", 0, ErrValorInvalido},
+		{"+5", "BRL", 0, ErrValorInvalido},
+		{"1.", "BRL", 0, ErrValorInvalido},
+		{".5", "BRL", 0, ErrValorInvalido},
+		{"-", "BRL", 0, ErrValorInvalido},
+		{"", "BRL", 0, ErrValorInvalido},
+		{"1.-5", "BRL", 0, ErrValorInvalido},
+		{"1,50", "BRL", 0, ErrValorInvalido},
+		{"1e2", "BRL", 0, ErrValorInvalido},
+		{"١٢", "BRL", 0, ErrValorInvalido},
+		{"99999999999999999999", "BRL", 0, ErrValorInvalido},
+		{"10", "XYZ", 0, ErrMoedaDesconhecida},
+		{"10", "", 0, ErrMoedaDesconhecida},
+	}
+	for _, c := range casos {
+		t.Run(c.decimal+" "+c.moeda, func(t *testing.T) {
+			m, err := FromDecimal(c.decimal, c.moeda)
+			if c.erro != nil {
+				if !errors.Is(err, c.erro) {
+					t.Fatalf("erro = %v, esperado %v", err, c.erro)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m.Valor != c.valor {
+				t.Errorf("valor = %d, esperado %d", m.Valor, c.valor)
+			}
+		})
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	casos := []struct {
+		money   Money
+		decimal string
+	}{
+		{New(1990, "BRL"), "19.90"},
+		{New(5, "BRL"), "0.05"},
+		{New(0, "BRL"), "0.00"},
+		{New(-1, "BRL"), "-0.01"},
+		{New(-1990, "BRL"), "-19.90"},
+		{New(1500, "JPY"), "1500"},
+		{New(-7, "JPY"), "-7"},
+		{New(1234, "KWD"), "1.234"},
+		{New(5, "KWD"), "0.005"},
+	}
+	for _, c := range casos {
+		if got := c.money.Decimal(); got != c.decimal {
+			t.Errorf("%d %s: Decimal = %q, esperado %q", c.money.Valor, c.money.Moeda, got, c.decimal)
+		}
+		// A volta pelo texto devolve o mesmo valor.
+		if volta, err := FromDecimal(c.money.Decimal(), c.money.Moeda); err != nil || volta != c.money {
+			t.Errorf("FromDecimal(%q) = %v, %v", c.money.Decimal(), volta, err)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	casos := []struct {
+		json  string
+		money Money
+		erro  error
+	}{
+		{`{"valor": 1990, "moeda": "BRL"}`, New(1990, "BRL"), nil},
+		{`{"valor": 1990, "moeda": "usd"}`, New(1990, "USD"), nil},
+		{`{"valor": 1990}`, New(1990, MoedaPadrao), nil},
+		{`{"valor": 1990, "moeda": "XYZ"}`, Money{}, ErrMoedaDesconhecida},
+		{`19.90`, New(1990, MoedaPadrao), nil},
+		{`19`, New(1900, MoedaPadrao), nil},
+		{`-0.5`, New(-50, MoedaPadrao), nil},
+		{`1e1`, New(1000, MoedaPadrao), nil},
+		{`1.99E1`, New(1990, MoedaPadrao), nil},
+		{`1990e-2`, New(1990, MoedaPadrao), nil},
+		{`5e-2`, New(5, MoedaPadrao), nil},
+		{`-2.5e+0`, New(-250, MoedaPadrao), nil},
+		{`1e-3`, Money{}, ErrValorInvalido},
+		{`19.999`, Money{}, ErrValorInvalido},
+		{`1e400`, Money{}, ErrValorInvalido},
+		{`"abc"`, Money{}, ErrValorInvalido},
+		{`true`, Money{}, ErrValorInvalido},
+	}
+	for _, c := range casos {
+		t.Run(c.json, func(t *testing.T) {
+			var m Money
+			err := json.Unmarshal([]byte(c.json), &m)
+			if c.erro != nil {
+				if !errors.Is(err, c.erro) {
+					t.Fatalf("erro = %v, esperado %v", err, c.erro)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m != c.money {
+				t.Errorf("Money = %v, esperado %v", m, c.money)
+			}
+		})
+	}
+
+	// null mantém o valor anterior.
+	m := New(1, "BRL")
+	if err := json.Unmarshal([]byte(`null`), &m); err != nil || m != New(1, "BRL") {
+		t.Errorf("null: %v, %v", m, err)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	casos := []struct {
+		a, b Money
+		soma Money
+		erro error
+	}{
+		{New(1990, "BRL"), New(10, "BRL"), New(2000, "BRL"), nil},
+		{New(1990, "BRL"), New(-1990, "BRL"), New(0, "BRL"), nil},
+		{New(1, "brl"), New(1, "BRL"), New(2, "BRL"), nil},
+		{New(1990, "BRL"), New(10, "USD"), Money{}, ErrMoedasDiferentes},
+	}
+	for _, c := range casos {
+		soma, err := c.a.Add(c.b)
+		if !errors.Is(err, c.erro) {
+			t.Errorf("%v + %v: erro = %v, esperado %v", c.a, c.b, err, c.erro)
+			continue
+		}
+		if soma != c.soma {
+			t.Errorf("%v + %v = %v, esperado %v", c.a, c.b, soma, c.soma)
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+	casos := []struct {
+		money      Money
+		quantidade int
+		produto    Money
+	}{
+		{New(1990, "BRL"), 3, New(5970, "BRL")},
+		{New(1990, "BRL"), 0, New(0, "BRL")},
+		{New(1990, "BRL"), 1, New(1990, "BRL")},
+		{New(-5, "JPY"), 2, New(-10, "JPY")},
+	}
+	for _, c := range casos {
+		if got := c.money.Mul(c.quantidade); got != c.produto {
+			t.Errorf("%v × %d = %v, esperado %v", c.money, c.quantidade, got, c.produto)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+)
+
+// PublisherEmMemoria guarda os eventos publicados, para testes e
+// desenvolvimento local.
+type PublisherEmMemoria struct {
+	mu      sync.Mutex
+	eventos []Evento
+	falha   error
+}
+
+// NewPublisherEmMemoria cria o publisher sem eventos.
+func NewPublisherEmMemoria() *PublisherEmMemoria {
+	return &PublisherEmMemoria{}
+}
+
+// Publicar implementa Publisher.
+func (p *PublisherEmMemoria) Publicar(_ context.Context, evento Evento) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.falha != nil {
+		return p.falha
+	}
+	p.eventos = append(p.eventos, evento)
+	return nil
+}
+
+// FalharCom faz as próximas publicações retornarem err, para simular um
+// destino fora do ar; nil volta a aceitar os eventos.
+func (p *PublisherEmMemoria) FalharCom(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.falha = err
+}
+
+// Eventos retorna uma cópia dos eventos publicados, na ordem de publicação.
+func (p *PublisherEmMemoria) Eventos() []Evento {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Evento(nil), p.eventos...)
+}
@@ -0,0 +1,119 @@
+package outbox
+
+import (
+	"context"
+	"ecommerce/pkg/db"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Evento é um fato de domínio já ocorrido, no formato em que é publicado.
+// ID é único por evento: como a entrega é "ao menos uma vez", os consumidores
+// devem usá-lo para descartar repetições.
+type Evento struct {
+	ID         string          `json:"id"`
+	Tipo       string          `json:"tipo"`        // Ex.: "pedido.criado".
+	Agregado   string          `json:"agregado"`    // Ex.: "pedido".
+	AgregadoID string          `json:"agregado_id"` // ID da entidade que originou o evento.
+	Dados      json.RawMessage `json:"dados"`
+	OcorridoEm time.Time       `json:"ocorrido_em"`
+}
+
+// NovoEvento monta um evento com ID novo, serializando dados em JSON.
+func NovoEvento(tipo, agregado, agregadoID string, dados any) (Evento, error) {
+	corpo, err := json.Marshal(dados)
+	if err != nil {
+		return Evento{}, fmt.Errorf("falha ao serializar o evento %s: %w", tipo, err)
+	}
+	return Evento{
+		ID:         uuid.NewString(),
+		Tipo:       tipo,
+		Agregado:   agregado,
+		AgregadoID: agregadoID,
+		Dados:      corpo,
+		OcorridoEm: time.Now(),
+	}, nil
+}
+
+// Publisher entrega eventos a outros sistemas. Um erro faz o Relay tentar de
+// novo mais tarde, então Publicar pode receber o mesmo evento mais de uma vez.
+type Publisher interface {
+	Publicar(ctx context.Context, evento Evento) error
+}
+
+// maxEventosComFalha limita quantos eventos com publicação parcial o
+// Multiplos acompanha; acima disso, algum deles é esquecido e, se repetido,
+// vai de novo a todos os publishers.
+const maxEventosComFalha = 10000
+
+// Multiplos publica cada evento em todos os publishers, na ordem informada.
+// Se algum falhar, o erro faz o Relay repetir o evento, e a repetição só vai
+// aos publishers que ainda não o receberam. Esse controle fica em memória:
+// depois de um reinício, a repetição vai a todos, então cada um ainda deve
+// tolerar repetições.
+func Multiplos(publishers ...Publisher) Publisher {
+	return &multiplos{publishers: publishers, entregues: make(map[string][]bool)}
+}
+
+type multiplos struct {
+	publishers []Publisher
+
+	mu        sync.Mutex
+	entregues map[string][]bool // Por evento com falha, os publishers que já o receberam.
+}
+
+func (m *multiplos) Publicar(ctx context.Context, evento Evento) error {
+	m.mu.Lock()
+	entregues := append([]bool(nil), m.entregues[evento.ID]...)
+	m.mu.Unlock()
+	if entregues == nil {
+		entregues = make([]bool, len(m.publishers))
+	}
+
+	var errs []error
+	for i, p := range m.publishers {
+		if entregues[i] {
+			continue
+		}
+		if err := p.Publicar(ctx, evento); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		entregues[i] = true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(errs) == 0 {
+		delete(m.entregues, evento.ID)
+		return nil
+	}
+	if _, ok := m.entregues[evento.ID]; !ok && len(m.entregues) >= maxEventosComFalha {
+		for id := range m.entregues {
+			delete(m.entregues, id)
+			break
+		}
+	}
+	m.entregues[evento.ID] = entregues
+	return errors.Join(errs...)
+}
+
+// Gravar registra os eventos na tabela outbox, que cada serviço cria em uma
+// migração própria. Deve ser chamado com o Querier da transação que grava o
+// agregado (db.Pool.Querier dentro de WithTx): os eventos só passam a existir
+// se a alteração for confirmada, e nunca se perdem depois dela.
+func Gravar(ctx context.Context, q db.Querier, eventos ...Evento) error {
+	const query = `INSERT INTO outbox (id, tipo, agregado, agregado_id, dados, ocorrido_em)
+				   VALUES ($1, $2, $3, $4, $5, $6)`
+	for _, e := range eventos {
+		if _, err := q.Exec(ctx, query, e.ID, e.Tipo, e.Agregado, e.AgregadoID, e.Dados, e.OcorridoEm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
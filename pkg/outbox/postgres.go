@@ -0,0 +1,77 @@
+package outbox
+
+import (
+	"context"
+	"ecommerce/pkg/db"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// tamanhoMaximoNotificacao é o limite do Postgres para o payload de um NOTIFY.
+const tamanhoMaximoNotificacao = 8000
+
+// PostgresPublisher publica os eventos com NOTIFY em um canal do Postgres;
+// quem quiser recebê-los executa LISTEN no mesmo canal (ver Escutar).
+//
+// Chamado pelo Relay, fora de transação, o NOTIFY é entregue na hora; se a
+// marcação do evento como publicado falhar depois dele, o evento é notificado
+// de novo. Ouvintes desconectados naquele momento não recebem o evento: o
+// NOTIFY não é durável.
+type PostgresPublisher struct {
+	db    *db.Pool
+	canal string
+}
+
+// NewPostgresPublisher cria o publisher para o canal informado.
+func NewPostgresPublisher(pool *db.Pool, canal string) *PostgresPublisher {
+	return &PostgresPublisher{db: pool, canal: canal}
+}
+
+// Publicar implementa Publisher. O evento inteiro vai como JSON no payload;
+// eventos maiores que o limite do NOTIFY falham e acabam descartados.
+func (p *PostgresPublisher) Publicar(ctx context.Context, evento Evento) error {
+	payload, err := json.Marshal(evento)
+	if err != nil {
+		return err
+	}
+	if len(payload) >= tamanhoMaximoNotificacao {
+		return fmt.Errorf("evento %s com %d bytes excede o limite do NOTIFY", evento.ID, len(payload))
+	}
+
+	_, err = p.db.Querier(ctx).Exec(ctx, `SELECT pg_notify($1, $2)`, p.canal, string(payload))
+	return err
+}
+
+// Escutar executa LISTEN no canal e chama tratar para cada evento recebido,
+// até o contexto ser cancelado ou a conexão cair. Um erro de tratar encerra a
+// escuta e é retornado.
+func Escutar(ctx context.Context, pool *db.Pool, canal string, tratar func(context.Context, Evento) error) error {
+	doPool, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// A conexão fica com LISTEN ativo; ela sai do pool e é fechada no fim.
+	conn := doPool.Hijack()
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{canal}.Sanitize()); err != nil {
+		return err
+	}
+
+	for {
+		notificacao, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var evento Evento
+		if err := json.Unmarshal([]byte(notificacao.Payload), &evento); err != nil {
+			return fmt.Errorf("notificação inválida no canal %s: %w", canal, err)
+		}
+		if err := tratar(ctx, evento); err != nil {
+			return err
+		}
+	}
+}
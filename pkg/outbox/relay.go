@@ -0,0 +1,336 @@
+package outbox
+
+import (
+	"context"
+	"ecommerce/pkg/db"
+	"log"
+	"sort"
+	"time"
+)
+
+// config reúne os ajustes do Relay.
+type config struct {
+	intervalo     time.Duration
+	lote          int
+	maxTentativas int
+	esperaInicial time.Duration
+	esperaMaxima  time.Duration
+	retencao      time.Duration
+	reserva       time.Duration
+}
+
+func configPadrao() config {
+	return config{
+		intervalo:     time.Second,
+		lote:          100,
+		maxTentativas: 10,
+		esperaInicial: time.Second,
+		esperaMaxima:  10 * time.Minute,
+		retencao:      7 * 24 * time.Hour,
+		reserva:       5 * time.Minute,
+	}
+}
+
+// Opcao ajusta a configuração usada por NewRelay.
+type Opcao func(*config)
+
+// ComIntervalo define de quanto em quanto tempo o Relay procura eventos pendentes.
+func ComIntervalo(d time.Duration) Opcao {
+	return func(c *config) { c.intervalo = d }
+}
+
+// ComLote define quantos eventos são lidos e publicados por vez.
+func ComLote(n int) Opcao {
+	return func(c *config) { c.lote = n }
+}
+
+// ComMaxTentativas define após quantas falhas de publicação o evento é
+// descartado (vai para a dead-letter).
+func ComMaxTentativas(n int) Opcao {
+	return func(c *config) { c.maxTentativas = n }
+}
+
+// ComEspera define a espera antes de publicar de novo um evento que falhou:
+// ela dobra a cada falha, de inicial até maxima.
+func ComEspera(inicial, maxima time.Duration) Opcao {
+	return func(c *config) {
+		c.esperaInicial = inicial
+		c.esperaMaxima = maxima
+	}
+}
+
+// ComRetencao define por quanto tempo os eventos já publicados ficam na
+// tabela antes de serem apagados.
+func ComRetencao(d time.Duration) Opcao {
+	return func(c *config) { c.retencao = d }
+}
+
+// ComReserva define por quanto tempo um lote lido fica reservado para a
+// instância que o leu. Os eventos que ela não publicar nesse prazo, por
+// exemplo por ter caído, voltam a ficar prontos para qualquer instância.
+func ComReserva(d time.Duration) Opcao {
+	return func(c *config) { c.reserva = d }
+}
+
+// Relay lê os eventos gravados na outbox e os entrega ao Publisher.
+//
+// A entrega é "ao menos uma vez": um evento só é marcado como publicado depois
+// que Publicar retorna sem erro, então uma queda entre os dois faz com que ele
+// seja publicado de novo. Falhas são repetidas com espera exponencial e, após
+// o máximo de tentativas, o evento é descartado, ficando na tabela com
+// descartado_em e o último erro até ser reprocessado (ver Reprocessar).
+//
+// Cada lote é reservado em um único UPDATE, confirmado antes de publicar: a
+// publicação, que pode chamar outros serviços, roda fora de transação e não
+// segura bloqueios no banco. Várias instâncias do serviço podem rodar o Relay
+// ao mesmo tempo sem publicar o mesmo evento juntas, enquanto durar a reserva.
+//
+// Os eventos de um mesmo agregado são publicados na ordem de gravação: um
+// evento só é lido depois que os anteriores do seu agregado forem publicados,
+// então uma falha segura os seguintes até a nova tentativa dar certo. Um
+// evento descartado deixa de segurá-los; se for reprocessado, chega depois.
+type Relay struct {
+	db        *db.Pool
+	publisher Publisher
+	cfg       config
+}
+
+// NewRelay cria o Relay sobre o pool do serviço, que precisa ter a tabela outbox.
+func NewRelay(pool *db.Pool, publisher Publisher, opcoes ...Opcao) *Relay {
+	cfg := configPadrao()
+	for _, opcao := range opcoes {
+		opcao(&cfg)
+	}
+	return &Relay{db: pool, publisher: publisher, cfg: cfg}
+}
+
+// Executar publica os eventos pendentes até o contexto ser cancelado.
+func (r *Relay) Executar(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.intervalo)
+	defer ticker.Stop()
+	limpeza := time.NewTicker(time.Hour)
+	defer limpeza.Stop()
+
+	for {
+		// Enquanto houver eventos, segue sem pausa: o seguinte de um agregado
+		// só fica pronto depois que o anterior é publicado.
+		for {
+			n, err := r.PublicarPendentes(ctx)
+			if err != nil {
+				log.Printf("Erro ao publicar eventos da outbox: %v", err)
+				break
+			}
+			if n == 0 {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-limpeza.C:
+			if _, err := r.RemoverPublicados(ctx); err != nil {
+				log.Printf("Erro ao remover eventos publicados da outbox: %v", err)
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublicarPendentes reserva um lote de eventos prontos para envio, publica
+// cada um e retorna quantos foram processados, com ou sem sucesso. Os que
+// não couberem no prazo da reserva voltam à fila sem contar tentativa.
+func (r *Relay) PublicarPendentes(ctx context.Context) (int, error) {
+	prazo := time.Now().Add(r.cfg.reserva)
+	eventos, tentativas, err := r.reservarLote(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, evento := range eventos {
+		if time.Now().After(prazo) {
+			return i, r.liberar(ctx, eventos[i:])
+		}
+
+		ctxPublicar, cancelar := context.WithDeadline(ctx, prazo)
+		errPublicar := r.publisher.Publicar(ctxPublicar, evento)
+		cancelar()
+		if errPublicar == nil {
+			err = r.marcarPublicado(ctx, evento.ID)
+		} else {
+			err = r.registrarFalha(ctx, evento, tentativas[i]+1, errPublicar)
+		}
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(eventos), nil
+}
+
+// reservarLote reserva o próximo lote de eventos prontos para envio, em ordem
+// de gravação, com o número de tentativas já feitas para cada um. A reserva
+// adia a próxima tentativa para o fim do prazo: outra instância só os lê de
+// novo se este relay não os publicar nem registrar a falha até lá.
+func (r *Relay) reservarLote(ctx context.Context) ([]Evento, []int, error) {
+	const query = `
+		WITH lote AS (
+			SELECT o.id
+			FROM outbox o
+			WHERE o.publicado_em IS NULL AND o.descartado_em IS NULL AND o.proxima_tentativa_em <= now()
+			  AND NOT EXISTS (
+				  SELECT 1
+				  FROM outbox anterior
+				  WHERE anterior.agregado = o.agregado AND anterior.agregado_id = o.agregado_id
+					AND anterior.sequencia < o.sequencia
+					AND anterior.publicado_em IS NULL AND anterior.descartado_em IS NULL
+			  )
+			ORDER BY o.sequencia
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE outbox o SET proxima_tentativa_em = now() + $2 * interval '1 millisecond'
+		FROM lote
+		WHERE o.id = lote.id
+		RETURNING o.id, o.tipo, o.agregado, o.agregado_id, o.dados, o.ocorrido_em, o.tentativas, o.sequencia`
+
+	rows, err := r.db.Querier(ctx).Query(ctx, query, r.cfg.lote, r.cfg.reserva.Milliseconds())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	type reservado struct {
+		evento     Evento
+		tentativas int
+		sequencia  int64
+	}
+	var lote []reservado
+	for rows.Next() {
+		var e reservado
+		if err := rows.Scan(&e.evento.ID, &e.evento.Tipo, &e.evento.Agregado, &e.evento.AgregadoID, &e.evento.Dados,
+			&e.evento.OcorridoEm, &e.tentativas, &e.sequencia); err != nil {
+			return nil, nil, err
+		}
+		lote = append(lote, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// O RETURNING não segue o ORDER BY do lote.
+	sort.Slice(lote, func(i, j int) bool { return lote[i].sequencia < lote[j].sequencia })
+	eventos := make([]Evento, len(lote))
+	tentativas := make([]int, len(lote))
+	for i, e := range lote {
+		eventos[i], tentativas[i] = e.evento, e.tentativas
+	}
+	return eventos, tentativas, nil
+}
+
+// liberar devolve à fila eventos reservados que não foram publicados.
+func (r *Relay) liberar(ctx context.Context, eventos []Evento) error {
+	ids := make([]string, len(eventos))
+	for i, e := range eventos {
+		ids[i] = e.ID
+	}
+	_, err := r.db.Querier(ctx).Exec(ctx, `UPDATE outbox SET proxima_tentativa_em = now() WHERE id = ANY($1)`, ids)
+	return err
+}
+
+func (r *Relay) marcarPublicado(ctx context.Context, id string) error {
+	_, err := r.db.Querier(ctx).Exec(ctx, `UPDATE outbox SET publicado_em = now() WHERE id = $1`, id)
+	return err
+}
+
+// registrarFalha agenda uma nova tentativa ou, esgotadas as tentativas,
+// descarta o evento.
+func (r *Relay) registrarFalha(ctx context.Context, evento Evento, tentativas int, causa error) error {
+	q := r.db.Querier(ctx)
+
+	if tentativas >= r.cfg.maxTentativas {
+		log.Printf("Evento %s (%s) descartado após %d tentativas: %v", evento.ID, evento.Tipo, tentativas, causa)
+		_, err := q.Exec(ctx,
+			`UPDATE outbox SET tentativas = $2, ultimo_erro = $3, descartado_em = now() WHERE id = $1`,
+			evento.ID, tentativas, causa.Error(),
+		)
+		return err
+	}
+
+	_, err := q.Exec(ctx,
+		`UPDATE outbox SET tentativas = $2, ultimo_erro = $3, proxima_tentativa_em = $4 WHERE id = $1`,
+		evento.ID, tentativas, causa.Error(), time.Now().Add(r.espera(tentativas)),
+	)
+	return err
+}
+
+// espera é o intervalo antes da próxima tentativa: esperaInicial dobrada a
+// cada falha, limitada a esperaMaxima.
+func (r *Relay) espera(tentativas int) time.Duration {
+	espera := r.cfg.esperaInicial
+	for i := 1; i < tentativas && espera < r.cfg.esperaMaxima; i++ {
+		espera *= 2
+	}
+	return min(espera, r.cfg.esperaMaxima)
+}
+
+// Descartados retorna os eventos da dead-letter, do mais antigo para o mais
+// novo, com o último erro de publicação de cada um.
+func (r *Relay) Descartados(ctx context.Context, limite int) ([]EventoDescartado, error) {
+	const query = `
+		SELECT id, tipo, agregado, agregado_id, dados, ocorrido_em, tentativas, ultimo_erro, descartado_em
+		FROM outbox
+		WHERE descartado_em IS NOT NULL
+		ORDER BY sequencia
+		LIMIT $1`
+
+	rows, err := r.db.Querier(ctx).Query(ctx, query, limite)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	descartados := []EventoDescartado{}
+	for rows.Next() {
+		var d EventoDescartado
+		if err := rows.Scan(&d.ID, &d.Tipo, &d.Agregado, &d.AgregadoID, &d.Dados, &d.OcorridoEm,
+			&d.Tentativas, &d.UltimoErro, &d.DescartadoEm); err != nil {
+			return nil, err
+		}
+		descartados = append(descartados, d)
+	}
+	return descartados, rows.Err()
+}
+
+// EventoDescartado é um evento da dead-letter.
+type EventoDescartado struct {
+	Evento
+	Tentativas   int       `json:"tentativas"`
+	UltimoErro   string    `json:"ultimo_erro"`
+	DescartadoEm time.Time `json:"descartado_em"`
+}
+
+// Reprocessar devolve um evento descartado à fila, com as tentativas zeradas.
+// Retorna false se o evento não existir ou não estiver descartado.
+func (r *Relay) Reprocessar(ctx context.Context, id string) (bool, error) {
+	tag, err := r.db.Querier(ctx).Exec(ctx,
+		`UPDATE outbox SET descartado_em = NULL, tentativas = 0, proxima_tentativa_em = now()
+		 WHERE id = $1 AND descartado_em IS NOT NULL`,
+		id,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// RemoverPublicados apaga os eventos publicados há mais tempo que a retenção.
+func (r *Relay) RemoverPublicados(ctx context.Context) (int64, error) {
+	tag, err := r.db.Querier(ctx).Exec(ctx,
+		`DELETE FROM outbox WHERE publicado_em < $1`,
+		time.Now().Add(-r.cfg.retencao),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
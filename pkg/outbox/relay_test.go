@@ -0,0 +1,320 @@
+package outbox
+
+import (
+	"context"
+	"ecommerce/pkg/db"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+var (
+	errDestinoFora = errors.New("destino fora do ar")
+	errDesfazer    = errors.New("desfazer")
+)
+
+func TestEspera(t *testing.T) {
+	r := NewRelay(nil, nil, ComEspera(time.Second, time.Minute))
+	casos := []struct {
+		tentativas int
+		espera     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{6, 32 * time.Second},
+		{7, time.Minute},
+		{50, time.Minute},
+	}
+	for _, c := range casos {
+		if got := r.espera(c.tentativas); got != c.espera {
+			t.Errorf("espera(%d) = %s, esperado %s", c.tentativas, got, c.espera)
+		}
+	}
+}
+
+func TestMultiplos(t *testing.T) {
+	casos := []struct {
+		nome   string
+		falhas []error
+		erro   bool
+	}{
+		{"todos publicam", []error{nil, nil}, false},
+		{"um falha", []error{nil, errDestinoFora}, true},
+		{"todos falham", []error{errDestinoFora, errDestinoFora}, true},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			var publishers []Publisher
+			var memorias []*PublisherEmMemoria
+			for _, falha := range c.falhas {
+				p := NewPublisherEmMemoria()
+				p.FalharCom(falha)
+				publishers = append(publishers, p)
+				memorias = append(memorias, p)
+			}
+
+			evento, _ := NovoEvento("pedido.criado", "pedido", "p1", map[string]string{"id": "p1"})
+			err := Multiplos(publishers...).Publicar(context.Background(), evento)
+			if (err != nil) != c.erro {
+				t.Fatalf("erro = %v, esperado erro: %v", err, c.erro)
+			}
+			// Uma falha não impede a publicação nos demais.
+			for i, p := range memorias {
+				if publicados := len(p.Eventos()); (c.falhas[i] == nil) != (publicados == 1) {
+					t.Errorf("publisher %d: %d eventos publicados", i, publicados)
+				}
+			}
+		})
+	}
+}
+
+func TestMultiplosRepeteSoNosQueFalharam(t *testing.T) {
+	ok, fora := NewPublisherEmMemoria(), NewPublisherEmMemoria()
+	fora.FalharCom(errDestinoFora)
+	multiplos := Multiplos(ok, fora)
+	evento, _ := NovoEvento("cliente.mesclado", "cliente", "c1", map[string]string{"id": "c1"})
+
+	if err := multiplos.Publicar(context.Background(), evento); err == nil {
+		t.Fatal("esperado erro com um publisher fora do ar")
+	}
+	fora.FalharCom(nil)
+	if err := multiplos.Publicar(context.Background(), evento); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(ok.Eventos()); n != 1 {
+		t.Errorf("publisher que já tinha recebido: %d eventos, esperado 1", n)
+	}
+	if n := len(fora.Eventos()); n != 1 {
+		t.Errorf("publisher que falhou: %d eventos, esperado 1", n)
+	}
+
+	// Publicado em todos, o evento é esquecido: uma nova publicação vai a todos.
+	if err := multiplos.Publicar(context.Background(), evento); err != nil {
+		t.Fatal(err)
+	}
+	if len(ok.Eventos()) != 2 || len(fora.Eventos()) != 2 {
+		t.Errorf("publicados = %d e %d, esperado 2 em cada", len(ok.Eventos()), len(fora.Eventos()))
+	}
+}
+
+// poolDeTeste conecta no banco de DATABASE_URL. Sem ela, o teste é pulado.
+func poolDeTeste(t *testing.T) *db.Pool {
+	t.Helper()
+	if _, ok := os.LookupEnv("DATABASE_URL"); !ok {
+		t.Skip("DATABASE_URL não definida: o relay precisa de um Postgres")
+	}
+	pool, err := db.NewPool(context.Background(), db.ComApplicationName("outbox-teste"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+// emOutboxTemporaria roda fn em uma transação desfeita no fim, com uma tabela
+// outbox temporária igual à das migrações dos serviços. Dentro dela, o relay
+// roda na mesma transação e nada fica gravado no banco.
+func emOutboxTemporaria(t *testing.T, pool *db.Pool, fn func(ctx context.Context)) {
+	t.Helper()
+	err := pool.WithTx(context.Background(), func(ctx context.Context) error {
+		_, err := pool.Querier(ctx).Exec(ctx, `
+			CREATE TEMP TABLE outbox (
+				id                   UUID PRIMARY KEY,
+				sequencia            BIGSERIAL NOT NULL,
+				tipo                 TEXT NOT NULL,
+				agregado             TEXT NOT NULL,
+				agregado_id          TEXT NOT NULL,
+				dados                JSONB NOT NULL,
+				ocorrido_em          TIMESTAMPTZ NOT NULL,
+				tentativas           INTEGER NOT NULL DEFAULT 0,
+				proxima_tentativa_em TIMESTAMPTZ NOT NULL DEFAULT now(),
+				ultimo_erro          TEXT,
+				publicado_em         TIMESTAMPTZ,
+				descartado_em        TIMESTAMPTZ
+			) ON COMMIT DROP`)
+		if err != nil {
+			return err
+		}
+		fn(ctx)
+		return errDesfazer
+	})
+	if !errors.Is(err, errDesfazer) {
+		t.Fatal(err)
+	}
+}
+
+// liberarEspera antecipa as novas tentativas: dentro da transação de teste,
+// now() não avança e elas nunca ficariam prontas.
+func liberarEspera(t *testing.T, ctx context.Context, pool *db.Pool) {
+	t.Helper()
+	if _, err := pool.Querier(ctx).Exec(ctx, `UPDATE outbox SET proxima_tentativa_em = now() - interval '1 second'`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRelayPublicaAoMenosUmaVez(t *testing.T) {
+	pool := poolDeTeste(t)
+	casos := []struct {
+		nome          string
+		falhas        int // Rodadas em que o destino está fora do ar.
+		maxTentativas int
+		publicados    int
+		descartado    bool
+	}{
+		{"publica na primeira", 0, 3, 1, false},
+		{"publica depois das falhas", 2, 3, 1, false},
+		{"descarta ao esgotar as tentativas", 3, 3, 0, true},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			emOutboxTemporaria(t, pool, func(ctx context.Context) {
+				evento, _ := NovoEvento("pedido.criado", "pedido", "p1", map[string]string{"id": "p1"})
+				if err := Gravar(ctx, pool.Querier(ctx), evento); err != nil {
+					t.Fatal(err)
+				}
+
+				publisher := NewPublisherEmMemoria()
+				relay := NewRelay(pool, publisher, ComMaxTentativas(c.maxTentativas))
+				for rodada := range c.maxTentativas + 1 {
+					if rodada < c.falhas {
+						publisher.FalharCom(errDestinoFora)
+					} else {
+						publisher.FalharCom(nil)
+					}
+					if _, err := relay.PublicarPendentes(ctx); err != nil {
+						t.Fatal(err)
+					}
+					liberarEspera(t, ctx, pool)
+				}
+
+				if got := len(publisher.Eventos()); got != c.publicados {
+					t.Errorf("publicados = %d, esperado %d", got, c.publicados)
+				}
+				descartados, err := relay.Descartados(ctx, 10)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if (len(descartados) == 1) != c.descartado {
+					t.Fatalf("descartados = %d, esperado descartado: %v", len(descartados), c.descartado)
+				}
+				if !c.descartado {
+					return
+				}
+				if d := descartados[0]; d.ID != evento.ID || d.Tentativas != c.maxTentativas || d.UltimoErro != errDestinoFora.Error() {
+					t.Errorf("descartado = %s com %d tentativas (%q)", d.ID, d.Tentativas, d.UltimoErro)
+				}
+
+				// Reprocessado, o evento volta à fila e é publicado.
+				if ok, err := relay.Reprocessar(ctx, evento.ID); !ok || err != nil {
+					t.Fatalf("Reprocessar = %v, %v", ok, err)
+				}
+				if _, err := relay.PublicarPendentes(ctx); err != nil {
+					t.Fatal(err)
+				}
+				if got := publisher.Eventos(); len(got) != 1 || got[0].ID != evento.ID {
+					t.Errorf("publicados após reprocessar = %v", got)
+				}
+			})
+		})
+	}
+}
+
+func TestRelayRepublicaSeAMarcacaoNaoConfirmar(t *testing.T) {
+	pool := poolDeTeste(t)
+	emOutboxTemporaria(t, pool, func(ctx context.Context) {
+		evento, _ := NovoEvento("pedido.criado", "pedido", "p1", map[string]string{"id": "p1"})
+		if err := Gravar(ctx, pool.Querier(ctx), evento); err != nil {
+			t.Fatal(err)
+		}
+		publisher := NewPublisherEmMemoria()
+		relay := NewRelay(pool, publisher)
+
+		// Uma queda depois de Publicar desfaz a marcação de publicado.
+		pool.WithTx(ctx, func(ctx context.Context) error {
+			relay.PublicarPendentes(ctx)
+			return errDesfazer
+		})
+		if _, err := relay.PublicarPendentes(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		publicados := publisher.Eventos()
+		if len(publicados) != 2 || publicados[0].ID != evento.ID || publicados[1].ID != evento.ID {
+			t.Errorf("publicados = %v, esperado o mesmo evento duas vezes", publicados)
+		}
+	})
+}
+
+func TestRelayPublicaCadaAgregadoEmOrdem(t *testing.T) {
+	pool := poolDeTeste(t)
+	emOutboxTemporaria(t, pool, func(ctx context.Context) {
+		primeiro, _ := NovoEvento("cliente.mesclado", "cliente", "a", map[string]string{"de": "a", "para": "b"})
+		seguinte, _ := NovoEvento("cliente.alterado", "cliente", "a", map[string]string{"id": "a"})
+		outro, _ := NovoEvento("cliente.mesclado", "cliente", "b", map[string]string{"de": "b", "para": "c"})
+		if err := Gravar(ctx, pool.Querier(ctx), primeiro, seguinte, outro); err != nil {
+			t.Fatal(err)
+		}
+		publisher := NewPublisherEmMemoria()
+		relay := NewRelay(pool, publisher)
+
+		// O primeiro evento de "a" falha: o seguinte dele espera, o de "b" não.
+		publisher.FalharCom(errDestinoFora)
+		if n, err := relay.PublicarPendentes(ctx); err != nil || n != 2 {
+			t.Fatalf("PublicarPendentes = %d, %v; esperado 2 eventos, um de cada agregado", n, err)
+		}
+		liberarEspera(t, ctx, pool)
+
+		publisher.FalharCom(nil)
+		for range 3 {
+			if _, err := relay.PublicarPendentes(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var ordem []string
+		for _, e := range publisher.Eventos() {
+			ordem = append(ordem, e.ID)
+		}
+		esperado := []string{primeiro.ID, outro.ID, seguinte.ID}
+		if len(ordem) != len(esperado) {
+			t.Fatalf("publicados = %v, esperado %v", ordem, esperado)
+		}
+		for i := range esperado {
+			if ordem[i] != esperado[i] {
+				t.Errorf("publicados = %v, esperado %v", ordem, esperado)
+				break
+			}
+		}
+	})
+}
+
+func TestRelayReservaOLoteParaUmaInstancia(t *testing.T) {
+	pool := poolDeTeste(t)
+	emOutboxTemporaria(t, pool, func(ctx context.Context) {
+		evento, _ := NovoEvento("pedido.criado", "pedido", "p1", map[string]string{"id": "p1"})
+		if err := Gravar(ctx, pool.Querier(ctx), evento); err != nil {
+			t.Fatal(err)
+		}
+		relay := NewRelay(pool, NewPublisherEmMemoria())
+
+		// Reservado e ainda não publicado, o evento não é lido de novo.
+		eventos, _, err := relay.reservarLote(ctx)
+		if err != nil || len(eventos) != 1 {
+			t.Fatalf("reservarLote = %d eventos, %v", len(eventos), err)
+		}
+		if eventos, _, err := relay.reservarLote(ctx); err != nil || len(eventos) != 0 {
+			t.Fatalf("segunda reserva = %d eventos, %v; esperado nenhum", len(eventos), err)
+		}
+
+		// Liberado, volta à fila.
+		if err := relay.liberar(ctx, eventos); err != nil {
+			t.Fatal(err)
+		}
+		liberarEspera(t, ctx, pool)
+		if eventos, _, err := relay.reservarLote(ctx); err != nil || len(eventos) != 1 {
+			t.Fatalf("reserva depois de liberar = %d eventos, %v; esperado 1", len(eventos), err)
+		}
+	})
+}
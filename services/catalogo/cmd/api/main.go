@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"ecommerce/catalogo/internal/application"
+	httphandler "ecommerce/catalogo/internal/infra/http"
+	"ecommerce/catalogo/internal/infra/repository"
+	"ecommerce/catalogo/migrations"
+	"ecommerce/pkg/common/problema"
+	"ecommerce/pkg/db"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	_ "ecommerce/catalogo/docs" // Importa os docs gerados pelo swag (necessário)
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/joho/godotenv"
+	httpSwagger "github.com/swaggo/http-swagger"
+)
+
+// @title API de Catálogo do E-commerce
+// @version 1.0
+// @description Microsserviço responsável pelos produtos, SKUs e preços vendidos na loja.
+// @BasePath /produtos
+func main() {
+	err := godotenv.Load()
+	if err != nil {
+		log.Println("Aviso: Erro ao carregar arquivo .env")
+	}
+
+	pool, err := db.NewPool(context.Background(), db.ComApplicationName("catalogo-service"))
+	if err != nil {
+		log.Fatalf("Não foi possível conectar ao banco de dados: %v", err)
+	}
+	defer pool.Close()
+
+	// Migrações do schema: "migrate up|down|status" executa o comando e encerra;
+	// sem ele, o serviço só sobe com todas as migrações aplicadas.
+	migrador, err := db.NewMigrador(pool.SQLDB(), migrations.FS, "catalogo")
+	if err != nil {
+		log.Fatalf("Migrações inválidas: %v", err)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrador.ExecutarComando(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("Erro ao executar migração: %v", err)
+		}
+		return
+	}
+	if err := migrador.VerificarAtualizado(context.Background()); err != nil {
+		log.Fatalf("Não é possível iniciar: %v", err)
+	}
+
+	repo := repository.NewPostgresProdutoRepository(pool)
+	produtoService := application.NewProdutoService(repo)
+	produtoHandler := httphandler.NewProdutoHandler(produtoService)
+
+	r := chi.NewRouter()
+	r.Use(problema.Rastreamento)
+	r.Use(middleware.Logger)
+	r.Use(middleware.Recoverer)
+
+	r.NotFound(problema.NaoEncontrado)
+	r.MethodNotAllowed(problema.MetodoNaoPermitido)
+
+	r.Post("/produtos", produtoHandler.CriarProdutoHandler)
+	r.Get("/produtos", produtoHandler.ListarProdutosHandler)
+	r.Get("/produtos/{id}", produtoHandler.BuscarProdutoPorIDHandler)
+	r.Put("/produtos/{id}/preco", produtoHandler.AlterarPrecoHandler)
+	r.Post("/produtos/{id}/desativar", produtoHandler.DesativarProdutoHandler)
+
+	r.Get("/swagger/*", httpSwagger.Handler())
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8082"
+	}
+
+	addr := fmt.Sprintf(":%s", port)
+	fmt.Printf("Servidor de Catálogo rodando na porta %s...\n", port)
+	fmt.Printf("Acesse a documentação da API em http://localhost:%s/swagger/index.html\n", port)
+	log.Fatal(http.ListenAndServe(addr, r))
+}
@@ -0,0 +1,403 @@
+// Package docs Code generated by swaggo/swag. DO NOT EDIT
+package docs
+
+import "github.com/swaggo/swag"
+
+const docTemplate = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "contact": {},
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/produtos": {
+            "get": {
+                "description": "Retorna todos os produtos do catálogo, ativos e inativos.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Lista todos os produtos",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ecommerce_catalogo_internal_domain.Produto"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao listar produtos",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Cadastra um produto com SKU único e preço vigente.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Cadastra um novo produto",
+                "parameters": [
+                    {
+                        "description": "Dados para cadastro do produto",
+                        "name": "produto",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_catalogo_internal_application.ProdutoInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_catalogo_internal_domain.Produto"
+                        }
+                    },
+                    "400": {
+                        "description": "Corpo da requisição inválido ou campo desconhecido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "409": {
+                        "description": "Já existe um produto com este SKU",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "413": {
+                        "description": "Corpo da requisição acima de 1 MiB",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "422": {
+                        "description": "Produto inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao criar produto",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}": {
+            "get": {
+                "description": "Retorna um produto com seu SKU e preço vigente.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Busca um produto por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Produto (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_catalogo_internal_domain.Produto"
+                        }
+                    },
+                    "404": {
+                        "description": "Produto não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao buscar produto",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/desativar": {
+            "post": {
+                "description": "Retira o produto de venda. Ele continua consultável, mas não pode mais ser pedido.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Desativa um produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Produto (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_catalogo_internal_domain.Produto"
+                        }
+                    },
+                    "404": {
+                        "description": "Produto não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao desativar produto",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/preco": {
+            "put": {
+                "description": "Define um novo preço vigente. Pedidos já feitos mantêm o preço da compra.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Altera o preço de um produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Produto (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Novo preço",
+                        "name": "preco",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_catalogo_internal_application.PrecoInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_catalogo_internal_domain.Produto"
+                        }
+                    },
+                    "400": {
+                        "description": "Corpo da requisição inválido ou campo desconhecido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "404": {
+                        "description": "Produto não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "413": {
+                        "description": "Corpo da requisição acima de 1 MiB",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "422": {
+                        "description": "Preço inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao alterar preço",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "ecommerce_catalogo_internal_application.PrecoInput": {
+            "type": "object",
+            "properties": {
+                "preco": {
+                    "$ref": "#/definitions/money.Money"
+                }
+            }
+        },
+        "ecommerce_catalogo_internal_application.ProdutoInput": {
+            "type": "object",
+            "properties": {
+                "descricao": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "preco": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "sku": {
+                    "type": "string"
+                }
+            }
+        },
+        "ecommerce_catalogo_internal_domain.Produto": {
+            "type": "object",
+            "properties": {
+                "alteradoEm": {
+                    "type": "string"
+                },
+                "ativo": {
+                    "type": "boolean"
+                },
+                "criadoEm": {
+                    "type": "string"
+                },
+                "descricao": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "preco": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "sku": {
+                    "type": "string"
+                }
+            }
+        },
+        "money.Money": {
+            "type": "object",
+            "properties": {
+                "moeda": {
+                    "description": "Código ISO 4217, ex.: \"BRL\".",
+                    "type": "string"
+                },
+                "valor": {
+                    "description": "Em unidades menores da moeda (centavos para BRL).",
+                    "type": "integer"
+                }
+            }
+        },
+        "problema.ErroCampo": {
+            "type": "object",
+            "properties": {
+                "campo": {
+                    "type": "string"
+                },
+                "mensagem": {
+                    "type": "string"
+                },
+                "regra": {
+                    "description": "Código estável da regra violada, como \"obrigatorio\".",
+                    "type": "string"
+                }
+            }
+        },
+        "problema.Problema": {
+            "type": "object",
+            "properties": {
+                "campos": {
+                    "description": "Erros de validação, um por campo.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/problema.ErroCampo"
+                    }
+                },
+                "codigo": {
+                    "description": "Código estável, como \"pedido_nao_encontrado\".",
+                    "type": "string"
+                },
+                "detail": {
+                    "description": "Explicação desta ocorrência.",
+                    "type": "string"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status HTTP da resposta.",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Resumo fixo do problema, igual para todas as ocorrências.",
+                    "type": "string"
+                },
+                "trace_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "URI que identifica o problema: \"urn:problema:\" + código.",
+                    "type": "string"
+                }
+            }
+        }
+    }
+}`
+
+// SwaggerInfo holds exported Swagger Info so clients can modify it
+var SwaggerInfo = &swag.Spec{
+	Version:          "1.0",
+	Host:             "",
+	BasePath:         "/produtos",
+	Schemes:          []string{},
+	Title:            "API de Catálogo do E-commerce",
+	Description:      "Microsserviço responsável pelos produtos, SKUs e preços vendidos na loja.",
+	InfoInstanceName: "swagger",
+	SwaggerTemplate:  docTemplate,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
+}
+
+func init() {
+	swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)
+}
@@ -0,0 +1,90 @@
+package application
+
+import (
+	"context"
+	"ecommerce/catalogo/internal/domain"
+	"ecommerce/pkg/money"
+)
+
+// ProdutoService é a implementação dos casos de uso do catálogo.
+type ProdutoService struct {
+	repo domain.ProdutoRepository
+}
+
+// NewProdutoService é o construtor do nosso serviço de aplicação.
+func NewProdutoService(repo domain.ProdutoRepository) *ProdutoService {
+	return &ProdutoService{
+		repo: repo,
+	}
+}
+
+// ProdutoInput é o DTO para o cadastro de um novo produto.
+type ProdutoInput struct {
+	SKU       string      `json:"sku"`
+	Nome      string      `json:"nome"`
+	Descricao string      `json:"descricao"`
+	Preco     money.Money `json:"preco"`
+}
+
+// PrecoInput é o DTO para a alteração do preço de um produto.
+type PrecoInput struct {
+	Preco money.Money `json:"preco"`
+}
+
+// CriarProduto é o caso de uso para cadastrar um novo produto no catálogo.
+func (s *ProdutoService) CriarProduto(ctx context.Context, input ProdutoInput) (*domain.Produto, error) {
+	produto, err := domain.NewProduto(input.SKU, input.Nome, input.Descricao, input.Preco)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(ctx, produto); err != nil {
+		return nil, err
+	}
+
+	return produto, nil
+}
+
+// BuscarProdutoPorID é o caso de uso para buscar um produto específico.
+func (s *ProdutoService) BuscarProdutoPorID(ctx context.Context, id string) (*domain.Produto, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// ListarProdutos é o caso de uso para buscar todos os produtos.
+func (s *ProdutoService) ListarProdutos(ctx context.Context) ([]*domain.Produto, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// AlterarPreco é o caso de uso para definir um novo preço vigente.
+func (s *ProdutoService) AlterarPreco(ctx context.Context, id string, input PrecoInput) (*domain.Produto, error) {
+	produto, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := produto.AlterarPreco(input.Preco); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, produto); err != nil {
+		return nil, err
+	}
+
+	return produto, nil
+}
+
+// DesativarProduto é o caso de uso para retirar um produto de venda.
+func (s *ProdutoService) DesativarProduto(ctx context.Context, id string) (*domain.Produto, error) {
+	produto, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	produto.Desativar()
+
+	if err := s.repo.Update(ctx, produto); err != nil {
+		return nil, err
+	}
+
+	return produto, nil
+}
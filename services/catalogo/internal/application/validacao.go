@@ -0,0 +1,21 @@
+package application
+
+import "ecommerce/pkg/common/validacao"
+
+// Validar confere os campos obrigatórios do novo produto. É chamado por
+// validacao.Decodificar ao ler o corpo da requisição; as regras de negócio
+// ficam em domain.NewProduto.
+func (in ProdutoInput) Validar() error {
+	v := validacao.New()
+	v.Obrigatorio("sku", in.SKU)
+	v.Obrigatorio("nome", in.Nome)
+	v.Positivo("preco.valor", in.Preco.Valor)
+	return v.Erro()
+}
+
+// Validar confere o novo preço.
+func (in PrecoInput) Validar() error {
+	v := validacao.New()
+	v.Positivo("preco.valor", in.Preco.Valor)
+	return v.Erro()
+}
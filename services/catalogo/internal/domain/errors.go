@@ -0,0 +1,11 @@
+package domain
+
+import "errors"
+
+// Erros que podem ser retornados pela camada de domínio.
+var (
+	ErrProdutoNaoEncontrado = errors.New("produto não encontrado")
+	ErrProdutoInvalido      = errors.New("produto inválido")
+	ErrPrecoInvalido        = errors.New("preço do produto inválido")
+	ErrSKUDuplicado         = errors.New("já existe um produto com este SKU")
+)
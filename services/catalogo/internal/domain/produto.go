@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"ecommerce/pkg/money"
+	"strings"
+	"time"
+)
+
+// Produto é a raiz do agregado do catálogo.
+// Cada produto é vendido por um SKU único e tem um preço vigente.
+type Produto struct {
+	ID         string
+	SKU        string
+	Nome       string
+	Descricao  string
+	Preco      money.Money
+	Ativo      bool
+	CriadoEm   time.Time
+	AlteradoEm time.Time
+}
+
+// NewProduto é o construtor do agregado. Produtos nascem ativos.
+func NewProduto(sku, nome, descricao string, preco money.Money) (*Produto, error) {
+	sku = strings.ToUpper(strings.TrimSpace(sku))
+	nome = strings.TrimSpace(nome)
+	if sku == "" || nome == "" {
+		return nil, ErrProdutoInvalido
+	}
+	if preco.Valor <= 0 {
+		return nil, ErrPrecoInvalido
+	}
+
+	return &Produto{
+		ID:        "", // O ID será gerado na camada de infraestrutura
+		SKU:       sku,
+		Nome:      nome,
+		Descricao: descricao,
+		Preco:     preco,
+		Ativo:     true,
+	}, nil
+}
+
+// AlterarPreco define um novo preço vigente para o produto.
+func (p *Produto) AlterarPreco(preco money.Money) error {
+	if preco.Valor <= 0 {
+		return ErrPrecoInvalido
+	}
+	p.Preco = preco
+	p.AlteradoEm = time.Now()
+	return nil
+}
+
+// Desativar retira o produto de venda sem apagá-lo, preservando pedidos antigos.
+func (p *Produto) Desativar() {
+	p.Ativo = false
+	p.AlteradoEm = time.Now()
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "context"
+
+// ProdutoRepository define os métodos para persistir e recuperar produtos do catálogo.
+type ProdutoRepository interface {
+	Save(ctx context.Context, produto *Produto) error
+	Update(ctx context.Context, produto *Produto) error
+	FindByID(ctx context.Context, id string) (*Produto, error)
+	FindAll(ctx context.Context) ([]*Produto, error)
+}
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"ecommerce/catalogo/internal/domain"
+	"ecommerce/pkg/common/problema"
+	"net/http"
+)
+
+// problemas associa os erros do domínio do catálogo aos códigos estáveis das
+// respostas problem+json. Os códigos fazem parte do contrato da API: não mude
+// um código existente, crie outro.
+var problemas = problema.NewCatalogo().
+	Registrar(domain.ErrProdutoNaoEncontrado, http.StatusNotFound, "produto_nao_encontrado", "Produto não encontrado").
+	Registrar(domain.ErrSKUDuplicado, http.StatusConflict, "sku_em_uso", "Já existe um produto com este SKU").
+	Registrar(domain.ErrProdutoInvalido, http.StatusUnprocessableEntity, "produto_invalido", "Dados do produto inválidos").
+	Registrar(domain.ErrPrecoInvalido, http.StatusUnprocessableEntity, "preco_invalido", "Preço do produto inválido")
+
+// escreverErro responde err como problem+json. Erros internos vão só para o
+// log, com o trace ID: a resposta não expõe detalhes do banco.
+func escreverErro(w http.ResponseWriter, r *http.Request, err error) {
+	problemas.Escrever(w, r, err)
+}
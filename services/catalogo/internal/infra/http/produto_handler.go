@@ -0,0 +1,148 @@
+package http
+
+import (
+	"ecommerce/catalogo/internal/application"
+	_ "ecommerce/catalogo/internal/domain" // Necessário para o swag resolver os tipos das respostas
+	_ "ecommerce/pkg/common/problema"      // Idem
+	"ecommerce/pkg/common/validacao"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ProdutoHandler lida com as requisições HTTP para o catálogo de produtos.
+type ProdutoHandler struct {
+	service *application.ProdutoService
+}
+
+// NewProdutoHandler é o construtor do nosso handler.
+func NewProdutoHandler(service *application.ProdutoService) *ProdutoHandler {
+	return &ProdutoHandler{
+		service: service,
+	}
+}
+
+// @Summary Cadastra um novo produto
+// @Description Cadastra um produto com SKU único e preço vigente.
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Param produto body application.ProdutoInput true "Dados para cadastro do produto"
+// @Success 201 {object} domain.Produto
+// @Failure 400 {object} problema.Problema "Corpo da requisição inválido ou campo desconhecido"
+// @Failure 409 {object} problema.Problema "Já existe um produto com este SKU"
+// @Failure 413 {object} problema.Problema "Corpo da requisição acima de 1 MiB"
+// @Failure 422 {object} problema.Problema "Produto inválido ou campos que não passaram na validação"
+// @Failure 500 {object} problema.Problema "Erro interno ao criar produto"
+// @Router /produtos [post]
+func (h *ProdutoHandler) CriarProdutoHandler(w http.ResponseWriter, r *http.Request) {
+	var input application.ProdutoInput
+	if err := validacao.Decodificar(w, r, &input); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	produto, err := h.service.CriarProduto(r.Context(), input)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated) // Status 201 Created
+	json.NewEncoder(w).Encode(produto)
+}
+
+// @Summary Lista todos os produtos
+// @Description Retorna todos os produtos do catálogo, ativos e inativos.
+// @Tags produtos
+// @Produce json
+// @Success 200 {array} domain.Produto
+// @Failure 500 {object} problema.Problema "Erro interno ao listar produtos"
+// @Router /produtos [get]
+func (h *ProdutoHandler) ListarProdutosHandler(w http.ResponseWriter, r *http.Request) {
+	produtos, err := h.service.ListarProdutos(r.Context())
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(produtos)
+}
+
+// @Summary Busca um produto por ID
+// @Description Retorna um produto com seu SKU e preço vigente.
+// @Tags produtos
+// @Produce json
+// @Param id path string true "ID do Produto (UUID)"
+// @Success 200 {object} domain.Produto
+// @Failure 404 {object} problema.Problema "Produto não encontrado"
+// @Failure 500 {object} problema.Problema "Erro interno ao buscar produto"
+// @Router /produtos/{id} [get]
+func (h *ProdutoHandler) BuscarProdutoPorIDHandler(w http.ResponseWriter, r *http.Request) {
+	produto, err := h.service.BuscarProdutoPorID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(produto)
+}
+
+// @Summary Altera o preço de um produto
+// @Description Define um novo preço vigente. Pedidos já feitos mantêm o preço da compra.
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do Produto (UUID)"
+// @Param preco body application.PrecoInput true "Novo preço"
+// @Success 200 {object} domain.Produto
+// @Failure 400 {object} problema.Problema "Corpo da requisição inválido ou campo desconhecido"
+// @Failure 404 {object} problema.Problema "Produto não encontrado"
+// @Failure 413 {object} problema.Problema "Corpo da requisição acima de 1 MiB"
+// @Failure 422 {object} problema.Problema "Preço inválido ou campos que não passaram na validação"
+// @Failure 500 {object} problema.Problema "Erro interno ao alterar preço"
+// @Router /produtos/{id}/preco [put]
+func (h *ProdutoHandler) AlterarPrecoHandler(w http.ResponseWriter, r *http.Request) {
+	var input application.PrecoInput
+	if err := validacao.Decodificar(w, r, &input); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	produto, err := h.service.AlterarPreco(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(produto)
+}
+
+// @Summary Desativa um produto
+// @Description Retira o produto de venda. Ele continua consultável, mas não pode mais ser pedido.
+// @Tags produtos
+// @Produce json
+// @Param id path string true "ID do Produto (UUID)"
+// @Success 200 {object} domain.Produto
+// @Failure 404 {object} problema.Problema "Produto não encontrado"
+// @Failure 500 {object} problema.Problema "Erro interno ao desativar produto"
+// @Router /produtos/{id}/desativar [post]
+func (h *ProdutoHandler) DesativarProdutoHandler(w http.ResponseWriter, r *http.Request) {
+	produto, err := h.service.DesativarProduto(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(produto)
+}
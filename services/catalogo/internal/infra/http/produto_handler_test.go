@@ -0,0 +1,70 @@
+package http
+
+import (
+	"context"
+	"ecommerce/catalogo/internal/application"
+	"ecommerce/catalogo/internal/domain"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// repoDeProdutos guarda os produtos salvos e recusa SKUs repetidos, como o
+// índice único do Postgres. Os demais métodos não são usados aqui.
+type repoDeProdutos struct {
+	domain.ProdutoRepository
+	skus map[string]bool
+}
+
+func (r *repoDeProdutos) Save(_ context.Context, produto *domain.Produto) error {
+	if r.skus[produto.SKU] {
+		return domain.ErrSKUDuplicado
+	}
+	r.skus[produto.SKU] = true
+	produto.ID = "produto-" + produto.SKU
+	return nil
+}
+
+func TestCriarProdutoHandler(t *testing.T) {
+	casos := []struct {
+		nome   string
+		corpo  string
+		status int
+		codigo string
+	}{
+		{"cadastra", `{"sku":"CAN-01","nome":"Caneca","preco":{"valor":2990,"moeda":"BRL"}}`, http.StatusCreated, ""},
+		{"SKU repetido", `{"sku":"CAN-00","nome":"Caneca","preco":{"valor":2990,"moeda":"BRL"}}`, http.StatusConflict, "sku_em_uso"},
+		{"campo desconhecido", `{"sku":"CAN-02","nome":"Caneca","preco":2990,"estoque":3}`, http.StatusBadRequest, "corpo_invalido"},
+		{"JSON depois do objeto", `{"sku":"CAN-02","nome":"Caneca","preco":29.90}{}`, http.StatusBadRequest, "corpo_invalido"},
+		{"moeda desconhecida", `{"sku":"CAN-02","nome":"Caneca","preco":{"valor":2990,"moeda":"XYZ"}}`, http.StatusBadRequest, "corpo_invalido"},
+		{"campos obrigatórios", `{"descricao":"sem nome"}`, http.StatusUnprocessableEntity, "validacao_falhou"},
+		{"corpo acima de 1 MiB", `{"sku":"` + strings.Repeat("a", 1<<20) + `"}`, http.StatusRequestEntityTooLarge, "corpo_grande_demais"},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			repo := &repoDeProdutos{skus: map[string]bool{"CAN-00": true}}
+			h := NewProdutoHandler(application.NewProdutoService(repo))
+
+			w := httptest.NewRecorder()
+			h.CriarProdutoHandler(w, httptest.NewRequest(http.MethodPost, "/produtos", strings.NewReader(c.corpo)))
+
+			if w.Code != c.status {
+				t.Fatalf("status = %d, esperado %d: %s", w.Code, c.status, w.Body)
+			}
+			if c.codigo == "" {
+				return
+			}
+			var p struct {
+				Codigo string `json:"codigo"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&p); err != nil || p.Codigo != c.codigo {
+				t.Errorf("código = %q (%v), esperado %q", p.Codigo, err, c.codigo)
+			}
+			if tipo := w.Header().Get("Content-Type"); tipo != "application/problem+json" {
+				t.Errorf("Content-Type = %q, esperado application/problem+json", tipo)
+			}
+		})
+	}
+}
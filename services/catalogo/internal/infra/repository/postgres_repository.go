@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"ecommerce/catalogo/internal/domain"
+	"ecommerce/pkg/db"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// codigoViolacaoUnica é o SQLSTATE do Postgres para unique_violation.
+const codigoViolacaoUnica = "23505"
+
+type postgresProdutoRepository struct {
+	db *db.Pool
+}
+
+// NewPostgresProdutoRepository é o construtor do nosso repositório.
+func NewPostgresProdutoRepository(pool *db.Pool) domain.ProdutoRepository {
+	return &postgresProdutoRepository{db: pool}
+}
+
+// Save cadastra um novo produto.
+func (r *postgresProdutoRepository) Save(ctx context.Context, produto *domain.Produto) error {
+	produto.ID = uuid.NewString()
+	now := time.Now()
+	produto.CriadoEm = now
+	produto.AlteradoEm = now
+
+	query := `INSERT INTO produtos (id, sku, nome, descricao, preco, moeda, ativo, criado_em, alterado_em)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := r.db.Querier(ctx).Exec(ctx, query,
+		produto.ID, produto.SKU, produto.Nome, produto.Descricao, produto.Preco.Valor, produto.Preco.Moeda,
+		produto.Ativo, produto.CriadoEm, produto.AlteradoEm,
+	)
+	return traduzirErro(err)
+}
+
+// Update persiste preço, status e dados descritivos de um produto existente.
+func (r *postgresProdutoRepository) Update(ctx context.Context, produto *domain.Produto) error {
+	query := `UPDATE produtos
+			  SET nome = $2, descricao = $3, preco = $4, moeda = $5, ativo = $6, alterado_em = $7
+			  WHERE id = $1`
+	tag, err := r.db.Querier(ctx).Exec(ctx, query,
+		produto.ID, produto.Nome, produto.Descricao, produto.Preco.Valor, produto.Preco.Moeda, produto.Ativo, produto.AlteradoEm,
+	)
+	if err != nil {
+		return traduzirErro(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrProdutoNaoEncontrado
+	}
+
+	return nil
+}
+
+// FindByID busca um produto pelo ID. Um ID fora do formato UUID não existe:
+// ele nem chega ao banco, que o recusaria com erro de sintaxe (22P02).
+func (r *postgresProdutoRepository) FindByID(ctx context.Context, id string) (*domain.Produto, error) {
+	if uuid.Validate(id) != nil {
+		return nil, domain.ErrProdutoNaoEncontrado
+	}
+
+	const query = `
+		SELECT id, sku, nome, descricao, preco, moeda, ativo, criado_em, alterado_em
+		FROM produtos
+		WHERE id = $1`
+
+	var p domain.Produto
+	err := r.db.Querier(ctx).QueryRow(ctx, query, id).Scan(
+		&p.ID, &p.SKU, &p.Nome, &p.Descricao, &p.Preco.Valor, &p.Preco.Moeda, &p.Ativo, &p.CriadoEm, &p.AlteradoEm,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrProdutoNaoEncontrado
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// FindAll busca todos os produtos do catálogo, ordenados por nome.
+func (r *postgresProdutoRepository) FindAll(ctx context.Context) ([]*domain.Produto, error) {
+	const query = `
+		SELECT id, sku, nome, descricao, preco, moeda, ativo, criado_em, alterado_em
+		FROM produtos
+		ORDER BY nome, id`
+
+	rows, err := r.db.Querier(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	produtos := []*domain.Produto{}
+	for rows.Next() {
+		var p domain.Produto
+		if err := rows.Scan(
+			&p.ID, &p.SKU, &p.Nome, &p.Descricao, &p.Preco.Valor, &p.Preco.Moeda, &p.Ativo, &p.CriadoEm, &p.AlteradoEm,
+		); err != nil {
+			return nil, err
+		}
+		produtos = append(produtos, &p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return produtos, nil
+}
+
+// traduzirErro converte erros conhecidos do Postgres em erros do domínio.
+func traduzirErro(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == codigoViolacaoUnica {
+		return domain.ErrSKUDuplicado
+	}
+	return err
+}
@@ -0,0 +1,11 @@
+CREATE TABLE produtos (
+    id          UUID PRIMARY KEY,
+    sku         TEXT NOT NULL UNIQUE,
+    nome        TEXT NOT NULL,
+    descricao   TEXT NOT NULL DEFAULT '',
+    preco       BIGINT NOT NULL CHECK (preco > 0),
+    moeda       CHAR(3) NOT NULL,
+    ativo       BOOLEAN NOT NULL DEFAULT true,
+    criado_em   TIMESTAMPTZ NOT NULL,
+    alterado_em TIMESTAMPTZ NOT NULL
+);
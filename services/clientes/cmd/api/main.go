@@ -1,14 +1,23 @@
 package main
 
 import (
+	"context"
 	"ecommerce/clientes/internal/application"
+	"ecommerce/clientes/internal/infra/cep"
 	httphandler "ecommerce/clientes/internal/infra/http"
+	"ecommerce/clientes/internal/infra/pedidos"
 	"ecommerce/clientes/internal/infra/repository"
+	"ecommerce/clientes/migrations"
+	"ecommerce/pkg/common/assinatura"
+	"ecommerce/pkg/common/problema"
 	"ecommerce/pkg/db"
+	"ecommerce/pkg/idempotencia"
+	"ecommerce/pkg/outbox"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	_ "ecommerce/clientes/docs" // <-- IMPORT DOS DOCS GERADOS
 
@@ -29,22 +38,91 @@ func main() {
 		log.Println("Aviso: Erro ao carregar arquivo .env")
 	}
 
-	dbConn, err := db.NewConnection()
+	pool, err := db.NewPool(context.Background(), db.ComApplicationName("clientes-service"))
 	if err != nil {
 		log.Fatalf("Não foi possível conectar ao banco de dados: %v", err)
 	}
-	defer dbConn.Close()
+	defer pool.Close()
 
-	repo := repository.NewPostgresClienteRepository(dbConn)
-	clienteService := application.NewClienteService(repo)
+	// Migrações do schema: "migrate up|down|status" executa o comando e encerra;
+	// sem ele, o serviço só sobe com todas as migrações aplicadas.
+	migrador, err := db.NewMigrador(pool.SQLDB(), migrations.FS, "clientes")
+	if err != nil {
+		log.Fatalf("Migrações inválidas: %v", err)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrador.ExecutarComando(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("Erro ao executar migração: %v", err)
+		}
+		return
+	}
+	if err := migrador.VerificarAtualizado(context.Background()); err != nil {
+		log.Fatalf("Não é possível iniciar: %v", err)
+	}
+	if err := repository.VerificarIndicesUnicos(context.Background(), pool); err != nil {
+		log.Fatalf("Não é possível iniciar: %v", err)
+	}
+
+	// O histórico de pedidos do cliente é consultado no serviço de pedidos, que também
+	// recebe os eventos de clientes.
+	pedidosURL, ok := os.LookupEnv("PEDIDOS_URL")
+	if !ok {
+		log.Fatalf("A variável de ambiente PEDIDOS_URL não foi definida")
+	}
+	// Os eventos enviados ao serviço de pedidos são assinados com este segredo, o mesmo nos dois serviços.
+	segredoEventos, err := assinatura.NewSegredo(os.Getenv("EVENTOS_SEGREDO"))
+	if err != nil {
+		log.Fatalf("EVENTOS_SEGREDO inválida: %v", err)
+	}
+
+	// Cidade e UF dos endereços são completadas ou conferidas pela base de CEPs embutida.
+	faixasDeCEP, err := cep.NewFaixasDeCEP()
+	if err != nil {
+		log.Fatalf("Não é possível iniciar: %v", err)
+	}
+
+	repo := repository.NewPostgresClienteRepository(pool)
+	pedidoGateway := pedidos.NewHTTPPedidoGateway(pedidosURL)
+	clienteService := application.NewClienteService(repo, pedidoGateway, faixasDeCEP)
 	clienteHandler := httphandler.NewClienteHandler(clienteService)
 
+	// Os eventos gravados na outbox são publicados no canal "clientes_eventos" do Postgres
+	// (LISTEN clientes_eventos para recebê-los) e enviados ao serviço de pedidos, que aplica
+	// cliente.mesclado; enquanto ele estiver fora do ar, os eventos esperam na outbox.
+	relay := outbox.NewRelay(pool, outbox.Multiplos(
+		outbox.NewPostgresPublisher(pool, "clientes_eventos"),
+		pedidos.NewHTTPEventosPublisher(pedidosURL, segredoEventos),
+	))
+	go relay.Executar(context.Background())
+
+	// Respostas de criação ficam guardadas por 24 horas para repetições com a mesma Idempotency-Key.
+	chavesIdempotencia := idempotencia.NewPostgresArmazenamento(pool)
+	go chavesIdempotencia.IniciarLimpeza(context.Background(), time.Hour)
+	idempotente := idempotencia.New(chavesIdempotencia, 24*time.Hour)
+
 	r := chi.NewRouter()
-	r.Use(middleware.Logger)
+	r.Use(problema.Rastreamento)
+	// O log de requisições mascara o CPF ou CNPJ usado na busca de clientes.
+	r.Use(httphandler.Logger)
 	r.Use(middleware.Recoverer)
+	// Erros, inclusive de rotas inexistentes, são respondidos como application/problem+json.
+	r.NotFound(problema.NaoEncontrado)
+	r.MethodNotAllowed(problema.MetodoNaoPermitido)
 
-	r.Post("/clientes", clienteHandler.CriarClienteHandler)
+	r.With(idempotente.Middleware).Post("/clientes", clienteHandler.CriarClienteHandler)
 	r.Get("/clientes", clienteHandler.ListarClientesHandler)
+	r.Get("/clientes/duplicados", clienteHandler.BuscarDuplicadosHandler)
+	r.Get("/clientes/{id}", clienteHandler.BuscarClientePorIDHandler)
+	r.Put("/clientes/{id}", clienteHandler.AtualizarClienteHandler)
+	r.Patch("/clientes/{id}", clienteHandler.PatchClienteHandler)
+	r.Delete("/clientes/{id}", clienteHandler.ExcluirClienteHandler)
+	r.Post("/clientes/{id}/mesclar", clienteHandler.MesclarClientesHandler)
+	r.Get("/clientes/{id}/enderecos", clienteHandler.ListarEnderecosHandler)
+	r.Post("/clientes/{id}/enderecos", clienteHandler.AdicionarEnderecoHandler)
+	r.Get("/clientes/{id}/enderecos/{enderecoId}", clienteHandler.BuscarEnderecoHandler)
+	r.Put("/clientes/{id}/enderecos/{enderecoId}", clienteHandler.AtualizarEnderecoHandler)
+	r.Delete("/clientes/{id}/enderecos/{enderecoId}", clienteHandler.RemoverEnderecoHandler)
+	r.Get("/clientes/{id}/pedidos", clienteHandler.ListarPedidosDoClienteHandler)
 
 	// --- ROTA DO SWAGGER ADICIONADA ---
 	r.Get("/swagger/*", httpSwagger.Handler())
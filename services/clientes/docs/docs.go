@@ -17,34 +17,759 @@ const docTemplate = `{
     "paths": {
         "/clientes": {
             "get": {
-                "description": "Retorna uma lista de todos os clientes cadastrados com seus endereços.",
+                "description": "Retorna uma página de clientes com seus endereços, com busca e ordenação.\nA página vem do cursor (next_cursor da resposta anterior, null na última página) ou de page/size.\nOs CPFs e CNPJs vêm mascarados; o documento completo só aparece na consulta do cliente por ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "clientes"
                 ],
-                "summary": "Lista todos os clientes",
+                "summary": "Lista clientes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Início do nome, sem diferenciar maiúsculas",
+                        "name": "nome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "E-mail exato, sem diferenciar maiúsculas",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CEP de algum endereço do cliente",
+                        "name": "cep",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cidade de algum endereço do cliente",
+                        "name": "cidade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CPF ou CNPJ exato, com ou sem pontuação",
+                        "name": "documento",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "nome",
+                            "-nome",
+                            "criado_em",
+                            "-criado_em"
+                        ],
+                        "type": "string",
+                        "description": "Ordenação (padrão -criado_em)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor da página anterior",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número da página, a partir de 1 (alternativa ao cursor)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tamanho da página (1 a 100, padrão 20)",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclui o total de clientes encontrados",
+                        "name": "total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_clientes_internal_application.PaginaClientesOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Busca, ordenação ou página inválida",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao listar clientes",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Cria um novo cliente com seus dados e endereços.\nCidade e estado (sigla da UF) dos endereços podem ser omitidos para serem preenchidos a partir do CEP.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Cria um novo cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chave para repetir a requisição sem criar outro cliente",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Dados para criação do cliente",
+                        "name": "cliente",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_clientes_internal_application.ClienteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_clientes_internal_domain.Cliente"
+                        }
+                    },
+                    "400": {
+                        "description": "Corpo da requisição inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "409": {
+                        "description": "E-mail ou CPF/CNPJ já em uso por outro cliente, ou Idempotency-Key ainda em andamento",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados do cliente ou do endereço inválidos, ou Idempotency-Key já usada com outro corpo",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao criar cliente",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/clientes/duplicados": {
+            "get": {
+                "description": "Aponta grupos de clientes ativos que provavelmente são a mesma pessoa: mesmo e-mail sem diferenciar\nmaiúsculas nem \"+etiqueta\" (criterio \"email\"), ou nomes parecidos com endereço no mesmo CEP\n(criterio \"nome_cep\"). Nada é alterado; para unir um grupo, use POST /clientes/{id}/mesclar.\nAté 100 grupos por critério e 50 clientes por grupo, os mais antigos; depois das mesclagens, os\nseguintes aparecem. Documento repetido não é critério: o cadastro já impede dois clientes ativos com\no mesmo CPF ou CNPJ. Os CPFs e CNPJs vêm mascarados.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Busca clientes duplicados",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_clientes_internal_application.DuplicadosOutput"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao buscar duplicados",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/clientes/{id}": {
+            "get": {
+                "description": "Retorna os dados de um cliente específico com seus endereços.\nA resposta traz a ETag da versão do cliente; com If-None-Match igual a ela, a resposta é 304 sem corpo.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Busca um cliente por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Cliente (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de uma resposta anterior",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_clientes_internal_domain.Cliente"
+                        }
+                    },
+                    "304": {
+                        "description": "Cliente não mudou desde a ETag informada"
+                    },
+                    "404": {
+                        "description": "Cliente não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao buscar cliente",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Substitui nome, e-mail e documento (CPF ou CNPJ) do cliente; sem documento no corpo, o atual é removido.\nEndereços não são alterados por esta rota.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Atualiza um cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Cliente (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag da versão do cliente lida pelo chamador",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Novos dados do cliente",
+                        "name": "cliente",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_clientes_internal_application.AtualizarClienteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_clientes_internal_domain.Cliente"
+                        }
+                    },
+                    "400": {
+                        "description": "Corpo da requisição ou If-Match inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "404": {
+                        "description": "Cliente não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "409": {
+                        "description": "E-mail ou CPF/CNPJ já em uso por outro cliente, ou cliente alterado por outra requisição",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "412": {
+                        "description": "O cliente não está mais na versão do If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados do cliente inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao atualizar cliente",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Faz a exclusão lógica do cliente; ele deixa de ser listado e consultado.",
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Exclui um cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Cliente (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag da versão do cliente lida pelo chamador",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "If-Match inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "404": {
+                        "description": "Cliente não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "412": {
+                        "description": "O cliente não está mais na versão do If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao excluir cliente",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Aplica um JSON Merge Patch (RFC 7396) sobre nome, e-mail e documento do cliente.\n\"documento\": null remove o CPF ou CNPJ.",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Altera parcialmente um cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Cliente (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag da versão do cliente lida pelo chamador",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Campos a alterar",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_clientes_internal_application.AtualizarClienteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_clientes_internal_domain.Cliente"
+                        }
+                    },
+                    "400": {
+                        "description": "JSON Merge Patch ou If-Match inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "404": {
+                        "description": "Cliente não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "409": {
+                        "description": "E-mail ou CPF/CNPJ já em uso por outro cliente, ou cliente alterado por outra requisição",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "412": {
+                        "description": "O cliente não está mais na versão do If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type não suportado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados do cliente inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao atualizar cliente",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/clientes/{id}/enderecos": {
+            "get": {
+                "description": "Retorna todos os endereços do cliente, com tipo e indicação de padrão.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "enderecos"
+                ],
+                "summary": "Lista os endereços de um cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Cliente (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ecommerce_clientes_internal_domain.Endereco"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Cliente não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao listar endereços",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Inclui um endereço de entrega ou cobrança. Marcá-lo como padrão desmarca o padrão anterior do mesmo tipo.\nEstado é a sigla da UF. Cidade e estado omitidos são preenchidos a partir do CEP; informados, são conferidos com ele.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "enderecos"
+                ],
+                "summary": "Adiciona um endereço a um cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Cliente (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados do endereço",
+                        "name": "endereco",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_clientes_internal_application.EnderecoInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_clientes_internal_domain.Endereco"
+                        }
+                    },
+                    "400": {
+                        "description": "Corpo da requisição inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "404": {
+                        "description": "Cliente não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "409": {
+                        "description": "Cliente alterado por outra requisição; tente novamente",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados do endereço inválidos, CEP não encontrado ou cidade/estado diferentes dos do CEP",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao adicionar endereço",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/clientes/{id}/enderecos/{enderecoId}": {
+            "get": {
+                "description": "Retorna um endereço específico do cliente.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "enderecos"
+                ],
+                "summary": "Busca um endereço de um cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Cliente (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID do Endereço",
+                        "name": "enderecoId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_clientes_internal_domain.Endereco"
+                        }
+                    },
+                    "400": {
+                        "description": "ID do endereço inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "404": {
+                        "description": "Cliente ou endereço não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao buscar endereço",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Substitui os dados do endereço. Marcá-lo como padrão desmarca o padrão anterior do mesmo tipo.\nEstado é a sigla da UF. Cidade e estado omitidos são preenchidos a partir do CEP; informados, são conferidos com ele.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "enderecos"
+                ],
+                "summary": "Altera um endereço de um cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Cliente (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID do Endereço",
+                        "name": "enderecoId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Novos dados do endereço",
+                        "name": "endereco",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_clientes_internal_application.EnderecoInput"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/ecommerce_clientes_internal_domain.Cliente"
-                            }
+                            "$ref": "#/definitions/ecommerce_clientes_internal_domain.Endereco"
+                        }
+                    },
+                    "400": {
+                        "description": "Corpo da requisição inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "404": {
+                        "description": "Cliente ou endereço não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "409": {
+                        "description": "Cliente alterado por outra requisição; tente novamente",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados do endereço inválidos, CEP não encontrado ou cidade/estado diferentes dos do CEP",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
                         }
                     },
                     "500": {
-                        "description": "Erro interno ao listar clientes",
+                        "description": "Erro interno ao alterar endereço",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problema.Problema"
                         }
                     }
                 }
             },
+            "delete": {
+                "description": "Remove o endereço. Pedidos já feitos mantêm a cópia do endereço de entrega.",
+                "tags": [
+                    "enderecos"
+                ],
+                "summary": "Remove um endereço de um cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Cliente (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID do Endereço",
+                        "name": "enderecoId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "ID do endereço inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "404": {
+                        "description": "Cliente ou endereço não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "409": {
+                        "description": "Cliente alterado por outra requisição; tente novamente",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao remover endereço",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/clientes/{id}/mesclar": {
             "post": {
-                "description": "Cria um novo cliente com seus dados e endereços.",
+                "description": "Une ao cliente da URL outro cadastro da mesma pessoa (origem_id). A origem é excluída e seus endereços\npassam para o cliente da URL, sem substituir os endereços padrão dele; o CPF/CNPJ da origem é herdado\nse o cliente não tiver um. Os pedidos da origem são transferidos pelo serviço de pedidos a partir do\nevento cliente.mesclado.",
                 "consumes": [
                     "application/json"
                 ],
@@ -54,35 +779,149 @@ const docTemplate = `{
                 "tags": [
                     "clientes"
                 ],
-                "summary": "Cria um novo cliente",
+                "summary": "Mescla dois clientes",
                 "parameters": [
                     {
-                        "description": "Dados para criação do cliente",
-                        "name": "cliente",
+                        "type": "string",
+                        "description": "ID do cliente que permanece (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag da versão do cliente que permanece",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Cliente absorvido",
+                        "name": "mesclagem",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/ecommerce_clientes_internal_application.ClienteInput"
+                            "$ref": "#/definitions/ecommerce_clientes_internal_application.MesclarClientesInput"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/ecommerce_clientes_internal_domain.Cliente"
                         }
                     },
                     "400": {
-                        "description": "Corpo da requisição inválido",
+                        "description": "Corpo da requisição ou If-Match inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "404": {
+                        "description": "Cliente não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "409": {
+                        "description": "Um dos clientes foi alterado por outra requisição",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "412": {
+                        "description": "O cliente não está mais na versão do If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "422": {
+                        "description": "origem_id inválido ou igual ao cliente da URL",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problema.Problema"
                         }
                     },
                     "500": {
-                        "description": "Erro interno ao criar cliente",
+                        "description": "Erro interno ao mesclar clientes",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/clientes/{id}/pedidos": {
+            "get": {
+                "description": "Retorna o histórico de pedidos do cliente, do mais novo para o mais antigo, consultando o serviço de pedidos.\nPara a próxima página, repita a chamada com cursor igual ao next_cursor recebido (null na última página).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Lista os pedidos de um cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Cliente (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "aguardando_pagamento",
+                            "pago",
+                            "enviado",
+                            "cancelado"
+                        ],
+                        "type": "string",
+                        "description": "Somente pedidos neste status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tamanho da página (1 a 100, padrão 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor da página anterior",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_clientes_internal_application.PedidosDoClienteOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Filtro, limite ou cursor inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "404": {
+                        "description": "Cliente não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao listar pedidos",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "503": {
+                        "description": "Serviço de pedidos indisponível",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problema.Problema"
                         }
                     }
                 }
@@ -90,9 +929,26 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "ecommerce_clientes_internal_application.AtualizarClienteInput": {
+            "type": "object",
+            "properties": {
+                "documento": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                }
+            }
+        },
         "ecommerce_clientes_internal_application.ClienteInput": {
             "type": "object",
             "properties": {
+                "documento": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
@@ -107,6 +963,17 @@ const docTemplate = `{
                 }
             }
         },
+        "ecommerce_clientes_internal_application.DuplicadosOutput": {
+            "type": "object",
+            "properties": {
+                "grupos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ecommerce_clientes_internal_application.GrupoDuplicadoOutput"
+                    }
+                }
+            }
+        },
         "ecommerce_clientes_internal_application.EnderecoInput": {
             "type": "object",
             "properties": {
@@ -119,8 +986,73 @@ const docTemplate = `{
                 "estado": {
                     "type": "string"
                 },
+                "padrao": {
+                    "type": "boolean"
+                },
                 "rua": {
                     "type": "string"
+                },
+                "tipo": {
+                    "type": "string"
+                }
+            }
+        },
+        "ecommerce_clientes_internal_application.GrupoDuplicadoOutput": {
+            "type": "object",
+            "properties": {
+                "clientes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ecommerce_clientes_internal_domain.Cliente"
+                    }
+                },
+                "criterio": {
+                    "type": "string"
+                }
+            }
+        },
+        "ecommerce_clientes_internal_application.MesclarClientesInput": {
+            "type": "object",
+            "properties": {
+                "origem_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "ecommerce_clientes_internal_application.PaginaClientesOutput": {
+            "type": "object",
+            "properties": {
+                "clientes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ecommerce_clientes_internal_domain.Cliente"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ecommerce_clientes_internal_application.PedidosDoClienteOutput": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "pedidos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ecommerce_clientes_internal_domain.PedidoDoCliente"
+                    }
                 }
             }
         },
@@ -133,6 +1065,10 @@ const docTemplate = `{
                 "criadoEm": {
                     "type": "string"
                 },
+                "documento": {
+                    "description": "CPF ou CNPJ; vazio se não informado.",
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
@@ -147,6 +1083,10 @@ const docTemplate = `{
                 },
                 "nome": {
                     "type": "string"
+                },
+                "versao": {
+                    "description": "Versao começa em 1 e é incrementada pelo repositório a cada alteração do\ncliente ou dos seus endereços; é a base do controle de concorrência otimista.",
+                    "type": "integer"
                 }
             }
         },
@@ -166,8 +1106,134 @@ const docTemplate = `{
                     "type": "integer",
                     "format": "int64"
                 },
+                "padrao": {
+                    "type": "boolean"
+                },
                 "rua": {
                     "type": "string"
+                },
+                "tipo": {
+                    "$ref": "#/definitions/ecommerce_clientes_internal_domain.TipoEndereco"
+                }
+            }
+        },
+        "ecommerce_clientes_internal_domain.ItemPedido": {
+            "type": "object",
+            "properties": {
+                "nome": {
+                    "type": "string"
+                },
+                "preco": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "produtoID": {
+                    "type": "string"
+                },
+                "quantidade": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ecommerce_clientes_internal_domain.PedidoDoCliente": {
+            "type": "object",
+            "properties": {
+                "atualizadoEm": {
+                    "type": "string"
+                },
+                "criadoEm": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ecommerce_clientes_internal_domain.ItemPedido"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/money.Money"
+                }
+            }
+        },
+        "ecommerce_clientes_internal_domain.TipoEndereco": {
+            "type": "string",
+            "enum": [
+                "entrega",
+                "cobranca"
+            ],
+            "x-enum-varnames": [
+                "TipoEntrega",
+                "TipoCobranca"
+            ]
+        },
+        "money.Money": {
+            "type": "object",
+            "properties": {
+                "moeda": {
+                    "description": "Código ISO 4217, ex.: \"BRL\".",
+                    "type": "string"
+                },
+                "valor": {
+                    "description": "Em unidades menores da moeda (centavos para BRL).",
+                    "type": "integer"
+                }
+            }
+        },
+        "problema.ErroCampo": {
+            "type": "object",
+            "properties": {
+                "campo": {
+                    "type": "string"
+                },
+                "mensagem": {
+                    "type": "string"
+                },
+                "regra": {
+                    "description": "Código estável da regra violada, como \"obrigatorio\".",
+                    "type": "string"
+                }
+            }
+        },
+        "problema.Problema": {
+            "type": "object",
+            "properties": {
+                "campos": {
+                    "description": "Erros de validação, um por campo.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/problema.ErroCampo"
+                    }
+                },
+                "codigo": {
+                    "description": "Código estável, como \"pedido_nao_encontrado\".",
+                    "type": "string"
+                },
+                "detail": {
+                    "description": "Explicação desta ocorrência.",
+                    "type": "string"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status HTTP da resposta.",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Resumo fixo do problema, igual para todas as ocorrências.",
+                    "type": "string"
+                },
+                "trace_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "URI que identifica o problema: \"urn:problema:\" + código.",
+                    "type": "string"
                 }
             }
         }
@@ -3,56 +3,98 @@ package application
 import (
 	"context"
 	"ecommerce/clientes/internal/domain"
+	"ecommerce/pkg/common/mergepatch"
+	"ecommerce/pkg/common/validacao"
+	"encoding/json"
+	"fmt"
+	"strings"
 )
 
 // ClienteService é a implementação dos nossos casos de uso de cliente.
 type ClienteService struct {
-	repo domain.ClienteRepository
+	repo    domain.ClienteRepository
+	pedidos domain.PedidoGateway
+	ceps    domain.ConsultaCEP
 }
 
 // NewClienteService é o construtor do nosso serviço de aplicação.
-func NewClienteService(repo domain.ClienteRepository) *ClienteService {
+func NewClienteService(repo domain.ClienteRepository, pedidos domain.PedidoGateway, ceps domain.ConsultaCEP) *ClienteService {
 	return &ClienteService{
-		repo: repo,
+		repo:    repo,
+		pedidos: pedidos,
+		ceps:    ceps,
 	}
 }
 
 // EnderecoInput é um DTO para os dados de endereço vindos da requisição.
+// Tipo é "entrega" (padrão) ou "cobranca". Estado é a sigla da UF; cidade e
+// estado podem ser omitidos para serem preenchidos a partir do CEP.
 type EnderecoInput struct {
+	Tipo   string `json:"tipo"`
 	Rua    string `json:"rua"`
 	Cidade string `json:"cidade"`
 	Estado string `json:"estado"`
 	CEP    string `json:"cep"`
+	Padrao bool   `json:"padrao"`
+}
+
+// paraDominio converte o DTO no endereço do domínio.
+func (e EnderecoInput) paraDominio() domain.Endereco {
+	return domain.Endereco{
+		Tipo:   domain.TipoEndereco(e.Tipo),
+		Rua:    e.Rua,
+		Cidade: e.Cidade,
+		Estado: e.Estado,
+		CEP:    e.CEP,
+		Padrao: e.Padrao,
+	}
 }
 
 // ClienteInput é o DTO principal para a criação de um novo cliente.
+// Documento é o CPF ou CNPJ, com ou sem pontuação; é opcional no cadastro,
+// mas necessário para a emissão de nota fiscal.
 type ClienteInput struct {
 	Nome      string          `json:"nome"`
 	Email     string          `json:"email"`
+	Documento string          `json:"documento,omitempty"`
 	Enderecos []EnderecoInput `json:"enderecos"`
 }
 
+// AtualizarClienteInput é o DTO para a alteração dos dados cadastrais de um cliente.
+// Também é o documento sobre o qual um JSON Merge Patch é aplicado.
+type AtualizarClienteInput struct {
+	Nome      string `json:"nome"`
+	Email     string `json:"email"`
+	Documento string `json:"documento,omitempty"`
+}
+
 // CriarCliente é o caso de uso para criar um novo cliente.
 // Ele orquestra a conversão de DTOs para o domínio e a persistência.
 func (s *ClienteService) CriarCliente(ctx context.Context, input ClienteInput) (*domain.Cliente, error) {
-	// 1. Converte os DTOs de EnderecoInput para o tipo do domínio.
-	var enderecosDominio []*domain.Endereco
-	for _, endInput := range input.Enderecos {
-		enderecosDominio = append(enderecosDominio, &domain.Endereco{
-			Rua:    endInput.Rua,
-			Cidade: endInput.Cidade,
-			Estado: endInput.Estado,
-			CEP:    endInput.CEP,
-		})
-	}
-
-	// 2. Cria a entidade principal do domínio.
+	// 1. Cria a entidade principal do domínio.
 	// Em um cenário mais complexo, aqui poderíamos chamar um construtor
 	// como domain.NewCliente() que validaria as regras de negócio.
+	documento, err := domain.NewDocumento(input.Documento)
+	if err != nil {
+		return nil, err
+	}
 	novoCliente := &domain.Cliente{
-		Nome:      input.Nome,
-		Email:     input.Email,
-		Enderecos: enderecosDominio,
+		Nome:      strings.TrimSpace(input.Nome),
+		Email:     strings.TrimSpace(input.Email),
+		Documento: documento,
+		Enderecos: []*domain.Endereco{},
+	}
+
+	// 2. Converte os DTOs de EnderecoInput e os adiciona pelo agregado,
+	// que garante a regra de um endereço padrão por tipo.
+	for _, endInput := range input.Enderecos {
+		endereco, err := s.prepararEndereco(ctx, endInput)
+		if err != nil {
+			return nil, err
+		}
+		if err := novoCliente.AdicionarEndereco(&endereco); err != nil {
+			return nil, err
+		}
 	}
 
 	// 3. Chama o repositório para salvar o novo cliente no banco de dados.
@@ -64,8 +106,236 @@ func (s *ClienteService) CriarCliente(ctx context.Context, input ClienteInput) (
 	return novoCliente, nil
 }
 
-// ListarClientes é o caso de uso para buscar todos os clientes.
-func (s *ClienteService) ListarClientes(ctx context.Context) ([]*domain.Cliente, error) {
-	// A lógica aqui é simples: apenas repassamos a chamada para a camada de repositório.
-	return s.repo.FindAll(ctx)
+// ListarClientes é o caso de uso para buscar clientes em páginas, com busca e ordenação.
+func (s *ClienteService) ListarClientes(ctx context.Context, input ListarClientesInput) (*PaginaClientesOutput, error) {
+	filtro, err := input.paraFiltro()
+	if err != nil {
+		return nil, err
+	}
+
+	pagina, err := s.repo.List(ctx, filtro)
+	if err != nil {
+		return nil, err
+	}
+
+	// A listagem não expõe o documento inteiro; ele vem na consulta do cliente.
+	clientes := make([]*domain.Cliente, len(pagina.Clientes))
+	for i, c := range pagina.Clientes {
+		clientes[i] = c.ComDocumentoMascarado()
+	}
+
+	return &PaginaClientesOutput{
+		Clientes:   clientes,
+		NextCursor: codificarCursor(pagina.Proximo, filtro.Decrescente),
+		Pagina:     input.Pagina,
+		Tamanho:    filtro.Limite,
+		Total:      pagina.Total,
+	}, nil
+}
+
+// ListarPedidosDoClienteInput são o filtro de status e a página dos pedidos do cliente.
+type ListarPedidosDoClienteInput struct {
+	Status string
+	Cursor string
+	Limite int
+}
+
+// PedidosDoClienteOutput é o envelope de uma página de pedidos do cliente,
+// no mesmo formato de GET /pedidos.
+type PedidosDoClienteOutput struct {
+	Pedidos    []domain.PedidoDoCliente `json:"pedidos"`
+	NextCursor *string                  `json:"next_cursor"`
+}
+
+// ListarPedidosDoCliente é o caso de uso que consulta, no serviço de pedidos,
+// o histórico de pedidos de um cliente existente.
+func (s *ClienteService) ListarPedidosDoCliente(ctx context.Context, clienteID string, input ListarPedidosDoClienteInput) (*PedidosDoClienteOutput, error) {
+	if _, err := s.repo.FindByID(ctx, clienteID); err != nil {
+		return nil, err
+	}
+
+	pagina, err := s.pedidos.ListarPorCliente(ctx, clienteID, domain.FiltroPedidosCliente{
+		Status: input.Status,
+		Cursor: input.Cursor,
+		Limite: input.Limite,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PedidosDoClienteOutput{Pedidos: pagina.Pedidos, NextCursor: pagina.NextCursor}, nil
+}
+
+// BuscarClientePorID é o caso de uso para buscar um cliente e seus endereços.
+func (s *ClienteService) BuscarClientePorID(ctx context.Context, id string) (*domain.Cliente, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// AtualizarCliente é o caso de uso para substituir nome, e-mail e documento de um cliente (PUT).
+// versao é a versão lida pelo chamador (If-Match); zero não verifica.
+func (s *ClienteService) AtualizarCliente(ctx context.Context, id string, versao int, input AtualizarClienteInput) (*domain.Cliente, error) {
+	cliente, err := s.buscarNaVersao(ctx, id, versao)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.salvarAlteracao(ctx, cliente, input)
+}
+
+// AplicarPatchCliente é o caso de uso para alterar parcialmente um cliente
+// com um JSON Merge Patch (RFC 7396, PATCH). O patch é aplicado sobre a
+// versão atual do cliente, que precisa ser a versão informada (zero não verifica).
+func (s *ClienteService) AplicarPatchCliente(ctx context.Context, id string, versao int, patch []byte) (*domain.Cliente, error) {
+	cliente, err := s.buscarNaVersao(ctx, id, versao)
+	if err != nil {
+		return nil, err
+	}
+
+	atual, err := json.Marshal(AtualizarClienteInput{Nome: cliente.Nome, Email: cliente.Email, Documento: string(cliente.Documento)})
+	if err != nil {
+		return nil, err
+	}
+
+	alterado, err := mergepatch.Aplicar(atual, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	// O documento resultante passa pelas mesmas regras do corpo de um PUT.
+	var input AtualizarClienteInput
+	if err := validacao.DecodificarJSON(alterado, &input); err != nil {
+		return nil, err
+	}
+
+	return s.salvarAlteracao(ctx, cliente, input)
+}
+
+// ExcluirCliente é o caso de uso para a exclusão (lógica) de um cliente.
+// Com versao diferente de zero, só exclui o cliente se ele estiver nessa versão.
+func (s *ClienteService) ExcluirCliente(ctx context.Context, id string, versao int) error {
+	return s.repo.Delete(ctx, id, versao)
+}
+
+// buscarNaVersao carrega o cliente e confere a versão esperada pelo chamador.
+func (s *ClienteService) buscarNaVersao(ctx context.Context, id string, versao int) (*domain.Cliente, error) {
+	cliente, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := cliente.VerificarVersao(versao); err != nil {
+		return nil, err
+	}
+	return cliente, nil
+}
+
+// salvarAlteracao aplica os novos dados ao cliente e persiste o resultado.
+func (s *ClienteService) salvarAlteracao(ctx context.Context, cliente *domain.Cliente, input AtualizarClienteInput) (*domain.Cliente, error) {
+	documento, err := domain.NewDocumento(input.Documento)
+	if err != nil {
+		return nil, err
+	}
+	if err := cliente.Atualizar(input.Nome, input.Email, documento); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, cliente); err != nil {
+		return nil, err
+	}
+
+	return cliente, nil
+}
+
+// ListarEnderecos é o caso de uso para listar os endereços de um cliente.
+func (s *ClienteService) ListarEnderecos(ctx context.Context, clienteID string) ([]*domain.Endereco, error) {
+	cliente, err := s.repo.FindByID(ctx, clienteID)
+	if err != nil {
+		return nil, err
+	}
+	return cliente.Enderecos, nil
+}
+
+// BuscarEndereco é o caso de uso para buscar um endereço específico de um cliente.
+func (s *ClienteService) BuscarEndereco(ctx context.Context, clienteID string, enderecoID int64) (*domain.Endereco, error) {
+	cliente, err := s.repo.FindByID(ctx, clienteID)
+	if err != nil {
+		return nil, err
+	}
+	endereco := cliente.Endereco(enderecoID)
+	if endereco == nil {
+		return nil, domain.ErrEnderecoNaoEncontrado
+	}
+	return endereco, nil
+}
+
+// AdicionarEndereco é o caso de uso para incluir um endereço em um cliente existente.
+func (s *ClienteService) AdicionarEndereco(ctx context.Context, clienteID string, input EnderecoInput) (*domain.Endereco, error) {
+	cliente, err := s.repo.FindByID(ctx, clienteID)
+	if err != nil {
+		return nil, err
+	}
+
+	endereco, err := s.prepararEndereco(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := cliente.AdicionarEndereco(&endereco); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.AddEndereco(ctx, cliente, &endereco); err != nil {
+		return nil, err
+	}
+	return &endereco, nil
+}
+
+// AtualizarEndereco é o caso de uso para alterar um endereço de um cliente.
+func (s *ClienteService) AtualizarEndereco(ctx context.Context, clienteID string, enderecoID int64, input EnderecoInput) (*domain.Endereco, error) {
+	cliente, err := s.repo.FindByID(ctx, clienteID)
+	if err != nil {
+		return nil, err
+	}
+
+	dados, err := s.prepararEndereco(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	endereco, err := cliente.AlterarEndereco(enderecoID, dados)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateEndereco(ctx, cliente, endereco); err != nil {
+		return nil, err
+	}
+	return endereco, nil
+}
+
+// prepararEndereco converte o DTO no endereço do domínio, padronizado e com
+// cidade e UF completadas ou conferidas pela localidade do CEP.
+func (s *ClienteService) prepararEndereco(ctx context.Context, input EnderecoInput) (domain.Endereco, error) {
+	endereco := input.paraDominio()
+	cep := domain.NormalizarCEP(endereco.CEP)
+	if cep == "" {
+		return endereco, fmt.Errorf("%w: CEP deve ter 8 dígitos", domain.ErrEnderecoInvalido)
+	}
+
+	localidade, err := s.ceps.Consultar(ctx, strings.ReplaceAll(cep, "-", ""))
+	if err != nil {
+		return endereco, err
+	}
+	return endereco, endereco.AplicarLocalidade(localidade)
+}
+
+// RemoverEndereco é o caso de uso para remover um endereço de um cliente.
+func (s *ClienteService) RemoverEndereco(ctx context.Context, clienteID string, enderecoID int64) error {
+	cliente, err := s.repo.FindByID(ctx, clienteID)
+	if err != nil {
+		return err
+	}
+
+	if err := cliente.RemoverEndereco(enderecoID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteEndereco(ctx, cliente, enderecoID)
 }
@@ -0,0 +1,137 @@
+package application
+
+import (
+	"context"
+	"ecommerce/clientes/internal/domain"
+	"ecommerce/pkg/common/validacao"
+)
+
+// limiteGruposDuplicados limita os grupos devolvidos por critério em cada busca
+// de duplicados e o tamanho das páginas lidas do repositório; depois de
+// mesclados, os seguintes aparecem na próxima busca.
+const limiteGruposDuplicados = 100
+
+// GrupoDuplicadoOutput são clientes que provavelmente são a mesma pessoa.
+// Criterio é "email" (mesmo e-mail normalizado) ou "nome_cep" (nomes
+// parecidos e endereço no mesmo CEP). Os documentos vêm mascarados.
+type GrupoDuplicadoOutput struct {
+	Criterio string            `json:"criterio"`
+	Clientes []*domain.Cliente `json:"clientes"`
+}
+
+// DuplicadosOutput é o resultado da busca de clientes duplicados.
+type DuplicadosOutput struct {
+	Grupos []GrupoDuplicadoOutput `json:"grupos"`
+}
+
+// MesclarClientesInput indica o cliente que será absorvido pelo cliente da URL.
+type MesclarClientesInput struct {
+	OrigemID string `json:"origem_id"`
+}
+
+// Validar confere o ID do cliente absorvido.
+func (in MesclarClientesInput) Validar() error {
+	v := validacao.New()
+	if v.Obrigatorio("origem_id", in.OrigemID) {
+		v.UUID("origem_id", in.OrigemID)
+	}
+	return v.Erro()
+}
+
+// BuscarDuplicados é o caso de uso que aponta os clientes ativos que
+// provavelmente são cadastros repetidos da mesma pessoa, para revisão e
+// mesclagem. Nada é alterado.
+func (s *ClienteService) BuscarDuplicados(ctx context.Context) (*DuplicadosOutput, error) {
+	saida := &DuplicadosOutput{Grupos: []GrupoDuplicadoOutput{}}
+
+	porEmail, err := s.repo.BuscarSuspeitosDeDuplicidade(ctx, domain.CriterioEmail, "", limiteGruposDuplicados)
+	if err != nil {
+		return nil, err
+	}
+	for _, grupo := range porEmail {
+		saida.Grupos = append(saida.Grupos, grupoMascarado(grupo.Criterio, grupo.Clientes))
+	}
+
+	// Dividir o mesmo CEP não basta: só os nomes parecidos formam um grupo.
+	// CEPs compartilhados por muitas pessoas, como os de prédios, raramente
+	// têm nomes parecidos, então os CEPs são percorridos página a página até
+	// completar o limite ou acabarem.
+	encontrados, apos := 0, ""
+	for encontrados < limiteGruposDuplicados {
+		pagina, err := s.repo.BuscarSuspeitosDeDuplicidade(ctx, domain.CriterioNomeECEP, apos, limiteGruposDuplicados)
+		if err != nil {
+			return nil, err
+		}
+		for _, grupo := range pagina {
+			for _, clientes := range agruparPorNome(grupo.Clientes) {
+				if len(clientes) < 2 || encontrados == limiteGruposDuplicados {
+					continue
+				}
+				saida.Grupos = append(saida.Grupos, grupoMascarado(grupo.Criterio, clientes))
+				encontrados++
+			}
+		}
+		if len(pagina) < limiteGruposDuplicados {
+			break
+		}
+		apos = pagina[len(pagina)-1].Chave
+	}
+	return saida, nil
+}
+
+// grupoMascarado monta a saída de um grupo com os documentos mascarados.
+func grupoMascarado(criterio domain.CriterioDuplicidade, clientes []*domain.Cliente) GrupoDuplicadoOutput {
+	mascarados := make([]*domain.Cliente, len(clientes))
+	for i, c := range clientes {
+		mascarados[i] = c.ComDocumentoMascarado()
+	}
+	return GrupoDuplicadoOutput{Criterio: string(criterio), Clientes: mascarados}
+}
+
+// agruparPorNome separa os clientes em grupos de nomes parecidos: cada
+// cliente entra no primeiro grupo com algum nome parecido com o dele.
+func agruparPorNome(clientes []*domain.Cliente) [][]*domain.Cliente {
+	var grupos [][]*domain.Cliente
+	for _, cliente := range clientes {
+		agrupado := false
+		for i, grupo := range grupos {
+			for _, outro := range grupo {
+				if domain.NomesParecidos(cliente.Nome, outro.Nome) {
+					grupos[i] = append(grupos[i], cliente)
+					agrupado = true
+					break
+				}
+			}
+			if agrupado {
+				break
+			}
+		}
+		if !agrupado {
+			grupos = append(grupos, []*domain.Cliente{cliente})
+		}
+	}
+	return grupos
+}
+
+// MesclarClientes é o caso de uso que une dois cadastros da mesma pessoa: o
+// cliente da origem é excluído e seus endereços passam para o destino, que
+// precisa estar na versão informada (zero não verifica). Os pedidos da origem
+// são transferidos pelo serviço de pedidos ao receber o evento cliente.mesclado.
+func (s *ClienteService) MesclarClientes(ctx context.Context, destinoID string, versao int, input MesclarClientesInput) (*domain.Cliente, error) {
+	destino, err := s.buscarNaVersao(ctx, destinoID, versao)
+	if err != nil {
+		return nil, err
+	}
+	origem, err := s.repo.FindByID(ctx, input.OrigemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := destino.Absorver(origem); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Mesclar(ctx, destino, origem); err != nil {
+		return nil, err
+	}
+	return destino, nil
+}
@@ -0,0 +1,102 @@
+package application
+
+import (
+	"context"
+	"ecommerce/clientes/internal/domain"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// repoDeSuspeitos devolve páginas fixas de grupos por critério, conferindo a
+// chave pedida como início de cada página. Os demais métodos do repositório
+// não são usados pela busca de duplicados.
+type repoDeSuspeitos struct {
+	domain.ClienteRepository
+	grupos map[domain.CriterioDuplicidade][]domain.GrupoDuplicado
+	buscas int
+}
+
+func (r *repoDeSuspeitos) BuscarSuspeitosDeDuplicidade(_ context.Context, criterio domain.CriterioDuplicidade, apos string, limite int) ([]domain.GrupoDuplicado, error) {
+	r.buscas++
+	var pagina []domain.GrupoDuplicado
+	for _, grupo := range r.grupos[criterio] {
+		if grupo.Chave > apos && len(pagina) < limite {
+			pagina = append(pagina, grupo)
+		}
+	}
+	return pagina, nil
+}
+
+func novoCliente(nome string, idade time.Duration) *domain.Cliente {
+	return &domain.Cliente{ID: nome, Nome: nome, CriadoEm: time.Now().Add(-idade)}
+}
+
+func TestBuscarDuplicadosPercorreOsCEPsAteAcharNomesParecidos(t *testing.T) {
+	// Os primeiros CEPs em ordem são de prédios, sem nomes parecidos; o único
+	// grupo de verdade está depois da primeira página.
+	var grupos []domain.GrupoDuplicado
+	for i := 0; i < limiteGruposDuplicados+10; i++ {
+		grupos = append(grupos, domain.GrupoDuplicado{
+			Criterio: domain.CriterioNomeECEP,
+			Chave:    fmt.Sprintf("01%06d", i),
+			Clientes: []*domain.Cliente{novoCliente(fmt.Sprintf("Ana %d Souza", i), time.Hour), novoCliente(fmt.Sprintf("Bruno %d Lima", i), time.Minute)},
+		})
+	}
+	grupos = append(grupos, domain.GrupoDuplicado{
+		Criterio: domain.CriterioNomeECEP,
+		Chave:    "99999999",
+		Clientes: []*domain.Cliente{novoCliente("Maria Silva", time.Hour), novoCliente("Carlos Pereira", time.Minute), novoCliente("Maria da Silva", time.Second)},
+	})
+	repo := &repoDeSuspeitos{grupos: map[domain.CriterioDuplicidade][]domain.GrupoDuplicado{domain.CriterioNomeECEP: grupos}}
+
+	saida, err := NewClienteService(repo, nil, nil).BuscarDuplicados(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saida.Grupos) != 1 {
+		t.Fatalf("grupos = %d, esperado 1", len(saida.Grupos))
+	}
+	grupo := saida.Grupos[0]
+	if grupo.Criterio != "nome_cep" || len(grupo.Clientes) != 2 || grupo.Clientes[0].Nome != "Maria Silva" || grupo.Clientes[1].Nome != "Maria da Silva" {
+		t.Errorf("grupo = %+v, esperado Maria Silva e Maria da Silva", grupo)
+	}
+	// Uma busca por e-mail e duas páginas de CEPs.
+	if repo.buscas != 3 {
+		t.Errorf("buscas = %d, esperado 3", repo.buscas)
+	}
+}
+
+func TestBuscarDuplicadosParaNoLimiteDeGrupos(t *testing.T) {
+	var grupos []domain.GrupoDuplicado
+	for i := 0; i < 3*limiteGruposDuplicados; i++ {
+		grupos = append(grupos, domain.GrupoDuplicado{
+			Criterio: domain.CriterioNomeECEP,
+			Chave:    fmt.Sprintf("%08d", i),
+			Clientes: []*domain.Cliente{novoCliente("Maria Silva", time.Hour), novoCliente("Maria Silva", time.Minute)},
+		})
+	}
+	email := []domain.GrupoDuplicado{{
+		Criterio: domain.CriterioEmail,
+		Chave:    "maria@exemplo.com",
+		Clientes: []*domain.Cliente{novoCliente("Maria", time.Hour), novoCliente("M. Silva", time.Minute)},
+	}}
+	repo := &repoDeSuspeitos{grupos: map[domain.CriterioDuplicidade][]domain.GrupoDuplicado{
+		domain.CriterioEmail:    email,
+		domain.CriterioNomeECEP: grupos,
+	}}
+
+	saida, err := NewClienteService(repo, nil, nil).BuscarDuplicados(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saida.Grupos) != 1+limiteGruposDuplicados {
+		t.Fatalf("grupos = %d, esperado %d", len(saida.Grupos), 1+limiteGruposDuplicados)
+	}
+	if saida.Grupos[0].Criterio != "email" {
+		t.Errorf("primeiro grupo = %q, esperado email", saida.Grupos[0].Criterio)
+	}
+	if repo.buscas != 2 {
+		t.Errorf("buscas = %d, esperado 2: a primeira página já completa o limite", repo.buscas)
+	}
+}
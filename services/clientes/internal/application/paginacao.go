@@ -0,0 +1,159 @@
+package application
+
+import (
+	"ecommerce/clientes/internal/domain"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Limites de tamanho de página da listagem de clientes.
+const (
+	tamanhoPadrao = 20
+	tamanhoMaximo = 100
+)
+
+// ordemPadrao lista primeiro os clientes mais novos, como a listagem sempre fez.
+const ordemPadrao = "-criado_em"
+
+// ListarClientesInput são a busca, a ordenação e a página da listagem de clientes.
+// Ordem é "nome" ou "criado_em", com "-" na frente para ordem decrescente.
+// A página vem do Cursor (next_cursor da página anterior) ou de Pagina, a partir de 1.
+type ListarClientesInput struct {
+	Nome      string // Início do nome.
+	Email     string
+	CEP       string
+	Cidade    string
+	Documento string // CPF ou CNPJ exato, com ou sem pontuação.
+	Ordem     string
+	Cursor    string
+	Pagina    int
+	Tamanho   int
+	ComTotal  bool
+}
+
+// PaginaClientesOutput é o envelope de uma página da listagem de clientes.
+// NextCursor é null na última página; Total só vem quando solicitado.
+// Os documentos dos clientes vêm mascarados.
+type PaginaClientesOutput struct {
+	Clientes   []*domain.Cliente `json:"clientes"`
+	NextCursor *string           `json:"next_cursor"`
+	Pagina     int               `json:"page,omitempty"`
+	Tamanho    int               `json:"size"`
+	Total      *int              `json:"total,omitempty"`
+}
+
+// cursorJSON é o conteúdo do cursor opaco entregue ao cliente da API.
+type cursorJSON struct {
+	Ordem       domain.OrdemClientes `json:"o"`
+	Decrescente bool                 `json:"d,omitempty"`
+	Nome        string               `json:"n,omitempty"`
+	CriadoEm    time.Time            `json:"c"`
+	ID          string               `json:"i"`
+}
+
+func (in ListarClientesInput) paraFiltro() (domain.FiltroClientes, error) {
+	filtro := domain.FiltroClientes{
+		NomePrefixo: strings.TrimSpace(in.Nome),
+		Email:       strings.TrimSpace(in.Email),
+		Cidade:      strings.TrimSpace(in.Cidade),
+		Limite:      in.Tamanho,
+		ContarTotal: in.ComTotal,
+	}
+
+	if in.CEP != "" {
+		filtro.CEP = somenteDigitos(in.CEP)
+		if filtro.CEP == "" {
+			return filtro, fmt.Errorf("%w: cep deve conter dígitos", domain.ErrFiltroInvalido)
+		}
+	}
+
+	documento, err := domain.NewDocumento(in.Documento)
+	if err != nil {
+		return filtro, fmt.Errorf("%w: documento deve ser um CPF ou CNPJ válido", domain.ErrFiltroInvalido)
+	}
+	filtro.Documento = documento
+
+	ordem := in.Ordem
+	if ordem == "" {
+		ordem = ordemPadrao
+	}
+	filtro.Decrescente = strings.HasPrefix(ordem, "-")
+	filtro.Ordem = domain.OrdemClientes(strings.TrimPrefix(ordem, "-"))
+	if filtro.Ordem != domain.OrdemPorNome && filtro.Ordem != domain.OrdemPorCriadoEm {
+		return filtro, fmt.Errorf("%w: sort deve ser nome, -nome, criado_em ou -criado_em", domain.ErrFiltroInvalido)
+	}
+
+	switch {
+	case filtro.Limite == 0:
+		filtro.Limite = tamanhoPadrao
+	case filtro.Limite < 0 || filtro.Limite > tamanhoMaximo:
+		return filtro, fmt.Errorf("%w: size deve estar entre 1 e %d", domain.ErrFiltroInvalido, tamanhoMaximo)
+	}
+
+	if in.Cursor != "" && in.Pagina != 0 {
+		return filtro, fmt.Errorf("%w: use cursor ou page, não os dois", domain.ErrFiltroInvalido)
+	}
+	if in.Pagina < 0 {
+		return filtro, fmt.Errorf("%w: page deve ser maior que zero", domain.ErrFiltroInvalido)
+	}
+	if in.Pagina > 1 {
+		filtro.Offset = (in.Pagina - 1) * filtro.Limite
+	}
+
+	if in.Cursor != "" {
+		apos, err := decodificarCursor(in.Cursor, filtro)
+		if err != nil {
+			return filtro, err
+		}
+		filtro.Apos = apos
+	}
+	return filtro, nil
+}
+
+// codificarCursor gera o cursor opaco a partir da posição do último cliente
+// da página. A ordenação vai junto para que o cursor não seja usado com outra.
+func codificarCursor(c *domain.CursorCliente, decrescente bool) *string {
+	if c == nil {
+		return nil
+	}
+	dados, _ := json.Marshal(cursorJSON{Ordem: c.Ordem, Decrescente: decrescente, Nome: c.Nome, CriadoEm: c.CriadoEm, ID: c.ID})
+	cursor := base64.RawURLEncoding.EncodeToString(dados)
+	return &cursor
+}
+
+// decodificarCursor faz o caminho inverso de codificarCursor e confere se o
+// cursor pertence à mesma ordenação pedida.
+func decodificarCursor(cursor string, filtro domain.FiltroClientes) (*domain.CursorCliente, error) {
+	dados, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cursor inválido", domain.ErrFiltroInvalido)
+	}
+	var c cursorJSON
+	if err := json.Unmarshal(dados, &c); err != nil {
+		return nil, fmt.Errorf("%w: cursor inválido", domain.ErrFiltroInvalido)
+	}
+	// O ID vai direto para a query: um cursor adulterado não pode chegar ao banco.
+	clienteID, err := uuid.Parse(c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cursor inválido", domain.ErrFiltroInvalido)
+	}
+	if c.Ordem != filtro.Ordem || c.Decrescente != filtro.Decrescente {
+		return nil, fmt.Errorf("%w: cursor gerado com outra ordenação", domain.ErrFiltroInvalido)
+	}
+	return &domain.CursorCliente{Ordem: c.Ordem, Nome: c.Nome, CriadoEm: c.CriadoEm, ID: clienteID.String()}, nil
+}
+
+func somenteDigitos(texto string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, texto)
+}
@@ -0,0 +1,86 @@
+package application
+
+import (
+	"ecommerce/clientes/internal/domain"
+	"ecommerce/pkg/common/validacao"
+	"strings"
+)
+
+// Limites de tamanho dos campos de texto do cadastro.
+const (
+	tamanhoMaximoNome   = 150
+	tamanhoMaximoEmail  = 254
+	tamanhoMaximoRua    = 200
+	tamanhoMaximoCidade = 100
+	maximoEnderecos     = 20
+)
+
+// Regras próprias do cadastro de clientes.
+const (
+	regraDocumento = "documento" // CPF ou CNPJ com dígitos verificadores corretos.
+	regraUF        = "uf"        // Sigla de uma das 27 UFs.
+)
+
+func init() {
+	validacao.RegistrarMensagens(regraDocumento, "deve ser um CPF ou CNPJ válido", "must be a valid CPF or CNPJ")
+	validacao.RegistrarMensagens(regraUF, "deve ser a sigla de uma UF, como SP", "must be a Brazilian state code, such as SP")
+}
+
+// Validar confere o formato dos dados do novo cliente e de seus endereços.
+// É chamado por validacao.Decodificar ao ler o corpo da requisição.
+func (in ClienteInput) Validar() error {
+	v := validacao.New()
+	validarDadosCadastrais(v, in.Nome, in.Email, in.Documento)
+	v.ItensEntre("enderecos", len(in.Enderecos), 0, maximoEnderecos)
+	for i, endereco := range in.Enderecos {
+		endereco.validar(v, func(campo string) string { return validacao.Indice("enderecos", i, campo) })
+	}
+	return v.Erro()
+}
+
+// Validar confere o formato dos dados cadastrais alterados.
+func (in AtualizarClienteInput) Validar() error {
+	v := validacao.New()
+	validarDadosCadastrais(v, in.Nome, in.Email, in.Documento)
+	return v.Erro()
+}
+
+// Validar confere o formato dos dados do endereço.
+func (in EnderecoInput) Validar() error {
+	v := validacao.New()
+	in.validar(v, func(campo string) string { return campo })
+	return v.Erro()
+}
+
+func validarDadosCadastrais(v *validacao.Validador, nome, email, documento string) {
+	if v.Obrigatorio("nome", nome) {
+		v.TamanhoMaximo("nome", nome, tamanhoMaximoNome)
+	}
+	if v.Obrigatorio("email", email) && v.TamanhoMaximo("email", email, tamanhoMaximoEmail) {
+		v.Email("email", email)
+	}
+	if documento != "" {
+		_, err := domain.NewDocumento(documento)
+		v.Verificar(err == nil, "documento", regraDocumento)
+	}
+}
+
+// validar acumula em v as falhas do endereço; nome dá o nome completo de
+// cada campo (direto ou dentro da lista de endereços do cliente).
+func (in EnderecoInput) validar(v *validacao.Validador, nome func(string) string) {
+	if in.Tipo != "" {
+		v.UmDe(nome("tipo"), in.Tipo, "entrega", "cobranca")
+	}
+	if v.Obrigatorio(nome("rua"), in.Rua) {
+		v.TamanhoMaximo(nome("rua"), in.Rua, tamanhoMaximoRua)
+	}
+	// Cidade e estado podem faltar: são completados pelo CEP.
+	v.TamanhoMaximo(nome("cidade"), in.Cidade, tamanhoMaximoCidade)
+	if estado := strings.ToUpper(strings.TrimSpace(in.Estado)); estado != "" {
+		_, ok := domain.UFs[estado]
+		v.Verificar(ok, nome("estado"), regraUF)
+	}
+	if v.Obrigatorio(nome("cep"), in.CEP) {
+		v.CEP(nome("cep"), in.CEP)
+	}
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"strings"
+)
+
+// UFs são as siglas das 27 unidades federativas, com o nome de cada uma.
+var UFs = map[string]string{
+	"AC": "Acre", "AL": "Alagoas", "AP": "Amapá", "AM": "Amazonas", "BA": "Bahia",
+	"CE": "Ceará", "DF": "Distrito Federal", "ES": "Espírito Santo", "GO": "Goiás",
+	"MA": "Maranhão", "MT": "Mato Grosso", "MS": "Mato Grosso do Sul", "MG": "Minas Gerais",
+	"PA": "Pará", "PB": "Paraíba", "PR": "Paraná", "PE": "Pernambuco", "PI": "Piauí",
+	"RJ": "Rio de Janeiro", "RN": "Rio Grande do Norte", "RS": "Rio Grande do Sul",
+	"RO": "Rondônia", "RR": "Roraima", "SC": "Santa Catarina", "SP": "São Paulo",
+	"SE": "Sergipe", "TO": "Tocantins",
+}
+
+// Localidade é a cidade e a UF a que um CEP pertence. Cidade é vazia quando
+// a consulta só conhece a UF do CEP.
+type Localidade struct {
+	Cidade string
+	Estado string // Sigla da UF.
+}
+
+// ConsultaCEP descobre a localidade de um CEP. A implementação padrão usa uma
+// base de faixas embutida no serviço; outras (como um provedor HTTP no estilo
+// do ViaCEP) só precisam implementar esta interface.
+type ConsultaCEP interface {
+	// Consultar recebe o CEP com 8 dígitos, sem hífen, e retorna
+	// ErrCEPNaoEncontrado se ele não pertencer a nenhuma localidade conhecida.
+	Consultar(ctx context.Context, cep string) (Localidade, error)
+}
+
+// NormalizarCEP retorna o CEP no formato 01310-100, ou vazio se o texto não
+// tiver exatamente 8 dígitos (com ou sem hífen).
+func NormalizarCEP(texto string) string {
+	digitos := strings.ReplaceAll(strings.TrimSpace(texto), "-", "")
+	if len(digitos) != 8 || !somenteDigitos(digitos) {
+		return ""
+	}
+	return digitos[:5] + "-" + digitos[5:]
+}
+
+// MesmaCidade compara nomes de cidade sem diferenciar maiúsculas, acentos e
+// espaços nas pontas: "sao paulo" e "São Paulo" são a mesma cidade.
+func MesmaCidade(a, b string) bool {
+	return semAcentos(a) == semAcentos(b)
+}
+
+// removerAcentos troca as letras acentuadas do português pelas sem acento.
+var removerAcentos = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "é", "e", "ê", "e", "í", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ú", "u", "ü", "u", "ç", "c",
+)
+
+func semAcentos(texto string) string {
+	return removerAcentos.Replace(strings.ToLower(strings.TrimSpace(texto)))
+}
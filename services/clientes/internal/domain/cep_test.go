@@ -0,0 +1,42 @@
+package domain
+
+import "testing"
+
+func TestNormalizarCEP(t *testing.T) {
+	casos := map[string]string{
+		"01310-100":  "01310-100",
+		"01310100":   "01310-100",
+		" 01310100 ": "01310-100",
+		"0131-0100":  "01310-100",
+		"0131010":    "",
+		"013101000":  "",
+		"01310-10A":  "",
+		"+1310100":   "",
+		"01.310-100": "",
+		"":           "",
+	}
+	for texto, esperado := range casos {
+		if got := NormalizarCEP(texto); got != esperado {
+			t.Errorf("NormalizarCEP(%q) = %q, esperado %q", texto, got, esperado)
+		}
+	}
+}
+
+func TestMesmaCidade(t *testing.T) {
+	casos := []struct {
+		a, b  string
+		mesma bool
+	}{
+		{"São Paulo", "sao paulo", true},
+		{" São Luís ", "SAO LUIS", true},
+		{"Goiânia", "goiania", true},
+		{"Florianópolis", "Florianopolis", true},
+		{"São Paulo", "Santo André", false},
+		{"Brasília", "Brasil", false},
+	}
+	for _, c := range casos {
+		if got := MesmaCidade(c.a, c.b); got != c.mesma {
+			t.Errorf("MesmaCidade(%q, %q) = %v, esperado %v", c.a, c.b, got, c.mesma)
+		}
+	}
+}
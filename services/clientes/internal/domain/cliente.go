@@ -1,22 +1,153 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Cliente é a nossa raiz de agregado.
 type Cliente struct {
 	ID         string
 	Nome       string
 	Email      string
+	Documento  Documento // CPF ou CNPJ; vazio se não informado.
 	Enderecos  []*Endereco
 	CriadoEm   time.Time
 	AlteradoEm time.Time
+	// Versao começa em 1 e é incrementada pelo repositório a cada alteração do
+	// cliente ou dos seus endereços; é a base do controle de concorrência otimista.
+	Versao int
 }
 
-// Endereco pertence ao agregado de Cliente.
-type Endereco struct {
-	ID     int64
-	Rua    string
-	Cidade string
-	Estado string
-	CEP    string
+// VerificarVersao confere se o cliente ainda está na versão em que o chamador
+// o leu. Zero dispensa a verificação.
+func (c *Cliente) VerificarVersao(esperada int) error {
+	if esperada != 0 && esperada != c.Versao {
+		return ErrConflitoDeVersao
+	}
+	return nil
+}
+
+// Atualizar altera os dados cadastrais do cliente, mantendo AlteradoEm.
+func (c *Cliente) Atualizar(nome, email string, documento Documento) error {
+	nome = strings.TrimSpace(nome)
+	email = strings.TrimSpace(email)
+	if nome == "" || email == "" {
+		return ErrClienteInvalido
+	}
+
+	c.Nome = nome
+	c.Email = email
+	c.Documento = documento
+	c.AlteradoEm = time.Now()
+	return nil
+}
+
+// ComDocumentoMascarado retorna uma cópia do cliente para listagens, com o
+// documento mascarado (ver Documento.Mascarado). A cópia é só para exibição.
+func (c *Cliente) ComDocumentoMascarado() *Cliente {
+	copia := *c
+	copia.Documento = Documento(c.Documento.Mascarado())
+	return &copia
+}
+
+// Absorver incorpora ao cliente os dados de outro cadastro da mesma pessoa,
+// que será excluído: os endereços passam para este cliente, sem substituir os
+// padrões que ele já tem, e o documento é herdado se este não tiver um.
+func (c *Cliente) Absorver(origem *Cliente) error {
+	if origem.ID == c.ID {
+		return ErrMesclagemInvalida
+	}
+
+	for _, endereco := range origem.Enderecos {
+		endereco.Padrao = c.EnderecoPadrao(endereco.Tipo) == nil
+		c.Enderecos = append(c.Enderecos, endereco)
+	}
+	origem.Enderecos = []*Endereco{}
+	if c.Documento == "" {
+		c.Documento = origem.Documento
+	}
+	c.AlteradoEm = time.Now()
+	return nil
+}
+
+// AdicionarEndereco inclui um endereço no cliente. O primeiro endereço de cada
+// tipo vira o padrão; um novo padrão substitui o anterior do mesmo tipo.
+func (c *Cliente) AdicionarEndereco(endereco *Endereco) error {
+	if err := endereco.validar(); err != nil {
+		return err
+	}
+
+	if c.EnderecoPadrao(endereco.Tipo) == nil {
+		endereco.Padrao = true
+	}
+	if endereco.Padrao {
+		c.desmarcarPadrao(endereco.Tipo)
+	}
+
+	c.Enderecos = append(c.Enderecos, endereco)
+	return nil
+}
+
+// AlterarEndereco substitui os dados de um endereço existente do cliente.
+func (c *Cliente) AlterarEndereco(enderecoID int64, dados Endereco) (*Endereco, error) {
+	endereco := c.Endereco(enderecoID)
+	if endereco == nil {
+		return nil, ErrEnderecoNaoEncontrado
+	}
+	if err := dados.validar(); err != nil {
+		return nil, err
+	}
+
+	if dados.Padrao {
+		c.desmarcarPadrao(dados.Tipo)
+	}
+
+	endereco.Tipo = dados.Tipo
+	endereco.Rua = dados.Rua
+	endereco.Cidade = dados.Cidade
+	endereco.Estado = dados.Estado
+	endereco.CEP = dados.CEP
+	endereco.Padrao = dados.Padrao
+	return endereco, nil
+}
+
+// RemoverEndereco retira um endereço do cliente.
+func (c *Cliente) RemoverEndereco(enderecoID int64) error {
+	for i, e := range c.Enderecos {
+		if e.ID == enderecoID {
+			c.Enderecos = append(c.Enderecos[:i], c.Enderecos[i+1:]...)
+			return nil
+		}
+	}
+	return ErrEnderecoNaoEncontrado
+}
+
+// Endereco busca um endereço do cliente pelo ID.
+func (c *Cliente) Endereco(enderecoID int64) *Endereco {
+	for _, e := range c.Enderecos {
+		if e.ID == enderecoID {
+			return e
+		}
+	}
+	return nil
+}
+
+// EnderecoPadrao retorna o endereço padrão do tipo informado, se houver.
+func (c *Cliente) EnderecoPadrao(tipo TipoEndereco) *Endereco {
+	for _, e := range c.Enderecos {
+		if e.Tipo == tipo && e.Padrao {
+			return e
+		}
+	}
+	return nil
+}
+
+// desmarcarPadrao garante a regra de no máximo um endereço padrão por tipo.
+func (c *Cliente) desmarcarPadrao(tipo TipoEndereco) {
+	for _, e := range c.Enderecos {
+		if e.Tipo == tipo {
+			e.Padrao = false
+		}
+	}
 }
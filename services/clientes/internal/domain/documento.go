@@ -0,0 +1,152 @@
+package domain
+
+import "strings"
+
+// TipoDocumento indica se o documento é de pessoa física ou jurídica.
+type TipoDocumento string
+
+// Os possíveis tipos de documento.
+const (
+	TipoCPF  TipoDocumento = "cpf"  // Pessoa física.
+	TipoCNPJ TipoDocumento = "cnpj" // Pessoa jurídica.
+)
+
+// Documento é o CPF ou o CNPJ do cliente, exigido na emissão de nota fiscal.
+// Guarda só os caracteres significativos, sem pontuação: 11 dígitos no CPF e
+// 14 caracteres no CNPJ, que pode ser alfanumérico (IN RFB 2.229/2024).
+// O valor vazio indica um cliente sem documento informado.
+type Documento string
+
+// NewDocumento valida e normaliza um CPF ou CNPJ, com ou sem pontuação.
+// O tipo é deduzido pelo tamanho. Texto vazio resulta no documento vazio.
+func NewDocumento(texto string) (Documento, error) {
+	numero := strings.ToUpper(strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '-', '/', ' ':
+			return -1
+		}
+		return r
+	}, texto))
+
+	switch {
+	case numero == "":
+		return "", nil
+	case len(numero) == 11 && cpfValido(numero):
+		return Documento(numero), nil
+	case len(numero) == 14 && cnpjValido(numero):
+		return Documento(numero), nil
+	}
+	return "", ErrDocumentoInvalido
+}
+
+// Tipo retorna TipoCPF ou TipoCNPJ, ou vazio se o documento não foi informado.
+func (d Documento) Tipo() TipoDocumento {
+	switch len(d) {
+	case 11:
+		return TipoCPF
+	case 14:
+		return TipoCNPJ
+	}
+	return ""
+}
+
+// PessoaJuridica informa se o documento é um CNPJ.
+func (d Documento) PessoaJuridica() bool {
+	return d.Tipo() == TipoCNPJ
+}
+
+// Formatado retorna o documento com a pontuação usual:
+// 123.456.789-09 ou 12.345.678/0001-95.
+func (d Documento) Formatado() string {
+	s := string(d)
+	switch d.Tipo() {
+	case TipoCPF:
+		return s[:3] + "." + s[3:6] + "." + s[6:9] + "-" + s[9:]
+	case TipoCNPJ:
+		return s[:2] + "." + s[2:5] + "." + s[5:8] + "/" + s[8:12] + "-" + s[12:]
+	}
+	return s
+}
+
+// Mascarado retorna o documento formatado com parte dos caracteres
+// escondida, para logs e listagens: ***.456.789-** ou **.345.678/****-**.
+func (d Documento) Mascarado() string {
+	// inicio e fim delimitam o trecho de Formatado que continua visível.
+	var inicio, fim int
+	switch d.Tipo() {
+	case TipoCPF:
+		inicio, fim = 3, 11
+	case TipoCNPJ:
+		inicio, fim = 2, 10
+	default:
+		return ""
+	}
+
+	formatado := []byte(d.Formatado())
+	for i, c := range formatado {
+		if (i < inicio || i >= fim) && c != '.' && c != '/' && c != '-' {
+			formatado[i] = '*'
+		}
+	}
+	return string(formatado)
+}
+
+// String retorna o documento mascarado, para que ele não apareça inteiro
+// quando formatado em logs e mensagens de erro.
+func (d Documento) String() string {
+	return d.Mascarado()
+}
+
+// cpfValido confere os dois dígitos verificadores (módulo 11) de um CPF
+// com 11 dígitos. Sequências repetidas, como 111.111.111-11, são recusadas.
+func cpfValido(numero string) bool {
+	if !somenteDigitos(numero) || strings.Count(numero, numero[:1]) == len(numero) {
+		return false
+	}
+	return digitoVerificador(numero[:9], 10) == numero[9] &&
+		digitoVerificador(numero[:10], 11) == numero[10]
+}
+
+// cnpjValido confere os dois dígitos verificadores de um CNPJ. Os 12
+// primeiros caracteres podem ser letras maiúsculas, que valem seu código
+// ASCII menos 48, como no CNPJ alfanumérico; os verificadores são dígitos.
+func cnpjValido(numero string) bool {
+	for _, c := range numero[:12] {
+		if (c < '0' || c > '9') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	if !somenteDigitos(numero[12:]) || strings.Count(numero, numero[:1]) == len(numero) {
+		return false
+	}
+	return digitoVerificador(numero[:12], 0) == numero[12] &&
+		digitoVerificador(numero[:13], 0) == numero[13]
+}
+
+// digitoVerificador calcula o dígito módulo 11 de base. Com pesoInicial, os
+// pesos descem de pesoInicial até 2 (CPF); com zero, vão de 2 a 9 da direita
+// para a esquerda, recomeçando em 2 (CNPJ).
+func digitoVerificador(base string, pesoInicial int) byte {
+	soma := 0
+	for i := range base {
+		peso := pesoInicial - i
+		if pesoInicial == 0 {
+			peso = 2 + (len(base)-1-i)%8
+		}
+		soma += int(base[i]-'0') * peso
+	}
+	resto := soma % 11
+	if resto < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - resto)
+}
+
+func somenteDigitos(texto string) bool {
+	for _, c := range texto {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewDocumento(t *testing.T) {
+	casos := []struct {
+		nome      string
+		texto     string
+		documento Documento
+		tipo      TipoDocumento
+		erro      error
+	}{
+		{nome: "vazio", texto: "", documento: "", tipo: ""},
+		{nome: "CPF com pontuação", texto: "529.982.247-25", documento: "52998224725", tipo: TipoCPF},
+		{nome: "CPF sem pontuação", texto: "11144477735", documento: "11144477735", tipo: TipoCPF},
+		{nome: "CPF com primeiro dígito zero", texto: "390.533.447-05", documento: "39053344705", tipo: TipoCPF},
+		{nome: "CPF com primeiro verificador errado", texto: "529.982.247-35", erro: ErrDocumentoInvalido},
+		{nome: "CPF com segundo verificador errado", texto: "529.982.247-26", erro: ErrDocumentoInvalido},
+		{nome: "CPF repetido", texto: "111.111.111-11", erro: ErrDocumentoInvalido},
+		{nome: "CPF com letra", texto: "52998224A25", erro: ErrDocumentoInvalido},
+		{nome: "CNPJ com pontuação", texto: "11.222.333/0001-81", documento: "11222333000181", tipo: TipoCNPJ},
+		{nome: "CNPJ sem pontuação", texto: "11444777000161", documento: "11444777000161", tipo: TipoCNPJ},
+		{nome: "CNPJ alfanumérico", texto: "12.ABC.345/01DE-35", documento: "12ABC34501DE35", tipo: TipoCNPJ},
+		{nome: "CNPJ alfanumérico em minúsculas", texto: "12.abc.345/01de-35", documento: "12ABC34501DE35", tipo: TipoCNPJ},
+		{nome: "CNPJ com primeiro verificador errado", texto: "11.222.333/0001-91", erro: ErrDocumentoInvalido},
+		{nome: "CNPJ com segundo verificador errado", texto: "11.222.333/0001-82", erro: ErrDocumentoInvalido},
+		{nome: "CNPJ alfanumérico com verificador errado", texto: "12.ABC.345/01DE-36", erro: ErrDocumentoInvalido},
+		{nome: "CNPJ com letra no verificador", texto: "12.ABC.345/01DE-3A", erro: ErrDocumentoInvalido},
+		{nome: "CNPJ repetido", texto: "00.000.000/0000-00", erro: ErrDocumentoInvalido},
+		{nome: "CNPJ com símbolo", texto: "12.AB#.345/01DE-35", erro: ErrDocumentoInvalido},
+		{nome: "tamanho de nenhum dos dois", texto: "123456789", erro: ErrDocumentoInvalido},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			documento, err := NewDocumento(c.texto)
+			if !errors.Is(err, c.erro) {
+				t.Fatalf("erro = %v, esperado %v", err, c.erro)
+			}
+			if documento != c.documento || documento.Tipo() != c.tipo {
+				t.Errorf("documento = %q (%q), esperado %q (%q)", string(documento), documento.Tipo(), string(c.documento), c.tipo)
+			}
+		})
+	}
+}
+
+func TestDocumentoFormatadoEMascarado(t *testing.T) {
+	casos := []struct {
+		documento Documento
+		formatado string
+		mascarado string
+	}{
+		{"52998224725", "529.982.247-25", "***.982.247-**"},
+		{"11222333000181", "11.222.333/0001-81", "**.222.333/****-**"},
+		{"12ABC34501DE35", "12.ABC.345/01DE-35", "**.ABC.345/****-**"},
+		{"", "", ""},
+	}
+	for _, c := range casos {
+		t.Run(c.formatado, func(t *testing.T) {
+			if got := c.documento.Formatado(); got != c.formatado {
+				t.Errorf("Formatado = %q, esperado %q", got, c.formatado)
+			}
+			if got := c.documento.Mascarado(); got != c.mascarado {
+				t.Errorf("Mascarado = %q, esperado %q", got, c.mascarado)
+			}
+			// Em logs, o documento nunca aparece inteiro.
+			if got := c.documento.String(); got != c.mascarado {
+				t.Errorf("String = %q, esperado %q", got, c.mascarado)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package domain
+
+import "strings"
+
+// CriterioDuplicidade é o motivo pelo qual clientes parecem ser a mesma pessoa.
+type CriterioDuplicidade string
+
+// Os critérios de detecção de duplicados. Documentos repetidos não são um
+// critério: o índice único uq_clientes_documento (migração 0009) já impede
+// dois clientes ativos com o mesmo CPF ou CNPJ, então esse grupo seria
+// sempre vazio.
+const (
+	// CriterioEmail agrupa e-mails iguais depois de EmailNormalizado.
+	CriterioEmail CriterioDuplicidade = "email"
+	// CriterioNomeECEP agrupa clientes com nomes parecidos (NomesParecidos)
+	// e algum endereço no mesmo CEP.
+	CriterioNomeECEP CriterioDuplicidade = "nome_cep"
+)
+
+// MaxClientesPorGrupoDuplicado limita os clientes carregados de cada grupo:
+// um CEP de prédio ou o CEP geral de uma cidade pode reunir milhares de
+// clientes. Ficam os cadastros mais antigos; os demais aparecem depois que
+// esses forem mesclados.
+const MaxClientesPorGrupoDuplicado = 50
+
+// GrupoDuplicado são clientes ativos que provavelmente são a mesma pessoa,
+// do cadastro mais antigo para o mais novo. Chave é o valor compartilhado
+// (e-mail normalizado ou CEP só com dígitos) e ordena as páginas da busca.
+type GrupoDuplicado struct {
+	Criterio CriterioDuplicidade
+	Chave    string
+	Clientes []*Cliente
+}
+
+// EmailNormalizado reduz o e-mail à forma usada na detecção de duplicados:
+// minúsculo, sem espaços e sem o sufixo "+etiqueta" do usuário
+// (maria+loja@exemplo.com e Maria@exemplo.com são o mesmo endereço).
+func EmailNormalizado(email string) string {
+	usuario, dominio, ok := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	if !ok {
+		return usuario
+	}
+	usuario, _, _ = strings.Cut(usuario, "+")
+	return usuario + "@" + dominio
+}
+
+// NomesParecidos compara nomes sem diferenciar maiúsculas e acentos. São
+// parecidos os nomes iguais, os que diferem por poucos erros de digitação
+// (até um a cada cinco letras) e os com o mesmo primeiro e último nome, como
+// "Maria Silva" e "Maria da Silva".
+func NomesParecidos(a, b string) bool {
+	partesA := strings.Fields(semAcentos(a))
+	partesB := strings.Fields(semAcentos(b))
+	if len(partesA) == 0 || len(partesB) == 0 {
+		return false
+	}
+
+	nomeA, nomeB := strings.Join(partesA, " "), strings.Join(partesB, " ")
+	if distanciaEdicao(nomeA, nomeB) <= max(len([]rune(nomeA)), len([]rune(nomeB)))/5 {
+		return true
+	}
+	return len(partesA) > 1 && len(partesB) > 1 &&
+		partesA[0] == partesB[0] && partesA[len(partesA)-1] == partesB[len(partesB)-1]
+}
+
+// distanciaEdicao é a distância de Levenshtein entre os textos, em runas.
+func distanciaEdicao(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	anterior := make([]int, len(rb)+1)
+	atual := make([]int, len(rb)+1)
+	for j := range anterior {
+		anterior[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		atual[0] = i
+		for j := 1; j <= len(rb); j++ {
+			custo := 1
+			if ra[i-1] == rb[j-1] {
+				custo = 0
+			}
+			atual[j] = min(anterior[j]+1, atual[j-1]+1, anterior[j-1]+custo)
+		}
+		anterior, atual = atual, anterior
+	}
+	return anterior[len(rb)]
+}
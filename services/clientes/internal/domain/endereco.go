@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TipoEndereco indica para que o endereço é usado.
+type TipoEndereco string
+
+// Os possíveis tipos de endereço.
+const (
+	TipoEntrega  TipoEndereco = "entrega"
+	TipoCobranca TipoEndereco = "cobranca"
+)
+
+// Endereco pertence ao agregado de Cliente.
+// Cada cliente tem no máximo um endereço padrão por tipo.
+type Endereco struct {
+	ID     int64
+	Tipo   TipoEndereco
+	Rua    string
+	Cidade string
+	Estado string
+	CEP    string
+	Padrao bool
+}
+
+// AplicarLocalidade padroniza o CEP (01310-100) e a UF (sigla em
+// maiúsculas) e completa a cidade e a UF não informadas com a localidade do
+// CEP. Informadas, elas precisam corresponder à localidade, quando ela é
+// conhecida; caso contrário o resultado é ErrCEPDivergente. A cidade passa a
+// ter a grafia da localidade.
+func (e *Endereco) AplicarLocalidade(l Localidade) error {
+	if cep := NormalizarCEP(e.CEP); cep != "" {
+		e.CEP = cep
+	}
+	e.Estado = strings.ToUpper(strings.TrimSpace(e.Estado))
+	e.Cidade = strings.TrimSpace(e.Cidade)
+
+	switch {
+	case e.Estado == "":
+		e.Estado = l.Estado
+	case l.Estado != "" && e.Estado != l.Estado:
+		return fmt.Errorf("%w: o CEP %s é de %s, não de %s", ErrCEPDivergente, e.CEP, l.Estado, e.Estado)
+	}
+
+	switch {
+	case e.Cidade == "" && l.Cidade == "":
+		return fmt.Errorf("%w: informe a cidade, que não foi identificada pelo CEP", ErrEnderecoInvalido)
+	case l.Cidade == "":
+		// A localidade não conhece a cidade; vale a informada.
+	case e.Cidade == "" || MesmaCidade(e.Cidade, l.Cidade):
+		e.Cidade = l.Cidade // Grafia da base, com acentos.
+	default:
+		return fmt.Errorf("%w: o CEP %s é de %s, não de %s", ErrCEPDivergente, e.CEP, l.Cidade, e.Cidade)
+	}
+	return nil
+}
+
+// validar confere os campos obrigatórios, o tipo do endereço, o formato do
+// CEP e a sigla da UF. Endereços sem tipo são considerados de entrega.
+func (e *Endereco) validar() error {
+	if e.Tipo == "" {
+		e.Tipo = TipoEntrega
+	}
+	if e.Tipo != TipoEntrega && e.Tipo != TipoCobranca {
+		return ErrEnderecoInvalido
+	}
+	if strings.TrimSpace(e.Rua) == "" || strings.TrimSpace(e.Cidade) == "" ||
+		strings.TrimSpace(e.Estado) == "" || strings.TrimSpace(e.CEP) == "" {
+		return ErrEnderecoInvalido
+	}
+	if NormalizarCEP(e.CEP) == "" {
+		return fmt.Errorf("%w: CEP deve ter 8 dígitos", ErrEnderecoInvalido)
+	}
+	if _, ok := UFs[e.Estado]; !ok {
+		return fmt.Errorf("%w: estado deve ser a sigla de uma UF", ErrEnderecoInvalido)
+	}
+	return nil
+}
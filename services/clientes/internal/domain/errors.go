@@ -0,0 +1,20 @@
+package domain
+
+import "errors"
+
+// Erros que podem ser retornados pela camada de domínio.
+var (
+	ErrClienteNaoEncontrado  = errors.New("cliente não encontrado")
+	ErrClienteInvalido       = errors.New("dados do cliente inválidos")
+	ErrEmailEmUso            = errors.New("e-mail já está em uso por outro cliente")
+	ErrDocumentoInvalido     = errors.New("CPF ou CNPJ inválido")
+	ErrDocumentoEmUso        = errors.New("CPF ou CNPJ já está em uso por outro cliente")
+	ErrEnderecoNaoEncontrado = errors.New("endereço não encontrado")
+	ErrEnderecoInvalido      = errors.New("dados do endereço inválidos")
+	ErrCEPNaoEncontrado      = errors.New("CEP não encontrado")
+	ErrCEPDivergente         = errors.New("cidade ou estado não correspondem ao CEP")
+	ErrFiltroInvalido        = errors.New("filtro de clientes inválido")
+	ErrPedidosIndisponivel   = errors.New("serviço de pedidos indisponível")
+	ErrConflitoDeVersao      = errors.New("cliente alterado por outra requisição")
+	ErrMesclagemInvalida     = errors.New("um cliente não pode ser mesclado com ele mesmo")
+)
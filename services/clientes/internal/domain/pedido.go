@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"ecommerce/pkg/money"
+	"time"
+)
+
+// PedidoDoCliente é a visão, no serviço de clientes, de um pedido feito pelo
+// cliente. Os pedidos pertencem ao serviço de pedidos e são apenas consultados.
+type PedidoDoCliente struct {
+	ID           string
+	Status       string
+	Total        money.Money
+	Itens        []ItemPedido
+	CriadoEm     time.Time
+	AtualizadoEm time.Time
+}
+
+// ItemPedido é um item de PedidoDoCliente.
+type ItemPedido struct {
+	ProdutoID  string
+	Nome       string
+	Preco      money.Money
+	Quantidade int
+}
+
+// FiltroPedidosCliente restringe e pagina os pedidos de um cliente.
+// Cursor é o next_cursor devolvido pelo serviço de pedidos na página anterior.
+type FiltroPedidosCliente struct {
+	Status string
+	Cursor string
+	Limite int
+}
+
+// PaginaPedidosCliente é uma página dos pedidos de um cliente.
+// NextCursor é nil na última página.
+type PaginaPedidosCliente struct {
+	Pedidos    []PedidoDoCliente
+	NextCursor *string
+}
+
+// PedidoGateway consulta os pedidos de um cliente no serviço de pedidos.
+type PedidoGateway interface {
+	ListarPorCliente(ctx context.Context, clienteID string, filtro FiltroPedidosCliente) (*PaginaPedidosCliente, error)
+}
@@ -1,9 +1,78 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ClienteRepository define a interface para interações com o banco de dados de clientes.
 type ClienteRepository interface {
 	Save(ctx context.Context, cliente *Cliente) error
-	FindAll(ctx context.Context) ([]*Cliente, error)
+	List(ctx context.Context, filtro FiltroClientes) (*PaginaClientes, error)
+	FindByID(ctx context.Context, id string) (*Cliente, error)
+	// Update, Delete e as alterações de endereço só gravam se o cliente ainda
+	// estiver na versão em que foi lido (Delete: na versão informada, zero
+	// dispensa); caso contrário retornam ErrConflitoDeVersao. Em caso de
+	// sucesso, cliente.Versao avança.
+	Update(ctx context.Context, cliente *Cliente) error
+	Delete(ctx context.Context, id string, versao int) error
+	AddEndereco(ctx context.Context, cliente *Cliente, endereco *Endereco) error
+	UpdateEndereco(ctx context.Context, cliente *Cliente, endereco *Endereco) error
+	DeleteEndereco(ctx context.Context, cliente *Cliente, enderecoID int64) error
+	// Mesclar grava a absorção de origem por destino (Cliente.Absorver):
+	// exclui origem, passa seus endereços para destino e registra o evento
+	// cliente.mesclado. Os dois clientes precisam estar nas versões lidas.
+	Mesclar(ctx context.Context, destino, origem *Cliente) error
+	// BuscarSuspeitosDeDuplicidade retorna até limite grupos do critério com
+	// mais de um cliente ativo, em ordem de Chave, a partir da primeira chave
+	// depois de apos ("" começa do início). Em CriterioNomeECEP o grupo é o
+	// CEP inteiro, sem comparar os nomes. Cada grupo traz no máximo
+	// MaxClientesPorGrupoDuplicado clientes.
+	BuscarSuspeitosDeDuplicidade(ctx context.Context, criterio CriterioDuplicidade, apos string, limite int) ([]GrupoDuplicado, error)
+}
+
+// OrdemClientes é o campo pelo qual a listagem de clientes é ordenada.
+// O ID desempata clientes com o mesmo valor.
+type OrdemClientes string
+
+// As ordenações possíveis da listagem.
+const (
+	OrdemPorNome     OrdemClientes = "nome"
+	OrdemPorCriadoEm OrdemClientes = "criado_em"
+)
+
+// CursorCliente é a posição de um cliente na listagem: o valor do campo de
+// ordenação e o ID do último cliente da página.
+type CursorCliente struct {
+	Ordem    OrdemClientes
+	Nome     string
+	CriadoEm time.Time
+	ID       string
+}
+
+// FiltroClientes restringe, ordena e pagina a listagem de clientes. Campos
+// de busca vazios não filtram. A página é indicada por Apos (cursor) ou por
+// Offset, nunca pelos dois.
+type FiltroClientes struct {
+	NomePrefixo string // Início do nome, sem diferenciar maiúsculas.
+	Email       string // E-mail exato, sem diferenciar maiúsculas.
+	CEP         string // Somente dígitos.
+	Cidade      string // Cidade exata, sem diferenciar maiúsculas.
+	Documento   Documento
+
+	Ordem       OrdemClientes
+	Decrescente bool
+
+	Apos        *CursorCliente
+	Offset      int
+	Limite      int
+	ContarTotal bool // Calcula o total de clientes que atendem ao filtro.
+}
+
+// PaginaClientes é uma página da listagem. Proximo é nil na última página e
+// Total só é preenchido quando pedido no filtro.
+type PaginaClientes struct {
+	Clientes []*Cliente
+	Proximo  *CursorCliente
+	Total    *int
 }
@@ -0,0 +1,99 @@
+package cep
+
+import (
+	"context"
+	"ecommerce/clientes/internal/domain"
+	"errors"
+	"testing"
+)
+
+func TestFaixasDeCEP(t *testing.T) {
+	faixas, err := NewFaixasDeCEP()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	casos := []struct {
+		cep        string
+		localidade domain.Localidade
+		erro       error
+	}{
+		{cep: "01310100", localidade: domain.Localidade{Cidade: "São Paulo", Estado: "SP"}},
+		{cep: "01000000", localidade: domain.Localidade{Cidade: "São Paulo", Estado: "SP"}},
+		{cep: "05999999", localidade: domain.Localidade{Cidade: "São Paulo", Estado: "SP"}},
+		{cep: "06000000", localidade: domain.Localidade{Estado: "SP"}}, // Grande SP, fora da capital.
+		{cep: "13010000", localidade: domain.Localidade{Estado: "SP"}},
+		{cep: "20040020", localidade: domain.Localidade{Cidade: "Rio de Janeiro", Estado: "RJ"}},
+		{cep: "28999999", localidade: domain.Localidade{Estado: "RJ"}},
+		{cep: "70040010", localidade: domain.Localidade{Cidade: "Brasília", Estado: "DF"}},
+		{cep: "72800000", localidade: domain.Localidade{Estado: "GO"}},
+		{cep: "73000000", localidade: domain.Localidade{Cidade: "Brasília", Estado: "DF"}},
+		{cep: "68900000", localidade: domain.Localidade{Cidade: "Macapá", Estado: "AP"}},
+		{cep: "68899999", localidade: domain.Localidade{Estado: "PA"}},
+		{cep: "69300000", localidade: domain.Localidade{Cidade: "Boa Vista", Estado: "RR"}},
+		{cep: "69400000", localidade: domain.Localidade{Estado: "AM"}},
+		{cep: "90010000", localidade: domain.Localidade{Cidade: "Porto Alegre", Estado: "RS"}},
+		{cep: "99999999", localidade: domain.Localidade{Estado: "RS"}},
+		{cep: "00999999", erro: domain.ErrCEPNaoEncontrado},
+		{cep: "0131010", erro: domain.ErrCEPNaoEncontrado},
+		{cep: "01310-100", erro: domain.ErrCEPNaoEncontrado},
+		{cep: "+1310100", erro: domain.ErrCEPNaoEncontrado},
+		{cep: "", erro: domain.ErrCEPNaoEncontrado},
+	}
+	for _, c := range casos {
+		t.Run(c.cep, func(t *testing.T) {
+			localidade, err := faixas.Consultar(context.Background(), c.cep)
+			if !errors.Is(err, c.erro) {
+				t.Fatalf("erro = %v, esperado %v", err, c.erro)
+			}
+			if localidade != c.localidade {
+				t.Errorf("localidade = %+v, esperado %+v", localidade, c.localidade)
+			}
+		})
+	}
+}
+
+// TestFaixasDeCEPCobremTodasAsUFs garante que a base embutida identifica
+// todas as UFs e tem a capital de cada uma.
+func TestFaixasDeCEPCobremTodasAsUFs(t *testing.T) {
+	faixas, err := NewFaixasDeCEP()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ufs, capitais := make(map[string]bool), make(map[string]bool)
+	for _, fx := range faixas.faixas {
+		ufs[fx.localidade.Estado] = true
+		if fx.localidade.Cidade != "" {
+			capitais[fx.localidade.Estado] = true
+		}
+	}
+	for uf := range domain.UFs {
+		if !ufs[uf] || !capitais[uf] {
+			t.Errorf("%s: faixa da UF %v, faixa da capital %v", uf, ufs[uf], capitais[uf])
+		}
+	}
+}
+
+func TestLocalidadesEmMemoria(t *testing.T) {
+	consulta := NewLocalidadesEmMemoria()
+	consulta.Adicionar("01310100", domain.Localidade{Cidade: "São Paulo", Estado: "SP"})
+
+	casos := []struct {
+		cep        string
+		localidade domain.Localidade
+		erro       error
+	}{
+		{cep: "01310100", localidade: domain.Localidade{Cidade: "São Paulo", Estado: "SP"}},
+		{cep: "01310-100", erro: domain.ErrCEPNaoEncontrado},
+		{cep: "20040020", erro: domain.ErrCEPNaoEncontrado},
+	}
+	for _, c := range casos {
+		t.Run(c.cep, func(t *testing.T) {
+			localidade, err := consulta.Consultar(context.Background(), c.cep)
+			if !errors.Is(err, c.erro) || localidade != c.localidade {
+				t.Errorf("Consultar = %+v, %v; esperado %+v, %v", localidade, err, c.localidade, c.erro)
+			}
+		})
+	}
+}
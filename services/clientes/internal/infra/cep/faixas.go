@@ -0,0 +1,82 @@
+// Package cep implementa a consulta de localidade por CEP (domain.ConsultaCEP).
+package cep
+
+import (
+	"context"
+	"ecommerce/clientes/internal/domain"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed faixas.csv
+var faixasCSV string
+
+// faixa é um intervalo de CEPs, como números de 8 dígitos, de uma localidade.
+type faixa struct {
+	inicio, fim int
+	localidade  domain.Localidade
+}
+
+// FaixasDeCEP consulta a localidade de um CEP em uma base de faixas embutida
+// no serviço, sem chamadas externas. A base cobre todas as UFs e as capitais:
+// fora das capitais, só a UF é identificada e a cidade informada pelo cliente
+// é aceita sem conferência.
+type FaixasDeCEP struct {
+	faixas []faixa // Das mais estreitas para as mais largas.
+}
+
+// NewFaixasDeCEP carrega a base embutida.
+func NewFaixasDeCEP() (*FaixasDeCEP, error) {
+	leitor := csv.NewReader(strings.NewReader(faixasCSV))
+	leitor.Comment = '#'
+	leitor.FieldsPerRecord = 4
+
+	linhas, err := leitor.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("base de CEPs inválida: %w", err)
+	}
+
+	faixas := make([]faixa, 0, len(linhas))
+	for _, linha := range linhas {
+		inicio, errInicio := strconv.Atoi(linha[0])
+		fim, errFim := strconv.Atoi(linha[1])
+		if errInicio != nil || errFim != nil || inicio > fim {
+			return nil, fmt.Errorf("base de CEPs inválida: faixa %s-%s", linha[0], linha[1])
+		}
+		if _, ok := domain.UFs[linha[2]]; !ok {
+			return nil, fmt.Errorf("base de CEPs inválida: UF %q", linha[2])
+		}
+		faixas = append(faixas, faixa{inicio: inicio, fim: fim, localidade: domain.Localidade{Estado: linha[2], Cidade: linha[3]}})
+	}
+
+	// A faixa mais estreita que contém o CEP é a mais específica (a da cidade).
+	// Com a mesma largura, como a do DF e a de Brasília, vale a que tem cidade.
+	sort.SliceStable(faixas, func(i, j int) bool {
+		li, lj := faixas[i].fim-faixas[i].inicio, faixas[j].fim-faixas[j].inicio
+		if li != lj {
+			return li < lj
+		}
+		return faixas[i].localidade.Cidade != "" && faixas[j].localidade.Cidade == ""
+	})
+	return &FaixasDeCEP{faixas: faixas}, nil
+}
+
+// Consultar implementa domain.ConsultaCEP.
+func (f *FaixasDeCEP) Consultar(_ context.Context, cep string) (domain.Localidade, error) {
+	// ParseUint, ao contrário de Atoi, não aceita sinal: "+1310100" não é CEP.
+	numero, err := strconv.ParseUint(cep, 10, 32)
+	if err != nil || len(cep) != 8 {
+		return domain.Localidade{}, domain.ErrCEPNaoEncontrado
+	}
+
+	for _, fx := range f.faixas {
+		if int(numero) >= fx.inicio && int(numero) <= fx.fim {
+			return fx.localidade, nil
+		}
+	}
+	return domain.Localidade{}, domain.ErrCEPNaoEncontrado
+}
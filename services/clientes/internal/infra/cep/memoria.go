@@ -0,0 +1,37 @@
+package cep
+
+import (
+	"context"
+	"ecommerce/clientes/internal/domain"
+	"sync"
+)
+
+// LocalidadesEmMemoria é uma consulta de CEP falsa, para testes e
+// desenvolvimento local: só conhece os CEPs registrados com Adicionar.
+type LocalidadesEmMemoria struct {
+	mu          sync.RWMutex
+	localidades map[string]domain.Localidade
+}
+
+// NewLocalidadesEmMemoria cria a consulta falsa sem CEPs.
+func NewLocalidadesEmMemoria() *LocalidadesEmMemoria {
+	return &LocalidadesEmMemoria{localidades: make(map[string]domain.Localidade)}
+}
+
+// Adicionar registra a localidade de um CEP com 8 dígitos, sem hífen.
+func (c *LocalidadesEmMemoria) Adicionar(cep string, localidade domain.Localidade) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.localidades[cep] = localidade
+}
+
+// Consultar implementa domain.ConsultaCEP.
+func (c *LocalidadesEmMemoria) Consultar(_ context.Context, cep string) (domain.Localidade, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	localidade, ok := c.localidades[cep]
+	if !ok {
+		return domain.Localidade{}, domain.ErrCEPNaoEncontrado
+	}
+	return localidade, nil
+}
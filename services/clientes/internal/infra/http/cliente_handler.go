@@ -2,9 +2,18 @@ package http
 
 import (
 	"ecommerce/clientes/internal/application"
-	_ "ecommerce/clientes/internal/domain"
+	"ecommerce/clientes/internal/domain"
+	"ecommerce/pkg/common/etag"
+	"ecommerce/pkg/common/mergepatch"
+	"ecommerce/pkg/common/problema"
+	"ecommerce/pkg/common/validacao"
 	"encoding/json"
+	"fmt"
+	"mime"
 	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
 )
 
 // ClienteHandler lida com as requisições HTTP para clientes.
@@ -21,52 +30,262 @@ func NewClienteHandler(service *application.ClienteService) *ClienteHandler {
 
 // @Summary Cria um novo cliente
 // @Description Cria um novo cliente com seus dados e endereços.
+// @Description Cidade e estado (sigla da UF) dos endereços podem ser omitidos para serem preenchidos a partir do CEP.
 // @Tags clientes
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Chave para repetir a requisição sem criar outro cliente"
 // @Param cliente body application.ClienteInput true "Dados para criação do cliente"
 // @Success 201 {object} domain.Cliente
-// @Failure 400 {string} string "Corpo da requisição inválido"
-// @Failure 500 {string} string "Erro interno ao criar cliente"
+// @Failure 400 {object} problema.Problema "Corpo da requisição inválido"
+// @Failure 409 {object} problema.Problema "E-mail ou CPF/CNPJ já em uso por outro cliente, ou Idempotency-Key ainda em andamento"
+// @Failure 422 {object} problema.Problema "Dados do cliente ou do endereço inválidos, ou Idempotency-Key já usada com outro corpo"
+// @Failure 500 {object} problema.Problema "Erro interno ao criar cliente"
 // @Router /clientes [post]
 func (h *ClienteHandler) CriarClienteHandler(w http.ResponseWriter, r *http.Request) {
 	// Decodifica o corpo da requisição JSON para o nosso DTO de entrada.
 	var input application.ClienteInput
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		http.Error(w, "Corpo da requisição inválido", http.StatusBadRequest)
+	if err := validacao.Decodificar(w, r, &input); err != nil {
+		escreverErro(w, r, err)
 		return
 	}
 
 	// Chama o serviço da camada de aplicação com os dados recebidos.
 	cliente, err := h.service.CriarCliente(r.Context(), input)
 	if err != nil {
-		http.Error(w, "Erro ao criar cliente: "+err.Error(), http.StatusInternalServerError)
+		escreverErro(w, r, err)
 		return
 	}
 
 	// Se tudo deu certo, codifica o cliente criado como JSON e envia na resposta.
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag.Formatar(cliente.Versao))
 	w.WriteHeader(http.StatusCreated) // Status 201 Created
 	json.NewEncoder(w).Encode(cliente)
 }
 
-// @Summary Lista todos os clientes
-// @Description Retorna uma lista de todos os clientes cadastrados com seus endereços.
+// @Summary Lista clientes
+// @Description Retorna uma página de clientes com seus endereços, com busca e ordenação.
+// @Description A página vem do cursor (next_cursor da resposta anterior, null na última página) ou de page/size.
+// @Description Os CPFs e CNPJs vêm mascarados; o documento completo só aparece na consulta do cliente por ID.
 // @Tags clientes
 // @Produce json
-// @Success 200 {array} domain.Cliente
-// @Failure 500 {string} string "Erro interno ao listar clientes"
+// @Param nome query string false "Início do nome, sem diferenciar maiúsculas"
+// @Param email query string false "E-mail exato, sem diferenciar maiúsculas"
+// @Param cep query string false "CEP de algum endereço do cliente"
+// @Param cidade query string false "Cidade de algum endereço do cliente"
+// @Param documento query string false "CPF ou CNPJ exato, com ou sem pontuação"
+// @Param sort query string false "Ordenação (padrão -criado_em)" Enums(nome, -nome, criado_em, -criado_em)
+// @Param cursor query string false "next_cursor da página anterior"
+// @Param page query int false "Número da página, a partir de 1 (alternativa ao cursor)"
+// @Param size query int false "Tamanho da página (1 a 100, padrão 20)"
+// @Param total query bool false "Inclui o total de clientes encontrados"
+// @Success 200 {object} application.PaginaClientesOutput
+// @Failure 400 {object} problema.Problema "Busca, ordenação ou página inválida"
+// @Failure 500 {object} problema.Problema "Erro interno ao listar clientes"
 // @Router /clientes [get]
 func (h *ClienteHandler) ListarClientesHandler(w http.ResponseWriter, r *http.Request) {
+	input, err := lerFiltrosListagem(r)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
 	// Chama o serviço da camada de aplicação.
-	clientes, err := h.service.ListarClientes(r.Context())
+	pagina, err := h.service.ListarClientes(r.Context(), input)
 	if err != nil {
-		http.Error(w, "Erro ao listar clientes: "+err.Error(), http.StatusInternalServerError)
+		escreverErro(w, r, err)
 		return
 	}
 
-	// Codifica o slice de clientes como JSON e envia na resposta.
+	// Codifica a página de clientes como JSON e envia na resposta.
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK) // Status 200 OK
-	json.NewEncoder(w).Encode(clientes)
+	json.NewEncoder(w).Encode(pagina)
+}
+
+// lerFiltrosListagem converte os parâmetros de query de GET /clientes.
+func lerFiltrosListagem(r *http.Request) (application.ListarClientesInput, error) {
+	q := r.URL.Query()
+	input := application.ListarClientesInput{
+		Nome:      q.Get("nome"),
+		Email:     q.Get("email"),
+		CEP:       q.Get("cep"),
+		Cidade:    q.Get("cidade"),
+		Documento: q.Get("documento"),
+		Ordem:     q.Get("sort"),
+		Cursor:    q.Get("cursor"),
+	}
+
+	var err error
+	if v := q.Get("page"); v != "" {
+		if input.Pagina, err = strconv.Atoi(v); err != nil {
+			return input, fmt.Errorf("%w: page deve ser um número", domain.ErrFiltroInvalido)
+		}
+	}
+	if v := q.Get("size"); v != "" {
+		if input.Tamanho, err = strconv.Atoi(v); err != nil {
+			return input, fmt.Errorf("%w: size deve ser um número", domain.ErrFiltroInvalido)
+		}
+	}
+	if v := q.Get("total"); v != "" {
+		if input.ComTotal, err = strconv.ParseBool(v); err != nil {
+			return input, fmt.Errorf("%w: total deve ser true ou false", domain.ErrFiltroInvalido)
+		}
+	}
+	return input, nil
+}
+
+// @Summary Busca um cliente por ID
+// @Description Retorna os dados de um cliente específico com seus endereços.
+// @Description A resposta traz a ETag da versão do cliente; com If-None-Match igual a ela, a resposta é 304 sem corpo.
+// @Tags clientes
+// @Produce json
+// @Param id path string true "ID do Cliente (UUID)"
+// @Param If-None-Match header string false "ETag de uma resposta anterior"
+// @Success 200 {object} domain.Cliente
+// @Success 304 "Cliente não mudou desde a ETag informada"
+// @Failure 404 {object} problema.Problema "Cliente não encontrado"
+// @Failure 500 {object} problema.Problema "Erro interno ao buscar cliente"
+// @Router /clientes/{id} [get]
+func (h *ClienteHandler) BuscarClientePorIDHandler(w http.ResponseWriter, r *http.Request) {
+	cliente, err := h.service.BuscarClientePorID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	tag := etag.Formatar(cliente.Versao)
+	w.Header().Set("ETag", tag)
+	if etag.NaoModificado(r, tag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(cliente)
+}
+
+// @Summary Atualiza um cliente
+// @Description Substitui nome, e-mail e documento (CPF ou CNPJ) do cliente; sem documento no corpo, o atual é removido.
+// @Description Endereços não são alterados por esta rota.
+// @Tags clientes
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do Cliente (UUID)"
+// @Param If-Match header string false "ETag da versão do cliente lida pelo chamador"
+// @Param cliente body application.AtualizarClienteInput true "Novos dados do cliente"
+// @Success 200 {object} domain.Cliente
+// @Failure 400 {object} problema.Problema "Corpo da requisição ou If-Match inválido"
+// @Failure 404 {object} problema.Problema "Cliente não encontrado"
+// @Failure 409 {object} problema.Problema "E-mail ou CPF/CNPJ já em uso por outro cliente, ou cliente alterado por outra requisição"
+// @Failure 412 {object} problema.Problema "O cliente não está mais na versão do If-Match"
+// @Failure 422 {object} problema.Problema "Dados do cliente inválidos"
+// @Failure 500 {object} problema.Problema "Erro interno ao atualizar cliente"
+// @Router /clientes/{id} [put]
+func (h *ClienteHandler) AtualizarClienteHandler(w http.ResponseWriter, r *http.Request) {
+	var input application.AtualizarClienteInput
+	if err := validacao.Decodificar(w, r, &input); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	versao, err := etag.VersaoEsperada(r)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	cliente, err := h.service.AtualizarCliente(r.Context(), chi.URLParam(r, "id"), versao, input)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag.Formatar(cliente.Versao))
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(cliente)
+}
+
+// @Summary Altera parcialmente um cliente
+// @Description Aplica um JSON Merge Patch (RFC 7396) sobre nome, e-mail e documento do cliente.
+// @Description "documento": null remove o CPF ou CNPJ.
+// @Tags clientes
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path string true "ID do Cliente (UUID)"
+// @Param If-Match header string false "ETag da versão do cliente lida pelo chamador"
+// @Param patch body application.AtualizarClienteInput true "Campos a alterar"
+// @Success 200 {object} domain.Cliente
+// @Failure 400 {object} problema.Problema "JSON Merge Patch ou If-Match inválido"
+// @Failure 404 {object} problema.Problema "Cliente não encontrado"
+// @Failure 409 {object} problema.Problema "E-mail ou CPF/CNPJ já em uso por outro cliente, ou cliente alterado por outra requisição"
+// @Failure 412 {object} problema.Problema "O cliente não está mais na versão do If-Match"
+// @Failure 415 {object} problema.Problema "Content-Type não suportado"
+// @Failure 422 {object} problema.Problema "Dados do cliente inválidos"
+// @Failure 500 {object} problema.Problema "Erro interno ao atualizar cliente"
+// @Router /clientes/{id} [patch]
+func (h *ClienteHandler) PatchClienteHandler(w http.ResponseWriter, r *http.Request) {
+	// Aceitamos application/json por conveniência, mas a semântica é sempre a de Merge Patch.
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != mergepatch.ContentType && mediaType != "application/json" {
+		problema.Escrever(w, r, problema.Problema{
+			Status:  http.StatusUnsupportedMediaType,
+			Codigo:  "content_type_nao_suportado",
+			Titulo:  "Content-Type não suportado",
+			Detalhe: "Use Content-Type " + mergepatch.ContentType,
+		})
+		return
+	}
+
+	patch, err := validacao.LerCorpo(w, r)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	versao, err := etag.VersaoEsperada(r)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	cliente, err := h.service.AplicarPatchCliente(r.Context(), chi.URLParam(r, "id"), versao, patch)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag.Formatar(cliente.Versao))
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(cliente)
+}
+
+// @Summary Exclui um cliente
+// @Description Faz a exclusão lógica do cliente; ele deixa de ser listado e consultado.
+// @Tags clientes
+// @Param id path string true "ID do Cliente (UUID)"
+// @Param If-Match header string false "ETag da versão do cliente lida pelo chamador"
+// @Success 204
+// @Failure 400 {object} problema.Problema "If-Match inválido"
+// @Failure 404 {object} problema.Problema "Cliente não encontrado"
+// @Failure 412 {object} problema.Problema "O cliente não está mais na versão do If-Match"
+// @Failure 500 {object} problema.Problema "Erro interno ao excluir cliente"
+// @Router /clientes/{id} [delete]
+func (h *ClienteHandler) ExcluirClienteHandler(w http.ResponseWriter, r *http.Request) {
+	versao, err := etag.VersaoEsperada(r)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	if err := h.service.ExcluirCliente(r.Context(), chi.URLParam(r, "id"), versao); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) // Status 204 No Content
 }
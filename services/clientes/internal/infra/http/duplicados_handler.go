@@ -0,0 +1,81 @@
+package http
+
+import (
+	"ecommerce/clientes/internal/application"
+	_ "ecommerce/clientes/internal/domain" // Necessário para o swag resolver os tipos das respostas
+	"ecommerce/pkg/common/etag"
+	_ "ecommerce/pkg/common/problema" // Idem
+	"ecommerce/pkg/common/validacao"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// @Summary Busca clientes duplicados
+// @Description Aponta grupos de clientes ativos que provavelmente são a mesma pessoa: mesmo e-mail sem diferenciar
+// @Description maiúsculas nem "+etiqueta" (criterio "email"), ou nomes parecidos com endereço no mesmo CEP
+// @Description (criterio "nome_cep"). Nada é alterado; para unir um grupo, use POST /clientes/{id}/mesclar.
+// @Description Até 100 grupos por critério e 50 clientes por grupo, os mais antigos; depois das mesclagens, os
+// @Description seguintes aparecem. Documento repetido não é critério: o cadastro já impede dois clientes ativos com
+// @Description o mesmo CPF ou CNPJ. Os CPFs e CNPJs vêm mascarados.
+// @Tags clientes
+// @Produce json
+// @Success 200 {object} application.DuplicadosOutput
+// @Failure 500 {object} problema.Problema "Erro interno ao buscar duplicados"
+// @Router /clientes/duplicados [get]
+func (h *ClienteHandler) BuscarDuplicadosHandler(w http.ResponseWriter, r *http.Request) {
+	duplicados, err := h.service.BuscarDuplicados(r.Context())
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(duplicados)
+}
+
+// @Summary Mescla dois clientes
+// @Description Une ao cliente da URL outro cadastro da mesma pessoa (origem_id). A origem é excluída e seus endereços
+// @Description passam para o cliente da URL, sem substituir os endereços padrão dele; o CPF/CNPJ da origem é herdado
+// @Description se o cliente não tiver um. Os pedidos da origem são transferidos pelo serviço de pedidos a partir do
+// @Description evento cliente.mesclado.
+// @Tags clientes
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do cliente que permanece (UUID)"
+// @Param If-Match header string false "ETag da versão do cliente que permanece"
+// @Param mesclagem body application.MesclarClientesInput true "Cliente absorvido"
+// @Success 200 {object} domain.Cliente
+// @Failure 400 {object} problema.Problema "Corpo da requisição ou If-Match inválido"
+// @Failure 404 {object} problema.Problema "Cliente não encontrado"
+// @Failure 409 {object} problema.Problema "Um dos clientes foi alterado por outra requisição"
+// @Failure 412 {object} problema.Problema "O cliente não está mais na versão do If-Match"
+// @Failure 422 {object} problema.Problema "origem_id inválido ou igual ao cliente da URL"
+// @Failure 500 {object} problema.Problema "Erro interno ao mesclar clientes"
+// @Router /clientes/{id}/mesclar [post]
+func (h *ClienteHandler) MesclarClientesHandler(w http.ResponseWriter, r *http.Request) {
+	var input application.MesclarClientesInput
+	if err := validacao.Decodificar(w, r, &input); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	versao, err := etag.VersaoEsperada(r)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	cliente, err := h.service.MesclarClientes(r.Context(), chi.URLParam(r, "id"), versao, input)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag.Formatar(cliente.Versao))
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(cliente)
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"ecommerce/clientes/internal/application"
+	_ "ecommerce/clientes/internal/domain" // Necessário para o swag resolver os tipos das respostas
+	_ "ecommerce/pkg/common/problema"      // Idem
+	"ecommerce/pkg/common/validacao"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// @Summary Lista os endereços de um cliente
+// @Description Retorna todos os endereços do cliente, com tipo e indicação de padrão.
+// @Tags enderecos
+// @Produce json
+// @Param id path string true "ID do Cliente (UUID)"
+// @Success 200 {array} domain.Endereco
+// @Failure 404 {object} problema.Problema "Cliente não encontrado"
+// @Failure 500 {object} problema.Problema "Erro interno ao listar endereços"
+// @Router /clientes/{id}/enderecos [get]
+func (h *ClienteHandler) ListarEnderecosHandler(w http.ResponseWriter, r *http.Request) {
+	enderecos, err := h.service.ListarEnderecos(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(enderecos)
+}
+
+// @Summary Busca um endereço de um cliente
+// @Description Retorna um endereço específico do cliente.
+// @Tags enderecos
+// @Produce json
+// @Param id path string true "ID do Cliente (UUID)"
+// @Param enderecoId path int true "ID do Endereço"
+// @Success 200 {object} domain.Endereco
+// @Failure 400 {object} problema.Problema "ID do endereço inválido"
+// @Failure 404 {object} problema.Problema "Cliente ou endereço não encontrado"
+// @Failure 500 {object} problema.Problema "Erro interno ao buscar endereço"
+// @Router /clientes/{id}/enderecos/{enderecoId} [get]
+func (h *ClienteHandler) BuscarEnderecoHandler(w http.ResponseWriter, r *http.Request) {
+	enderecoID, ok := enderecoIDDaURL(w, r)
+	if !ok {
+		return
+	}
+
+	endereco, err := h.service.BuscarEndereco(r.Context(), chi.URLParam(r, "id"), enderecoID)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(endereco)
+}
+
+// @Summary Adiciona um endereço a um cliente
+// @Description Inclui um endereço de entrega ou cobrança. Marcá-lo como padrão desmarca o padrão anterior do mesmo tipo.
+// @Description Estado é a sigla da UF. Cidade e estado omitidos são preenchidos a partir do CEP; informados, são conferidos com ele.
+// @Tags enderecos
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do Cliente (UUID)"
+// @Param endereco body application.EnderecoInput true "Dados do endereço"
+// @Success 201 {object} domain.Endereco
+// @Failure 400 {object} problema.Problema "Corpo da requisição inválido"
+// @Failure 404 {object} problema.Problema "Cliente não encontrado"
+// @Failure 409 {object} problema.Problema "Cliente alterado por outra requisição; tente novamente"
+// @Failure 422 {object} problema.Problema "Dados do endereço inválidos, CEP não encontrado ou cidade/estado diferentes dos do CEP"
+// @Failure 500 {object} problema.Problema "Erro interno ao adicionar endereço"
+// @Router /clientes/{id}/enderecos [post]
+func (h *ClienteHandler) AdicionarEnderecoHandler(w http.ResponseWriter, r *http.Request) {
+	var input application.EnderecoInput
+	if err := validacao.Decodificar(w, r, &input); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	endereco, err := h.service.AdicionarEndereco(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated) // Status 201 Created
+	json.NewEncoder(w).Encode(endereco)
+}
+
+// @Summary Altera um endereço de um cliente
+// @Description Substitui os dados do endereço. Marcá-lo como padrão desmarca o padrão anterior do mesmo tipo.
+// @Description Estado é a sigla da UF. Cidade e estado omitidos são preenchidos a partir do CEP; informados, são conferidos com ele.
+// @Tags enderecos
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do Cliente (UUID)"
+// @Param enderecoId path int true "ID do Endereço"
+// @Param endereco body application.EnderecoInput true "Novos dados do endereço"
+// @Success 200 {object} domain.Endereco
+// @Failure 400 {object} problema.Problema "Corpo da requisição inválido"
+// @Failure 404 {object} problema.Problema "Cliente ou endereço não encontrado"
+// @Failure 409 {object} problema.Problema "Cliente alterado por outra requisição; tente novamente"
+// @Failure 422 {object} problema.Problema "Dados do endereço inválidos, CEP não encontrado ou cidade/estado diferentes dos do CEP"
+// @Failure 500 {object} problema.Problema "Erro interno ao alterar endereço"
+// @Router /clientes/{id}/enderecos/{enderecoId} [put]
+func (h *ClienteHandler) AtualizarEnderecoHandler(w http.ResponseWriter, r *http.Request) {
+	enderecoID, ok := enderecoIDDaURL(w, r)
+	if !ok {
+		return
+	}
+
+	var input application.EnderecoInput
+	if err := validacao.Decodificar(w, r, &input); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	endereco, err := h.service.AtualizarEndereco(r.Context(), chi.URLParam(r, "id"), enderecoID, input)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(endereco)
+}
+
+// @Summary Remove um endereço de um cliente
+// @Description Remove o endereço. Pedidos já feitos mantêm a cópia do endereço de entrega.
+// @Tags enderecos
+// @Param id path string true "ID do Cliente (UUID)"
+// @Param enderecoId path int true "ID do Endereço"
+// @Success 204
+// @Failure 400 {object} problema.Problema "ID do endereço inválido"
+// @Failure 404 {object} problema.Problema "Cliente ou endereço não encontrado"
+// @Failure 409 {object} problema.Problema "Cliente alterado por outra requisição; tente novamente"
+// @Failure 500 {object} problema.Problema "Erro interno ao remover endereço"
+// @Router /clientes/{id}/enderecos/{enderecoId} [delete]
+func (h *ClienteHandler) RemoverEnderecoHandler(w http.ResponseWriter, r *http.Request) {
+	enderecoID, ok := enderecoIDDaURL(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoverEndereco(r.Context(), chi.URLParam(r, "id"), enderecoID); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) // Status 204 No Content
+}
+
+// enderecoIDDaURL lê o ID numérico do endereço; em caso de erro já responde 400.
+func enderecoIDDaURL(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	enderecoID, err := strconv.ParseInt(chi.URLParam(r, "enderecoId"), 10, 64)
+	if err != nil {
+		escreverErro(w, r, errEnderecoIDInvalido)
+		return 0, false
+	}
+	return enderecoID, true
+}
@@ -0,0 +1,40 @@
+package http
+
+import (
+	"ecommerce/clientes/internal/domain"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Logger registra as requisições como o middleware.Logger do chi, mas com o
+// CPF ou CNPJ da busca (GET /clientes?documento=) mascarado na URL do log.
+var Logger = middleware.RequestLogger(formatadorMascarado{
+	LogFormatter: &middleware.DefaultLogFormatter{Logger: log.New(os.Stdout, "", log.LstdFlags)},
+})
+
+// formatadorMascarado entrega ao formatador do chi uma cópia da requisição
+// com o documento da query mascarado; a requisição atendida não muda.
+type formatadorMascarado struct {
+	middleware.LogFormatter
+}
+
+func (f formatadorMascarado) NewLogEntry(r *http.Request) middleware.LogEntry {
+	q := r.URL.Query()
+	if !q.Has("documento") {
+		return f.LogFormatter.NewLogEntry(r)
+	}
+
+	mascarado := "***"
+	if documento, err := domain.NewDocumento(q.Get("documento")); err == nil && documento != "" {
+		mascarado = documento.Mascarado()
+	}
+	q.Set("documento", mascarado)
+
+	copia := r.Clone(r.Context())
+	copia.URL.RawQuery = q.Encode()
+	copia.RequestURI = copia.URL.RequestURI()
+	return f.LogFormatter.NewLogEntry(copia)
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"ecommerce/clientes/internal/application"
+	"ecommerce/clientes/internal/domain"
+	_ "ecommerce/pkg/common/problema" // Necessário para o swag resolver os tipos das respostas
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// @Summary Lista os pedidos de um cliente
+// @Description Retorna o histórico de pedidos do cliente, do mais novo para o mais antigo, consultando o serviço de pedidos.
+// @Description Para a próxima página, repita a chamada com cursor igual ao next_cursor recebido (null na última página).
+// @Tags clientes
+// @Produce json
+// @Param id path string true "ID do Cliente (UUID)"
+// @Param status query string false "Somente pedidos neste status" Enums(aguardando_pagamento, pago, enviado, cancelado)
+// @Param limit query int false "Tamanho da página (1 a 100, padrão 20)"
+// @Param cursor query string false "next_cursor da página anterior"
+// @Success 200 {object} application.PedidosDoClienteOutput
+// @Failure 400 {object} problema.Problema "Filtro, limite ou cursor inválido"
+// @Failure 404 {object} problema.Problema "Cliente não encontrado"
+// @Failure 500 {object} problema.Problema "Erro interno ao listar pedidos"
+// @Failure 503 {object} problema.Problema "Serviço de pedidos indisponível"
+// @Router /clientes/{id}/pedidos [get]
+func (h *ClienteHandler) ListarPedidosDoClienteHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	input := application.ListarPedidosDoClienteInput{
+		Status: q.Get("status"),
+		Cursor: q.Get("cursor"),
+	}
+	if v := q.Get("limit"); v != "" {
+		limite, err := strconv.Atoi(v)
+		if err != nil {
+			escreverErro(w, r, fmt.Errorf("%w: limit deve ser um número", domain.ErrFiltroInvalido))
+			return
+		}
+		input.Limite = limite
+	}
+
+	pedidos, err := h.service.ListarPedidosDoCliente(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(pedidos)
+}
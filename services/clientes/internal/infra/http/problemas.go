@@ -0,0 +1,44 @@
+package http
+
+import (
+	"ecommerce/clientes/internal/domain"
+	"ecommerce/pkg/common/etag"
+	"ecommerce/pkg/common/mergepatch"
+	"ecommerce/pkg/common/problema"
+	"errors"
+	"net/http"
+)
+
+// errEnderecoIDInvalido é o ID de endereço da URL que não é um número.
+var errEnderecoIDInvalido = errors.New("ID do endereço inválido")
+
+// problemas associa os erros do domínio de clientes aos códigos estáveis das
+// respostas problem+json. Os códigos fazem parte do contrato da API: não mude
+// um código existente, crie outro.
+var problemas = problema.NewCatalogo().
+	Registrar(etag.ErrIfMatchInvalido, http.StatusBadRequest, "if_match_invalido", "If-Match inválido").
+	Registrar(mergepatch.ErrPatchInvalido, http.StatusBadRequest, "patch_invalido", "JSON Merge Patch inválido").
+	Registrar(domain.ErrFiltroInvalido, http.StatusBadRequest, "filtro_invalido", "Filtro inválido").
+	Registrar(errEnderecoIDInvalido, http.StatusBadRequest, "endereco_id_invalido", "ID do endereço inválido").
+	Registrar(domain.ErrClienteNaoEncontrado, http.StatusNotFound, "cliente_nao_encontrado", "Cliente não encontrado").
+	Registrar(domain.ErrEnderecoNaoEncontrado, http.StatusNotFound, "endereco_nao_encontrado", "Endereço não encontrado").
+	Registrar(domain.ErrEmailEmUso, http.StatusConflict, "email_em_uso", "E-mail já está em uso").
+	Registrar(domain.ErrDocumentoEmUso, http.StatusConflict, "documento_em_uso", "CPF ou CNPJ já está em uso").
+	Registrar(domain.ErrConflitoDeVersao, http.StatusConflict, "conflito_de_versao", "Cliente alterado por outra requisição").
+	Registrar(domain.ErrClienteInvalido, http.StatusUnprocessableEntity, "cliente_invalido", "Dados do cliente inválidos").
+	Registrar(domain.ErrDocumentoInvalido, http.StatusUnprocessableEntity, "documento_invalido", "CPF ou CNPJ inválido").
+	Registrar(domain.ErrEnderecoInvalido, http.StatusUnprocessableEntity, "endereco_invalido", "Dados do endereço inválidos").
+	Registrar(domain.ErrCEPNaoEncontrado, http.StatusUnprocessableEntity, "cep_nao_encontrado", "CEP não encontrado").
+	Registrar(domain.ErrMesclagemInvalida, http.StatusUnprocessableEntity, "mesclagem_invalida", "Mesclagem de clientes inválida").
+	Registrar(domain.ErrCEPDivergente, http.StatusUnprocessableEntity, "cep_divergente", "Cidade ou estado não correspondem ao CEP").
+	Registrar(domain.ErrPedidosIndisponivel, http.StatusServiceUnavailable, "pedidos_indisponivel", "Serviço de pedidos indisponível")
+
+// escreverErro responde err como problem+json. O conflito de versão é 412
+// quando a requisição trouxe If-Match (ver etag.StatusConflito).
+func escreverErro(w http.ResponseWriter, r *http.Request, err error) {
+	p := problemas.Problema(r, err)
+	if errors.Is(err, domain.ErrConflitoDeVersao) {
+		p.Status = etag.StatusConflito(r)
+	}
+	problema.Escrever(w, r, p)
+}
@@ -0,0 +1,70 @@
+package pedidos
+
+import (
+	"bytes"
+	"context"
+	"ecommerce/pkg/circuitbreaker"
+	"ecommerce/pkg/common/assinatura"
+	"ecommerce/pkg/outbox"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPEventosPublisher entrega os eventos da outbox de clientes ao serviço de
+// pedidos, por POST /eventos/clientes. É o Publisher do relay: uma resposta
+// fora de 2xx, ou o serviço fora do ar, deixa o evento na outbox para nova
+// tentativa, então nada se perde com o serviço de pedidos parado. O serviço de
+// pedidos ignora os tipos que não lhe interessam e tolera repetições. Cada
+// requisição é assinada com o segredo compartilhado (pkg/common/assinatura).
+//
+// Com o circuito aberto, o restante do lote falha sem esperar o timeout: o
+// relay publica dentro da transação que segura o lote.
+type HTTPEventosPublisher struct {
+	url     string
+	segredo assinatura.Segredo
+	client  *http.Client
+	breaker *circuitbreaker.CircuitBreaker
+}
+
+// NewHTTPEventosPublisher cria o publisher para o serviço de pedidos em baseURL.
+func NewHTTPEventosPublisher(baseURL string, segredo assinatura.Segredo) *HTTPEventosPublisher {
+	return &HTTPEventosPublisher{
+		url:     strings.TrimRight(baseURL, "/") + "/eventos/clientes",
+		segredo: segredo,
+		client:  &http.Client{Timeout: timeoutPadrao},
+		breaker: circuitbreaker.New(limiteFalhas, tempoCircuitoAberto),
+	}
+}
+
+// Publicar implementa outbox.Publisher.
+func (p *HTTPEventosPublisher) Publicar(ctx context.Context, evento outbox.Evento) error {
+	corpo, err := json.Marshal(evento)
+	if err != nil {
+		return err
+	}
+
+	return p.breaker.Executar(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(corpo))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		p.segredo.Assinar(req, corpo, time.Now())
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("serviço de pedidos respondeu com status %d ao evento %s", resp.StatusCode, evento.ID)
+		}
+		return nil
+	})
+}
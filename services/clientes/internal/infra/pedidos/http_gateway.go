@@ -0,0 +1,135 @@
+package pedidos
+
+import (
+	"context"
+	"ecommerce/clientes/internal/domain"
+	"ecommerce/pkg/circuitbreaker"
+	"ecommerce/pkg/common/problema"
+	"ecommerce/pkg/money"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parâmetros de resiliência da chamada ao serviço de pedidos.
+const (
+	timeoutPadrao       = 5 * time.Second
+	limiteFalhas        = 5
+	tempoCircuitoAberto = 30 * time.Second
+)
+
+type httpPedidoGateway struct {
+	baseURL string
+	client  *http.Client
+	breaker *circuitbreaker.CircuitBreaker
+}
+
+// NewHTTPPedidoGateway cria o gateway que consulta o serviço de pedidos,
+// com timeout e circuit breaker para que a indisponibilidade dele não trave o de clientes.
+func NewHTTPPedidoGateway(baseURL string) domain.PedidoGateway {
+	return &httpPedidoGateway{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeoutPadrao},
+		breaker: circuitbreaker.New(limiteFalhas, tempoCircuitoAberto),
+	}
+}
+
+// paginaResponse espelha o JSON de GET /pedidos.
+type paginaResponse struct {
+	Pedidos []struct {
+		ID     string
+		Status string
+		Total  money.Money
+		Itens  []struct {
+			ProdutoID  string
+			Nome       string
+			Preco      money.Money
+			Quantidade int
+		}
+		CriadoEm     time.Time
+		AtualizadoEm time.Time
+	} `json:"pedidos"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// ListarPorCliente implementa domain.PedidoGateway usando GET /pedidos?cliente_id=.
+func (g *httpPedidoGateway) ListarPorCliente(ctx context.Context, clienteID string, filtro domain.FiltroPedidosCliente) (*domain.PaginaPedidosCliente, error) {
+	params := url.Values{"cliente_id": {clienteID}}
+	if filtro.Status != "" {
+		params.Set("status", filtro.Status)
+	}
+	if filtro.Cursor != "" {
+		params.Set("cursor", filtro.Cursor)
+	}
+	if filtro.Limite != 0 {
+		params.Set("limit", strconv.Itoa(filtro.Limite))
+	}
+
+	var body paginaResponse
+	var recusa string // Motivo de um 400, que não conta como falha para o circuito.
+	err := g.breaker.Executar(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"/pedidos?"+params.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := g.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return json.NewDecoder(resp.Body).Decode(&body)
+		case http.StatusBadRequest:
+			// Filtro inválido é erro de quem chamou, não indisponibilidade.
+			var p problema.Problema
+			json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&p)
+			recusa = p.Detalhe
+			if recusa == "" {
+				recusa = "recusado pelo serviço de pedidos"
+			}
+			return nil
+		default:
+			return fmt.Errorf("serviço de pedidos respondeu com status %d", resp.StatusCode)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrPedidosIndisponivel, err)
+	}
+	if recusa != "" {
+		return nil, fmt.Errorf("%w: %s", domain.ErrFiltroInvalido, recusa)
+	}
+
+	pagina := &domain.PaginaPedidosCliente{
+		Pedidos:    make([]domain.PedidoDoCliente, 0, len(body.Pedidos)),
+		NextCursor: body.NextCursor,
+	}
+	for _, p := range body.Pedidos {
+		pedido := domain.PedidoDoCliente{
+			ID:           p.ID,
+			Status:       p.Status,
+			Total:        p.Total,
+			Itens:        make([]domain.ItemPedido, 0, len(p.Itens)),
+			CriadoEm:     p.CriadoEm,
+			AtualizadoEm: p.AtualizadoEm,
+		}
+		for _, item := range p.Itens {
+			pedido.Itens = append(pedido.Itens, domain.ItemPedido{
+				ProdutoID:  item.ProdutoID,
+				Nome:       item.Nome,
+				Preco:      item.Preco,
+				Quantidade: item.Quantidade,
+			})
+		}
+		pagina.Pedidos = append(pagina.Pedidos, pedido)
+	}
+	return pagina, nil
+}
@@ -0,0 +1,66 @@
+package pedidos
+
+import (
+	"context"
+	"ecommerce/clientes/internal/domain"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// PedidosEmMemoria é um gateway de pedidos falso, para testes e desenvolvimento local.
+// O cursor é a posição do próximo pedido na lista do cliente.
+type PedidosEmMemoria struct {
+	mu      sync.RWMutex
+	pedidos map[string][]domain.PedidoDoCliente
+}
+
+// NewPedidosEmMemoria cria o gateway falso sem pedidos.
+func NewPedidosEmMemoria() *PedidosEmMemoria {
+	return &PedidosEmMemoria{pedidos: make(map[string][]domain.PedidoDoCliente)}
+}
+
+// Adicionar registra um pedido do cliente.
+func (g *PedidosEmMemoria) Adicionar(clienteID string, pedido domain.PedidoDoCliente) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.pedidos[clienteID] = append(g.pedidos[clienteID], pedido)
+}
+
+// ListarPorCliente implementa domain.PedidoGateway, do pedido mais novo para o mais antigo.
+func (g *PedidosEmMemoria) ListarPorCliente(_ context.Context, clienteID string, filtro domain.FiltroPedidosCliente) (*domain.PaginaPedidosCliente, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var selecionados []domain.PedidoDoCliente
+	for _, p := range g.pedidos[clienteID] {
+		if filtro.Status == "" || p.Status == filtro.Status {
+			selecionados = append(selecionados, p)
+		}
+	}
+	sort.SliceStable(selecionados, func(i, j int) bool { return selecionados[i].CriadoEm.After(selecionados[j].CriadoEm) })
+
+	inicio := 0
+	if filtro.Cursor != "" {
+		var err error
+		if inicio, err = strconv.Atoi(filtro.Cursor); err != nil || inicio < 0 {
+			return nil, fmt.Errorf("%w: cursor inválido", domain.ErrFiltroInvalido)
+		}
+	}
+	limite := filtro.Limite
+	if limite <= 0 {
+		limite = 20
+	}
+
+	pagina := &domain.PaginaPedidosCliente{Pedidos: []domain.PedidoDoCliente{}}
+	if inicio < len(selecionados) {
+		fim := min(inicio+limite, len(selecionados))
+		pagina.Pedidos = append(pagina.Pedidos, selecionados[inicio:fim]...)
+		if fim < len(selecionados) {
+			proximo := strconv.Itoa(fim)
+			pagina.NextCursor = &proximo
+		}
+	}
+	return pagina, nil
+}
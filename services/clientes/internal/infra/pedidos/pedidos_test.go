@@ -0,0 +1,182 @@
+package pedidos
+
+import (
+	"context"
+	"ecommerce/clientes/internal/domain"
+	"ecommerce/pkg/common/assinatura"
+	"ecommerce/pkg/outbox"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+// idsDaPagina devolve os IDs da página e o próximo cursor ("" na última).
+func idsDaPagina(pagina *domain.PaginaPedidosCliente) ([]string, string) {
+	var ids []string
+	for _, p := range pagina.Pedidos {
+		ids = append(ids, p.ID)
+	}
+	if pagina.NextCursor == nil {
+		return ids, ""
+	}
+	return ids, *pagina.NextCursor
+}
+
+func TestPedidosEmMemoria(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	gateway := NewPedidosEmMemoria()
+	gateway.Adicionar("cliente-1", domain.PedidoDoCliente{ID: "a", Status: "pago", CriadoEm: base})
+	gateway.Adicionar("cliente-1", domain.PedidoDoCliente{ID: "b", Status: "cancelado", CriadoEm: base.Add(time.Hour)})
+	gateway.Adicionar("cliente-1", domain.PedidoDoCliente{ID: "c", Status: "pago", CriadoEm: base.Add(2 * time.Hour)})
+	gateway.Adicionar("cliente-2", domain.PedidoDoCliente{ID: "d", Status: "pago", CriadoEm: base})
+
+	casos := []struct {
+		nome    string
+		cliente string
+		filtro  domain.FiltroPedidosCliente
+		ids     []string
+		proximo string
+		erro    error
+	}{
+		{nome: "mais novo primeiro", cliente: "cliente-1", ids: []string{"c", "b", "a"}},
+		{nome: "filtro por status", cliente: "cliente-1", filtro: domain.FiltroPedidosCliente{Status: "pago"}, ids: []string{"c", "a"}},
+		{nome: "primeira página", cliente: "cliente-1", filtro: domain.FiltroPedidosCliente{Limite: 2}, ids: []string{"c", "b"}, proximo: "2"},
+		{nome: "última página", cliente: "cliente-1", filtro: domain.FiltroPedidosCliente{Limite: 2, Cursor: "2"}, ids: []string{"a"}},
+		{nome: "cursor além do fim", cliente: "cliente-1", filtro: domain.FiltroPedidosCliente{Cursor: "9"}},
+		{nome: "cliente sem pedidos", cliente: "cliente-3"},
+		{nome: "cursor inválido", cliente: "cliente-1", filtro: domain.FiltroPedidosCliente{Cursor: "x"}, erro: domain.ErrFiltroInvalido},
+		{nome: "cursor negativo", cliente: "cliente-1", filtro: domain.FiltroPedidosCliente{Cursor: "-1"}, erro: domain.ErrFiltroInvalido},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			pagina, err := gateway.ListarPorCliente(context.Background(), c.cliente, c.filtro)
+			if !errors.Is(err, c.erro) {
+				t.Fatalf("erro = %v, esperado %v", err, c.erro)
+			}
+			if c.erro != nil {
+				return
+			}
+			if pagina.Pedidos == nil {
+				t.Error("página vazia deveria ter lista vazia, não nil")
+			}
+			ids, proximo := idsDaPagina(pagina)
+			if !slices.Equal(ids, c.ids) || proximo != c.proximo {
+				t.Errorf("página = %v (próximo %q), esperado %v (próximo %q)", ids, proximo, c.ids, c.proximo)
+			}
+		})
+	}
+}
+
+func TestHTTPPedidoGateway(t *testing.T) {
+	casos := []struct {
+		nome    string
+		status  int
+		corpo   string
+		ids     []string
+		proximo string
+		erro    error
+	}{
+		{
+			nome:    "página com itens",
+			status:  http.StatusOK,
+			corpo:   `{"pedidos":[{"ID":"p1","Status":"pago","Total":{"valor":2990,"moeda":"BRL"},"Itens":[{"ProdutoID":"x","Quantidade":1}]}],"next_cursor":"abc"}`,
+			ids:     []string{"p1"},
+			proximo: "abc",
+		},
+		{
+			nome:   "filtro recusado pelo serviço de pedidos",
+			status: http.StatusBadRequest,
+			corpo:  `{"detail":"cursor inválido"}`,
+			erro:   domain.ErrFiltroInvalido,
+		},
+		{nome: "serviço de pedidos com falha", status: http.StatusInternalServerError, erro: domain.ErrPedidosIndisponivel},
+		{nome: "resposta inválida", status: http.StatusOK, corpo: `{`, erro: domain.ErrPedidosIndisponivel},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query()
+				if r.URL.Path != "/pedidos" || q.Get("cliente_id") != "cliente-1" || q.Get("status") != "pago" ||
+					q.Get("cursor") != "c1" || q.Get("limit") != "5" {
+					t.Errorf("requisição = %s, esperado /pedidos com cliente_id, status, cursor e limit", r.URL)
+				}
+				w.WriteHeader(c.status)
+				io.WriteString(w, c.corpo)
+			}))
+			defer servidor.Close()
+
+			pagina, err := NewHTTPPedidoGateway(servidor.URL).ListarPorCliente(context.Background(), "cliente-1",
+				domain.FiltroPedidosCliente{Status: "pago", Cursor: "c1", Limite: 5})
+			if !errors.Is(err, c.erro) {
+				t.Fatalf("erro = %v, esperado %v", err, c.erro)
+			}
+			if c.erro != nil {
+				return
+			}
+			ids, proximo := idsDaPagina(pagina)
+			if !slices.Equal(ids, c.ids) || proximo != c.proximo {
+				t.Errorf("página = %v (próximo %q), esperado %v (próximo %q)", ids, proximo, c.ids, c.proximo)
+			}
+			if len(pagina.Pedidos[0].Itens) != 1 {
+				t.Errorf("itens = %d, esperado 1", len(pagina.Pedidos[0].Itens))
+			}
+		})
+	}
+}
+
+var segredoTeste = assinatura.Segredo("segredo-de-teste-com-32-caracteres")
+
+func TestHTTPEventosPublisher(t *testing.T) {
+	casos := []struct {
+		nome   string
+		status int
+		erro   bool
+	}{
+		{"evento aplicado", http.StatusNoContent, false},
+		{"evento recusado", http.StatusUnprocessableEntity, true},
+		{"serviço de pedidos com falha", http.StatusInternalServerError, true},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			evento, _ := outbox.NovoEvento("cliente.mesclado", "cliente", "c1", map[string]string{"cliente_id": "c1", "mesclado_com": "c2"})
+			servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var recebido outbox.Evento
+				if r.Method != http.MethodPost || r.URL.Path != "/eventos/clientes" {
+					t.Errorf("requisição = %s %s, esperado POST /eventos/clientes", r.Method, r.URL.Path)
+				}
+				corpo, _ := io.ReadAll(r.Body)
+				if err := segredoTeste.Conferir(r, corpo, time.Now()); err != nil {
+					t.Errorf("assinatura: %v", err)
+				}
+				if err := json.Unmarshal(corpo, &recebido); err != nil || recebido.ID != evento.ID || recebido.Tipo != evento.Tipo {
+					t.Errorf("evento recebido = %+v (%v), esperado %s", recebido, err, evento.ID)
+				}
+				w.WriteHeader(c.status)
+			}))
+			defer servidor.Close()
+
+			err := NewHTTPEventosPublisher(servidor.URL+"/", segredoTeste).Publicar(context.Background(), evento)
+			if (err != nil) != c.erro {
+				t.Errorf("Publicar = %v, esperado erro: %v", err, c.erro)
+			}
+		})
+	}
+}
+
+// TestHTTPEventosPublisherComPedidosForaDoAr: sem resposta, Publicar falha e o
+// relay mantém o evento na outbox para nova tentativa.
+func TestHTTPEventosPublisherComPedidosForaDoAr(t *testing.T) {
+	servidor := httptest.NewServer(http.NotFoundHandler())
+	url := servidor.URL
+	servidor.Close()
+
+	evento, _ := outbox.NovoEvento("cliente.mesclado", "cliente", "c1", map[string]string{})
+	if err := NewHTTPEventosPublisher(url, segredoTeste).Publicar(context.Background(), evento); err == nil {
+		t.Error("Publicar com o serviço de pedidos fora do ar não retornou erro")
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"ecommerce/clientes/internal/domain"
+	"ecommerce/pkg/db"
+	"ecommerce/pkg/outbox"
+	"time"
+)
+
+// Tipos dos eventos publicados pelo serviço de clientes.
+const (
+	agregadoCliente         = "cliente"
+	eventoClienteCadastrado = "cliente.cadastrado"
+	eventoClienteMesclado   = "cliente.mesclado"
+)
+
+// dadosEventoCliente é o conteúdo (campo "dados") dos eventos de cliente.
+type dadosEventoCliente struct {
+	ClienteID string    `json:"cliente_id"`
+	Nome      string    `json:"nome"`
+	Email     string    `json:"email"`
+	CriadoEm  time.Time `json:"criado_em"`
+	Versao    int       `json:"versao"`
+}
+
+// gravarEvento registra na outbox um evento do cliente, dentro da transação
+// que grava a alteração correspondente.
+func gravarEvento(ctx context.Context, q db.Querier, tipo string, cliente *domain.Cliente) error {
+	evento, err := outbox.NovoEvento(tipo, agregadoCliente, cliente.ID, dadosEventoCliente{
+		ClienteID: cliente.ID,
+		Nome:      cliente.Nome,
+		Email:     cliente.Email,
+		CriadoEm:  cliente.CriadoEm,
+		Versao:    cliente.Versao,
+	})
+	if err != nil {
+		return err
+	}
+	return outbox.Gravar(ctx, q, evento)
+}
+
+// dadosEventoMesclagem é o conteúdo do evento cliente.mesclado: o cliente
+// cliente_id foi absorvido por mesclado_com, a quem passam a pertencer seus
+// pedidos.
+type dadosEventoMesclagem struct {
+	ClienteID   string    `json:"cliente_id"`
+	MescladoCom string    `json:"mesclado_com"`
+	MescladoEm  time.Time `json:"mesclado_em"`
+}
+
+// gravarEventoMesclagem registra na outbox a mesclagem de origem em destino.
+func gravarEventoMesclagem(ctx context.Context, q db.Querier, destino, origem *domain.Cliente) error {
+	evento, err := outbox.NovoEvento(eventoClienteMesclado, agregadoCliente, origem.ID, dadosEventoMesclagem{
+		ClienteID:   origem.ID,
+		MescladoCom: destino.ID,
+		MescladoEm:  destino.AlteradoEm,
+	})
+	if err != nil {
+		return err
+	}
+	return outbox.Gravar(ctx, q, evento)
+}
@@ -2,107 +2,684 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"ecommerce/clientes/internal/domain"
+	"ecommerce/pkg/db"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// codigoViolacaoUnica é o SQLSTATE do Postgres para unique_violation.
+const codigoViolacaoUnica = "23505"
+
+// Índices únicos dos clientes ativos, para traduzir as violações em erros do domínio.
+const (
+	indiceEmail     = "uq_clientes_email"
+	indiceDocumento = "uq_clientes_documento"
+)
+
+// VerificarIndicesUnicos confere que os índices que garantem e-mail e
+// documento únicos existem e são válidos. Sem eles, cadastros repetidos
+// seriam aceitos em silêncio, então o serviço não deve subir.
+func VerificarIndicesUnicos(ctx context.Context, pool *db.Pool) error {
+	for _, indice := range []string{indiceEmail, indiceDocumento} {
+		var valido bool
+		err := pool.Querier(ctx).QueryRow(ctx,
+			`SELECT i.indisvalid FROM pg_index i WHERE i.indexrelid = to_regclass($1)`, indice,
+		).Scan(&valido)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("índice único %s não existe", indice)
+		}
+		if err != nil {
+			return err
+		}
+		if !valido {
+			return fmt.Errorf("índice único %s está inválido", indice)
+		}
+	}
+	return nil
+}
+
 type postgresClienteRepository struct {
-	db *sql.DB
+	db *db.Pool
 }
 
 // NewPostgresClienteRepository é o construtor do nosso repositório.
-func NewPostgresClienteRepository(db *sql.DB) domain.ClienteRepository {
-	return &postgresClienteRepository{db: db}
+func NewPostgresClienteRepository(pool *db.Pool) domain.ClienteRepository {
+	return &postgresClienteRepository{db: pool}
 }
 
-// Save cria um novo cliente e seus endereços dentro de uma transação.
+// Save cria um novo cliente e seus endereços dentro de uma transação, com o
+// evento cliente.cadastrado gravado na outbox.
 func (r *postgresClienteRepository) Save(ctx context.Context, cliente *domain.Cliente) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
 	// Gera um novo ID e define as datas de criação e alteração.
 	cliente.ID = uuid.NewString()
 	now := time.Now()
 	cliente.CriadoEm = now
-	cliente.AlteradoEm = now // <-- ALTERADO: Na criação, AlteradoEm é igual a CriadoEm.
+	cliente.AlteradoEm = now // Na criação, AlteradoEm é igual a CriadoEm.
+	cliente.Versao = 1
+
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		q := r.db.Querier(ctx)
+
+		// Insere o cliente principal.
+		// O documento vazio é gravado como NULL: o índice único ignora os clientes sem documento.
+		clienteQuery := `INSERT INTO clientes (id, nome, email, documento, criado_em, alterado_em, versao) VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7)`
+		_, err := q.Exec(ctx, clienteQuery, cliente.ID, cliente.Nome, cliente.Email, string(cliente.Documento), cliente.CriadoEm, cliente.AlteradoEm, cliente.Versao)
+		if err != nil {
+			return traduzirErro(err)
+		}
+
+		// Insere os endereços associados, já com tipo e indicação de padrão.
+		if err := inserirEnderecos(ctx, q, cliente.ID, cliente.Enderecos); err != nil {
+			return err
+		}
+
+		return gravarEvento(ctx, q, eventoClienteCadastrado, cliente)
+	})
+}
+
+// inserirEnderecos grava os endereços em um único batch: os INSERTs vão ao banco
+// em uma só ida e os IDs gerados voltam na mesma ordem dos endereços.
+func inserirEnderecos(ctx context.Context, q db.Querier, clienteID string, enderecos []*domain.Endereco) error {
+	if len(enderecos) == 0 {
+		return nil
+	}
+
+	const query = `INSERT INTO cliente_enderecos (cliente_id, tipo, rua, cidade, estado, cep, padrao)
+				   VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	batch := &pgx.Batch{}
+	for _, endereco := range enderecos {
+		batch.Queue(query, clienteID, endereco.Tipo, endereco.Rua, endereco.Cidade, endereco.Estado, endereco.CEP, endereco.Padrao).
+			QueryRow(func(row pgx.Row) error {
+				return row.Scan(&endereco.ID)
+			})
+	}
+	return q.SendBatch(ctx, batch).Close()
+}
+
+// List retorna uma página de clientes não excluídos, com seus endereços.
+// Com cursor, a página seguinte começa depois do último cliente da anterior
+// na ordenação escolhida (keyset); sem cursor, Offset pula clientes.
+func (r *postgresClienteRepository) List(ctx context.Context, filtro domain.FiltroClientes) (*domain.PaginaClientes, error) {
+	condicoes := []string{"c.excluido_em IS NULL"}
+	var args []any
+	// param registra o valor e devolve o placeholder correspondente ($1, $2, ...).
+	param := func(valor any) string {
+		args = append(args, valor)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filtro.NomePrefixo != "" {
+		condicoes = append(condicoes, "lower(c.nome) LIKE "+param(escaparLike(strings.ToLower(filtro.NomePrefixo))+"%"))
+	}
+	if filtro.Email != "" {
+		condicoes = append(condicoes, "lower(c.email) = lower("+param(filtro.Email)+")")
+	}
+	if filtro.Documento != "" {
+		condicoes = append(condicoes, "c.documento = "+param(string(filtro.Documento)))
+	}
+	if filtro.CEP != "" || filtro.Cidade != "" {
+		// CEP e cidade precisam estar no mesmo endereço.
+		doEndereco := []string{"e.cliente_id = c.id"}
+		if filtro.CEP != "" {
+			doEndereco = append(doEndereco, "regexp_replace(e.cep, '[^0-9]', '', 'g') = "+param(filtro.CEP))
+		}
+		if filtro.Cidade != "" {
+			doEndereco = append(doEndereco, "lower(e.cidade) = lower("+param(filtro.Cidade)+")")
+		}
+		condicoes = append(condicoes, "EXISTS (SELECT 1 FROM cliente_enderecos e WHERE "+strings.Join(doEndereco, " AND ")+")")
+	}
 
-	// Insere o cliente principal, incluindo a nova coluna.
-	clienteQuery := `INSERT INTO clientes (id, nome, email, criado_em, alterado_em) VALUES ($1, $2, $3, $4, $5)`              // <-- ALTERADO
-	_, err = tx.ExecContext(ctx, clienteQuery, cliente.ID, cliente.Nome, cliente.Email, cliente.CriadoEm, cliente.AlteradoEm) // <-- ALTERADO
+	pagina := &domain.PaginaClientes{}
+	if filtro.ContarTotal {
+		var total int
+		query := `SELECT count(*) FROM clientes c WHERE ` + strings.Join(condicoes, " AND ")
+		if err := r.db.Querier(ctx).QueryRow(ctx, query, args...).Scan(&total); err != nil {
+			return nil, err
+		}
+		pagina.Total = &total
+	}
+
+	coluna, direcao, comparacao := "c.criado_em", "ASC", ">"
+	if filtro.Ordem == domain.OrdemPorNome {
+		coluna = "c.nome"
+	}
+	if filtro.Decrescente {
+		direcao, comparacao = "DESC", "<"
+	}
+	if filtro.Apos != nil {
+		var valor any = filtro.Apos.CriadoEm
+		if filtro.Ordem == domain.OrdemPorNome {
+			valor = filtro.Apos.Nome
+		}
+		condicoes = append(condicoes, "("+coluna+", c.id) "+comparacao+" ("+param(valor)+", "+param(filtro.Apos.ID)+")")
+	}
+
+	// Um cliente a mais que o limite indica que existe próxima página.
+	query := `SELECT c.id, c.nome, c.email, COALESCE(c.documento, ''), c.criado_em, c.alterado_em, c.versao
+			  FROM clientes c
+			  WHERE ` + strings.Join(condicoes, " AND ") + `
+			  ORDER BY ` + coluna + " " + direcao + ", c.id " + direcao + `
+			  LIMIT ` + param(filtro.Limite+1)
+	if filtro.Offset > 0 {
+		query += " OFFSET " + param(filtro.Offset)
+	}
+
+	rows, err := r.db.Querier(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clientes := []*domain.Cliente{}
+	for rows.Next() {
+		var c domain.Cliente
+		var documento string
+		if err := rows.Scan(&c.ID, &c.Nome, &c.Email, &documento, &c.CriadoEm, &c.AlteradoEm, &c.Versao); err != nil {
+			return nil, err
+		}
+		c.Documento = domain.Documento(documento)
+		c.Enderecos = []*domain.Endereco{}
+		clientes = append(clientes, &c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(clientes) > filtro.Limite {
+		clientes = clientes[:filtro.Limite]
+		ultimo := clientes[len(clientes)-1]
+		pagina.Proximo = &domain.CursorCliente{Ordem: filtro.Ordem, Nome: ultimo.Nome, CriadoEm: ultimo.CriadoEm, ID: ultimo.ID}
+	}
+
+	if err := r.carregarEnderecos(ctx, clientes); err != nil {
+		return nil, err
+	}
+	pagina.Clientes = clientes
+	return pagina, nil
+}
+
+// carregarEnderecos busca os endereços de todos os clientes da página em uma única query.
+func (r *postgresClienteRepository) carregarEnderecos(ctx context.Context, clientes []*domain.Cliente) error {
+	if len(clientes) == 0 {
+		return nil
+	}
+
+	porID := make(map[string]*domain.Cliente, len(clientes))
+	ids := make([]string, 0, len(clientes))
+	for _, c := range clientes {
+		porID[c.ID] = c
+		ids = append(ids, c.ID)
+	}
+
+	const query = `
+		SELECT cliente_id, id, tipo, rua, cidade, estado, cep, padrao
+		FROM cliente_enderecos
+		WHERE cliente_id = ANY($1)
+		ORDER BY cliente_id, id`
+
+	rows, err := r.db.Querier(ctx).Query(ctx, query, ids)
 	if err != nil {
 		return err
 	}
+	defer rows.Close()
 
-	// Insere os endereços associados (nenhuma mudança aqui)
-	enderecoQuery := `INSERT INTO cliente_enderecos (cliente_id, rua, cidade, estado, cep) VALUES ($1, $2, $3, $4, $5)`
-	for _, endereco := range cliente.Enderecos {
-		_, err = tx.ExecContext(ctx, enderecoQuery, cliente.ID, endereco.Rua, endereco.Cidade, endereco.Estado, endereco.CEP)
-		if err != nil {
+	for rows.Next() {
+		var clienteID string
+		var e domain.Endereco
+		if err := rows.Scan(&clienteID, &e.ID, &e.Tipo, &e.Rua, &e.Cidade, &e.Estado, &e.CEP, &e.Padrao); err != nil {
 			return err
 		}
+		cliente := porID[clienteID]
+		cliente.Enderecos = append(cliente.Enderecos, &e)
 	}
 
-	return tx.Commit()
+	return rows.Err()
+}
+
+// escaparLike protege os curingas do LIKE (% e _) digitados na busca.
+func escaparLike(texto string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(texto)
 }
 
-// FindAll busca todos os clientes e seus respectivos endereços.
-func (r *postgresClienteRepository) FindAll(ctx context.Context) ([]*domain.Cliente, error) {
-	// Adicionamos a coluna c.alterado_em à query.
+// FindByID busca um cliente e seus endereços pelo ID. Um ID fora do formato
+// UUID não existe: ele nem chega ao banco, que o recusaria com erro de sintaxe.
+func (r *postgresClienteRepository) FindByID(ctx context.Context, id string) (*domain.Cliente, error) {
+	if uuid.Validate(id) != nil {
+		return nil, domain.ErrClienteNaoEncontrado
+	}
+
 	const query = `
-		SELECT c.id, c.nome, c.email, c.criado_em, c.alterado_em, -- <-- ALTERADO
-		       e.id, e.rua, e.cidade, e.estado, e.cep
+		SELECT c.id, c.nome, c.email, COALESCE(c.documento, ''), c.criado_em, c.alterado_em, c.versao,
+		       e.id, e.tipo, e.rua, e.cidade, e.estado, e.cep, e.padrao
 		FROM clientes c
 		LEFT JOIN cliente_enderecos e ON c.id = e.cliente_id
-		ORDER BY c.criado_em DESC, c.id`
+		WHERE c.id = $1 AND c.excluido_em IS NULL
+		ORDER BY e.id`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.Querier(ctx).Query(ctx, query, id)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	clientesMap := make(map[string]*domain.Cliente)
-	var clientesOrdenados []*domain.Cliente
-
+	var cliente *domain.Cliente
 	for rows.Next() {
 		var c domain.Cliente
-		var e domain.Endereco
-		var endID sql.NullInt64
-		var endRua, endCidade, endEstado, endCEP sql.NullString
+		var documento string
+		var endID pgtype.Int8
+		var endTipo, endRua, endCidade, endEstado, endCEP pgtype.Text
+		var endPadrao pgtype.Bool
 
-		// Adicionamos &c.AlteradoEm ao Scan.
 		if err := rows.Scan(
-			&c.ID, &c.Nome, &c.Email, &c.CriadoEm, &c.AlteradoEm, // <-- ALTERADO
-			&endID, &endRua, &endCidade, &endEstado, &endCEP,
+			&c.ID, &c.Nome, &c.Email, &documento, &c.CriadoEm, &c.AlteradoEm, &c.Versao,
+			&endID, &endTipo, &endRua, &endCidade, &endEstado, &endCEP, &endPadrao,
 		); err != nil {
 			return nil, err
 		}
 
-		if _, existe := clientesMap[c.ID]; !existe {
+		// Os dados do cliente se repetem em todas as linhas; usamos apenas a primeira.
+		if cliente == nil {
+			c.Documento = domain.Documento(documento)
 			c.Enderecos = []*domain.Endereco{}
-			clientesMap[c.ID] = &c
-			clientesOrdenados = append(clientesOrdenados, &c)
+			cliente = &c
 		}
 
 		if endID.Valid {
-			e.ID = endID.Int64
-			e.Rua = endRua.String
-			e.Cidade = endCidade.String
-			e.Estado = endEstado.String
-			e.CEP = endCEP.String
-			clientesMap[c.ID].Enderecos = append(clientesMap[c.ID].Enderecos, &e)
+			cliente.Enderecos = append(cliente.Enderecos, &domain.Endereco{
+				ID:     endID.Int64,
+				Tipo:   domain.TipoEndereco(endTipo.String),
+				Rua:    endRua.String,
+				Cidade: endCidade.String,
+				Estado: endEstado.String,
+				CEP:    endCEP.String,
+				Padrao: endPadrao.Bool,
+			})
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if cliente == nil {
+		return nil, domain.ErrClienteNaoEncontrado
+	}
+
+	return cliente, nil
+}
+
+// Update persiste nome, e-mail, documento e data de alteração de um cliente
+// existente, desde que ele ainda esteja na versão em que foi lido. Um
+// duplicado pendente (migração 0010) que muda de e-mail passa a valer para o
+// índice único, e o e-mail que ele deixa pode precisar de outro cadastro indexado.
+func (r *postgresClienteRepository) Update(ctx context.Context, cliente *domain.Cliente) error {
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		q := r.db.Querier(ctx)
+		query := `WITH anterior AS (SELECT email FROM clientes WHERE id = $1)
+				  UPDATE clientes SET nome = $2, email = $3, documento = NULLIF($4, ''), alterado_em = $5, versao = versao + 1,
+					  duplicado_pendente = duplicado_pendente AND lower(email) = lower($3)
+				  WHERE id = $1 AND versao = $6 AND excluido_em IS NULL
+				  RETURNING versao, (SELECT email FROM anterior)`
+		var versao int
+		var emailAnterior string
+		err := q.QueryRow(ctx, query, cliente.ID, cliente.Nome, cliente.Email, string(cliente.Documento), cliente.AlteradoEm, cliente.Versao).
+			Scan(&versao, &emailAnterior)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return conflitoOuInexistente(ctx, q, cliente.ID)
+		}
+		if err != nil {
+			return traduzirErro(err)
+		}
+		if err := promoverDuplicadoPendente(ctx, q, emailAnterior); err != nil {
+			return err
+		}
+
+		db.AposCommit(ctx, func() { cliente.Versao = versao })
+		return nil
+	})
+}
+
+// Delete faz a exclusão lógica do cliente: ele deixa de aparecer nas consultas,
+// mas continua no banco para preservar o histórico de pedidos.
+func (r *postgresClienteRepository) Delete(ctx context.Context, id string, versao int) error {
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		q := r.db.Querier(ctx)
+		query := `UPDATE clientes SET excluido_em = $2, alterado_em = $2, versao = versao + 1
+				  WHERE id = $1 AND ($3 = 0 OR versao = $3) AND excluido_em IS NULL
+				  RETURNING email`
+		var email string
+		err := q.QueryRow(ctx, query, id, time.Now(), versao).Scan(&email)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return conflitoOuInexistente(ctx, q, id)
+		}
+		if err != nil {
+			return err
 		}
+		return promoverDuplicadoPendente(ctx, q, email)
+	})
+}
+
+// AddEndereco inclui um endereço no cliente. Se ele for o novo padrão do seu tipo,
+// o padrão anterior é desmarcado na mesma transação.
+func (r *postgresClienteRepository) AddEndereco(ctx context.Context, cliente *domain.Cliente, endereco *domain.Endereco) error {
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		q := r.db.Querier(ctx)
+
+		// O cliente é tocado primeiro: com a versão conferida, a linha fica
+		// bloqueada até o fim da transação e as alterações de endereço não se cruzam.
+		if err := tocarCliente(ctx, q, cliente); err != nil {
+			return err
+		}
+
+		if endereco.Padrao {
+			if err := desmarcarPadrao(ctx, q, cliente.ID, endereco); err != nil {
+				return err
+			}
+		}
+
+		query := `INSERT INTO cliente_enderecos (cliente_id, tipo, rua, cidade, estado, cep, padrao)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+		return q.QueryRow(ctx, query, cliente.ID, endereco.Tipo, endereco.Rua, endereco.Cidade, endereco.Estado, endereco.CEP, endereco.Padrao).
+			Scan(&endereco.ID)
+	})
+}
+
+// UpdateEndereco altera um endereço do cliente, desmarcando o padrão anterior quando necessário.
+func (r *postgresClienteRepository) UpdateEndereco(ctx context.Context, cliente *domain.Cliente, endereco *domain.Endereco) error {
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		q := r.db.Querier(ctx)
+
+		if err := tocarCliente(ctx, q, cliente); err != nil {
+			return err
+		}
+
+		if endereco.Padrao {
+			if err := desmarcarPadrao(ctx, q, cliente.ID, endereco); err != nil {
+				return err
+			}
+		}
+
+		query := `UPDATE cliente_enderecos SET tipo = $3, rua = $4, cidade = $5, estado = $6, cep = $7, padrao = $8
+				  WHERE id = $1 AND cliente_id = $2`
+		tag, err := q.Exec(ctx, query, endereco.ID, cliente.ID, endereco.Tipo, endereco.Rua, endereco.Cidade, endereco.Estado, endereco.CEP, endereco.Padrao)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrEnderecoNaoEncontrado
+		}
+		return nil
+	})
+}
+
+// DeleteEndereco remove um endereço do cliente. Pedidos guardam uma cópia do
+// endereço de entrega, então a remoção não afeta pedidos já feitos.
+func (r *postgresClienteRepository) DeleteEndereco(ctx context.Context, cliente *domain.Cliente, enderecoID int64) error {
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		q := r.db.Querier(ctx)
+
+		if err := tocarCliente(ctx, q, cliente); err != nil {
+			return err
+		}
+
+		tag, err := q.Exec(ctx, `DELETE FROM cliente_enderecos WHERE id = $1 AND cliente_id = $2`, enderecoID, cliente.ID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrEnderecoNaoEncontrado
+		}
+		return nil
+	})
+}
+
+// Mesclar exclui origem, marcando que foi absorvido por destino, e grava em
+// destino o documento herdado e os endereços recebidos, com o evento
+// cliente.mesclado na mesma transação.
+func (r *postgresClienteRepository) Mesclar(ctx context.Context, destino, origem *domain.Cliente) error {
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		q := r.db.Querier(ctx)
+
+		// A origem sai primeiro, liberando o documento que o destino pode herdar.
+		tag, err := q.Exec(ctx,
+			`UPDATE clientes SET excluido_em = $2, alterado_em = $2, versao = versao + 1, mesclado_com = $3
+			 WHERE id = $1 AND versao = $4 AND excluido_em IS NULL`,
+			origem.ID, destino.AlteradoEm, destino.ID, origem.Versao,
+		)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return conflitoOuInexistente(ctx, q, origem.ID)
+		}
+		if err := promoverDuplicadoPendente(ctx, q, origem.Email); err != nil {
+			return err
+		}
+
+		var versao int
+		err = q.QueryRow(ctx,
+			`UPDATE clientes SET documento = NULLIF($2, ''), alterado_em = $3, versao = versao + 1
+			 WHERE id = $1 AND versao = $4 AND excluido_em IS NULL
+			 RETURNING versao`,
+			destino.ID, string(destino.Documento), destino.AlteradoEm, destino.Versao,
+		).Scan(&versao)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return conflitoOuInexistente(ctx, q, destino.ID)
+		}
+		if err != nil {
+			return traduzirErro(err)
+		}
+
+		// Os endereços do destino são regravados com a marcação de padrão
+		// decidida pelo domínio; os da origem mudam de dono.
+		batch := &pgx.Batch{}
+		for _, endereco := range destino.Enderecos {
+			batch.Queue(`UPDATE cliente_enderecos SET cliente_id = $2, padrao = $3 WHERE id = $1`,
+				endereco.ID, destino.ID, endereco.Padrao)
+		}
+		if err := q.SendBatch(ctx, batch).Close(); err != nil {
+			return err
+		}
+
+		if err := gravarEventoMesclagem(ctx, q, destino, origem); err != nil {
+			return err
+		}
+		db.AposCommit(ctx, func() { destino.Versao = versao })
+		return nil
+	})
+}
+
+// consultasDeDuplicidade agrupam os clientes ativos pela chave de cada
+// critério: o e-mail normalizado (como domain.EmailNormalizado) ou o CEP dos
+// endereços, só com dígitos. Os grupos vêm em ordem de chave, para a busca
+// seguir de página em página, com os clientes mais antigos de cada um.
+var consultasDeDuplicidade = map[domain.CriterioDuplicidade]string{
+	domain.CriterioEmail: `
+		SELECT chave, (array_agg(id::text ORDER BY criado_em, id))[1:$3]
+		FROM (SELECT regexp_replace(lower(trim(email)), '\+[^@]*@', '@') AS chave, id, criado_em
+			  FROM clientes
+			  WHERE excluido_em IS NULL) c
+		WHERE chave > $1
+		GROUP BY chave
+		HAVING count(*) > 1
+		ORDER BY chave
+		LIMIT $2`,
+	domain.CriterioNomeECEP: `
+		SELECT chave, (array_agg(id::text ORDER BY criado_em, id))[1:$3]
+		FROM (SELECT DISTINCT regexp_replace(e.cep, '[^0-9]', '', 'g') AS chave, c.id, c.criado_em
+			  FROM clientes c
+			  JOIN cliente_enderecos e ON e.cliente_id = c.id
+			  WHERE c.excluido_em IS NULL) c
+		WHERE chave > $1
+		GROUP BY chave
+		HAVING count(*) > 1
+		ORDER BY chave
+		LIMIT $2`,
+}
+
+// BuscarSuspeitosDeDuplicidade busca uma página de grupos do critério e
+// carrega os clientes de cada um.
+func (r *postgresClienteRepository) BuscarSuspeitosDeDuplicidade(ctx context.Context, criterio domain.CriterioDuplicidade, apos string, limite int) ([]domain.GrupoDuplicado, error) {
+	query, ok := consultasDeDuplicidade[criterio]
+	if !ok {
+		return nil, fmt.Errorf("critério de duplicidade desconhecido: %q", criterio)
 	}
 
+	rows, err := r.db.Querier(ctx).Query(ctx, query, apos, limite, domain.MaxClientesPorGrupoDuplicado)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var grupos []domain.GrupoDuplicado
+	var idsPorGrupo [][]string
+	var todos []string
+	for rows.Next() {
+		var chave string
+		var ids []string
+		if err := rows.Scan(&chave, &ids); err != nil {
+			return nil, err
+		}
+		grupos = append(grupos, domain.GrupoDuplicado{Criterio: criterio, Chave: chave})
+		idsPorGrupo = append(idsPorGrupo, ids)
+		todos = append(todos, ids...)
+	}
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return clientesOrdenados, nil
+	clientes, err := r.buscarPorIDs(ctx, todos)
+	if err != nil {
+		return nil, err
+	}
+	for i, ids := range idsPorGrupo {
+		for _, id := range ids {
+			if cliente, ok := clientes[id]; ok {
+				grupos[i].Clientes = append(grupos[i].Clientes, cliente)
+			}
+		}
+		sort.Slice(grupos[i].Clientes, func(a, b int) bool {
+			return grupos[i].Clientes[a].CriadoEm.Before(grupos[i].Clientes[b].CriadoEm)
+		})
+	}
+	return grupos, nil
+}
+
+// buscarPorIDs carrega os clientes ativos informados, com seus endereços.
+func (r *postgresClienteRepository) buscarPorIDs(ctx context.Context, ids []string) (map[string]*domain.Cliente, error) {
+	const query = `
+		SELECT id, nome, email, COALESCE(documento, ''), criado_em, alterado_em, versao
+		FROM clientes
+		WHERE id = ANY($1) AND excluido_em IS NULL`
+
+	rows, err := r.db.Querier(ctx).Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	porID := make(map[string]*domain.Cliente)
+	var clientes []*domain.Cliente
+	for rows.Next() {
+		var c domain.Cliente
+		var documento string
+		if err := rows.Scan(&c.ID, &c.Nome, &c.Email, &documento, &c.CriadoEm, &c.AlteradoEm, &c.Versao); err != nil {
+			return nil, err
+		}
+		c.Documento = domain.Documento(documento)
+		c.Enderecos = []*domain.Endereco{}
+		porID[c.ID] = &c
+		clientes = append(clientes, &c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return porID, r.carregarEnderecos(ctx, clientes)
+}
+
+// desmarcarPadrao tira a marcação de padrão dos demais endereços do mesmo tipo.
+func desmarcarPadrao(ctx context.Context, q db.Querier, clienteID string, endereco *domain.Endereco) error {
+	_, err := q.Exec(ctx,
+		`UPDATE cliente_enderecos SET padrao = false
+		 WHERE cliente_id = $1 AND tipo = $2 AND padrao AND id <> $3`,
+		clienteID, endereco.Tipo, endereco.ID,
+	)
+	return err
+}
+
+// tocarCliente atualiza alterado_em e a versão do cliente quando seus
+// endereços mudam, conferindo que ele ainda está na versão em que foi lido.
+// A nova versão só é refletida em cliente depois do commit.
+func tocarCliente(ctx context.Context, q db.Querier, cliente *domain.Cliente) error {
+	var versao int
+	err := q.QueryRow(ctx,
+		`UPDATE clientes SET alterado_em = $2, versao = versao + 1
+		 WHERE id = $1 AND versao = $3 AND excluido_em IS NULL
+		 RETURNING versao`,
+		cliente.ID, time.Now(), cliente.Versao,
+	).Scan(&versao)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return conflitoOuInexistente(ctx, q, cliente.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	db.AposCommit(ctx, func() { cliente.Versao = versao })
+	return nil
+}
+
+// promoverDuplicadoPendente mantém um cadastro ativo de cada e-mail no
+// índice único: se o e-mail ficou sem nenhum, o duplicado pendente mais
+// antigo com ele deixa de ser pendente. Sem isso, ao excluir ou mesclar o
+// cadastro indexado, o e-mail voltaria a ser aceito em um novo cadastro.
+func promoverDuplicadoPendente(ctx context.Context, q db.Querier, email string) error {
+	query := `UPDATE clientes SET duplicado_pendente = false
+			  WHERE id = (SELECT id FROM clientes
+						  WHERE lower(email) = lower($1) AND excluido_em IS NULL AND duplicado_pendente
+						  ORDER BY criado_em, id
+						  LIMIT 1)
+				AND NOT EXISTS (SELECT 1 FROM clientes
+								WHERE lower(email) = lower($1) AND excluido_em IS NULL AND NOT duplicado_pendente)`
+	_, err := q.Exec(ctx, query, email)
+	return err
+}
+
+// conflitoOuInexistente explica uma alteração com versão que não encontrou
+// linha: o cliente existe em outra versão ou não existe (ou foi excluído).
+func conflitoOuInexistente(ctx context.Context, q db.Querier, id string) error {
+	var existe bool
+	err := q.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM clientes WHERE id = $1 AND excluido_em IS NULL)`, id).Scan(&existe)
+	if err != nil {
+		return err
+	}
+	if existe {
+		return domain.ErrConflitoDeVersao
+	}
+	return domain.ErrClienteNaoEncontrado
+}
+
+// traduzirErro converte erros conhecidos do Postgres em erros do domínio.
+func traduzirErro(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == codigoViolacaoUnica {
+		switch pgErr.ConstraintName {
+		case indiceEmail:
+			return domain.ErrEmailEmUso
+		case indiceDocumento:
+			return domain.ErrDocumentoEmUso
+		}
+	}
+	return err
 }
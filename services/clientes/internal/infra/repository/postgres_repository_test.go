@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"ecommerce/clientes/internal/domain"
+	"ecommerce/clientes/migrations"
+	"ecommerce/pkg/db"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// errDesfazer encerra a transação de cada teste ou iteração do benchmark sem gravar nada.
+var errDesfazer = errors.New("desfazer")
+
+// poolDeTeste conecta no banco de DATABASE_URL e aplica as migrações.
+// Sem DATABASE_URL, o teste é pulado: use um banco descartável.
+func poolDeTeste(tb testing.TB) *db.Pool {
+	tb.Helper()
+	if _, ok := os.LookupEnv("DATABASE_URL"); !ok {
+		tb.Skip("DATABASE_URL não definida: o teste precisa de um Postgres")
+	}
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx, db.ComApplicationName("clientes-teste"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(pool.Close)
+
+	migrador, err := db.NewMigrador(pool.SQLDB(), migrations.FS, "clientes")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if _, err := migrador.Up(ctx); err != nil {
+		tb.Fatal(err)
+	}
+	return pool
+}
+
+// inserirEnderecosPorLinha é a gravação anterior ao batch, um INSERT por
+// endereço, mantida aqui como base de comparação.
+func inserirEnderecosPorLinha(ctx context.Context, q db.Querier, clienteID string, enderecos []*domain.Endereco) error {
+	const query = `INSERT INTO cliente_enderecos (cliente_id, tipo, rua, cidade, estado, cep, padrao)
+				   VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	for _, endereco := range enderecos {
+		err := q.QueryRow(ctx, query, clienteID, endereco.Tipo, endereco.Rua, endereco.Cidade, endereco.Estado, endereco.CEP, endereco.Padrao).
+			Scan(&endereco.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BenchmarkInserirEnderecos compara o INSERT por endereço com o batch de
+// inserirEnderecos em cadastros de tamanhos diferentes:
+//
+//	DATABASE_URL=postgres://... go test -run '^$' -bench InserirEnderecos ./internal/infra/repository/
+func BenchmarkInserirEnderecos(b *testing.B) {
+	pool := poolDeTeste(b)
+	estrategias := []struct {
+		nome    string
+		inserir func(context.Context, db.Querier, string, []*domain.Endereco) error
+	}{
+		{"por_linha", inserirEnderecosPorLinha},
+		{"batch", inserirEnderecos},
+	}
+
+	for _, quantidade := range []int{1, 10, 100} {
+		var enderecos []*domain.Endereco
+		for i := range quantidade {
+			enderecos = append(enderecos, &domain.Endereco{
+				Tipo:   domain.TipoEntrega,
+				Rua:    "Rua " + strconv.Itoa(i),
+				Cidade: "São Paulo",
+				Estado: "SP",
+				CEP:    "01310-100",
+			})
+		}
+
+		for _, estrategia := range estrategias {
+			b.Run(estrategia.nome+"/enderecos="+strconv.Itoa(quantidade), func(b *testing.B) {
+				for b.Loop() {
+					clienteID := uuid.NewString()
+					err := pool.WithTx(context.Background(), func(ctx context.Context) error {
+						q := pool.Querier(ctx)
+						b.StopTimer()
+						_, err := q.Exec(ctx,
+							`INSERT INTO clientes (id, nome, email, criado_em, alterado_em) VALUES ($1, 'Benchmark', $2, now(), now())`,
+							clienteID, clienteID+"@benchmark.example",
+						)
+						b.StartTimer()
+						if err != nil {
+							return err
+						}
+						if err := estrategia.inserir(ctx, q, clienteID, enderecos); err != nil {
+							return err
+						}
+						return errDesfazer
+					})
+					if !errors.Is(err, errDesfazer) {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestEmailDeDuplicadoPendente confere que o índice único continua valendo
+// com os duplicados pendentes da migração 0010: o e-mail repetido é recusado
+// mesmo depois de o cadastro indexado sair, e o pendente que muda de e-mail
+// passa a ser conferido.
+func TestEmailDeDuplicadoPendente(t *testing.T) {
+	pool := poolDeTeste(t)
+	repo := NewPostgresClienteRepository(pool)
+
+	err := pool.WithTx(context.Background(), func(ctx context.Context) error {
+		q := pool.Querier(ctx)
+		email := uuid.NewString() + "@teste.example"
+		antigo, pendente := uuid.NewString(), uuid.NewString()
+		_, err := q.Exec(ctx,
+			`INSERT INTO clientes (id, nome, email, criado_em, alterado_em, duplicado_pendente) VALUES
+				($1, 'Maria', $3, now() - interval '2 days', now(), false),
+				($2, 'Maria', upper($3), now() - interval '1 day', now(), true)`,
+			antigo, pendente, email,
+		)
+		if err != nil {
+			return err
+		}
+
+		novo := func() error {
+			return repo.Save(ctx, &domain.Cliente{Nome: "Maria", Email: strings.ToUpper(email), Enderecos: []*domain.Endereco{}})
+		}
+		if err := novo(); !errors.Is(err, domain.ErrEmailEmUso) {
+			t.Errorf("cadastro com e-mail repetido: %v, esperado ErrEmailEmUso", err)
+		}
+
+		// Sem o cadastro indexado, o pendente toma o lugar dele.
+		if err := repo.Delete(ctx, antigo, 0); err != nil {
+			return err
+		}
+		if err := novo(); !errors.Is(err, domain.ErrEmailEmUso) {
+			t.Errorf("cadastro depois da exclusão do indexado: %v, esperado ErrEmailEmUso", err)
+		}
+
+		// Mudar de e-mail tira o cliente dos pendentes e libera o anterior.
+		cliente, err := repo.FindByID(ctx, pendente)
+		if err != nil {
+			return err
+		}
+		cliente.Email = "outro-" + email
+		if err := repo.Update(ctx, cliente); err != nil {
+			return err
+		}
+		var marcado bool
+		if err := q.QueryRow(ctx, `SELECT duplicado_pendente FROM clientes WHERE id = $1`, pendente).Scan(&marcado); err != nil {
+			return err
+		}
+		if marcado {
+			t.Error("cliente continua pendente depois de mudar de e-mail")
+		}
+		if err := novo(); err != nil {
+			t.Errorf("cadastro com o e-mail liberado: %v", err)
+		}
+		return errDesfazer
+	})
+	if !errors.Is(err, errDesfazer) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS cliente_enderecos;
+DROP TABLE IF EXISTS clientes;
@@ -0,0 +1,19 @@
+-- Tabelas que existiam antes do controle de migrações. IF NOT EXISTS permite
+-- registrar esta versão em bancos criados manualmente sem recriá-las.
+CREATE TABLE IF NOT EXISTS clientes (
+    id        UUID PRIMARY KEY,
+    nome      TEXT NOT NULL,
+    email     TEXT NOT NULL,
+    criado_em TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS cliente_enderecos (
+    id         BIGSERIAL PRIMARY KEY,
+    cliente_id UUID NOT NULL REFERENCES clientes (id) ON DELETE CASCADE,
+    rua        TEXT NOT NULL,
+    cidade     TEXT NOT NULL,
+    estado     TEXT NOT NULL,
+    cep        TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_cliente_enderecos_cliente_id ON cliente_enderecos (cliente_id);
@@ -0,0 +1 @@
+ALTER TABLE clientes DROP COLUMN IF EXISTS alterado_em;
@@ -0,0 +1,4 @@
+-- Coluna que antes era adicionada manualmente. IF NOT EXISTS mantém os bancos antigos compatíveis.
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS alterado_em TIMESTAMPTZ;
+UPDATE clientes SET alterado_em = criado_em WHERE alterado_em IS NULL;
+ALTER TABLE clientes ALTER COLUMN alterado_em SET NOT NULL;
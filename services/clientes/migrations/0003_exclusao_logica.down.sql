@@ -0,0 +1 @@
+ALTER TABLE clientes DROP COLUMN IF EXISTS excluido_em;
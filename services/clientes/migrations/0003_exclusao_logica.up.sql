@@ -0,0 +1 @@
+ALTER TABLE clientes ADD COLUMN excluido_em TIMESTAMPTZ;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS uq_cliente_enderecos_padrao;
+ALTER TABLE cliente_enderecos DROP COLUMN IF EXISTS padrao, DROP COLUMN IF EXISTS tipo;
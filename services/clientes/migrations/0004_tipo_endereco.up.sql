@@ -0,0 +1,10 @@
+ALTER TABLE cliente_enderecos
+    ADD COLUMN tipo   TEXT NOT NULL DEFAULT 'entrega' CHECK (tipo IN ('entrega', 'cobranca')),
+    ADD COLUMN padrao BOOLEAN NOT NULL DEFAULT false;
+
+-- Endereços antigos: o mais antigo de cada cliente vira o padrão de entrega.
+UPDATE cliente_enderecos e SET padrao = true
+WHERE e.id = (SELECT min(id) FROM cliente_enderecos WHERE cliente_id = e.cliente_id);
+
+-- No máximo um endereço padrão por tipo para cada cliente.
+CREATE UNIQUE INDEX uq_cliente_enderecos_padrao ON cliente_enderecos (cliente_id, tipo) WHERE padrao;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_cliente_enderecos_cidade;
+DROP INDEX IF EXISTS idx_cliente_enderecos_cep;
+DROP INDEX IF EXISTS idx_clientes_email;
+DROP INDEX IF EXISTS idx_clientes_nome_prefixo;
+DROP INDEX IF EXISTS idx_clientes_criado_em_id;
+DROP INDEX IF EXISTS idx_clientes_nome_id;
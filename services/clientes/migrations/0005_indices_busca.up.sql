@@ -0,0 +1,11 @@
+-- Ordenação da listagem por nome ou data de criação, com o ID como desempate.
+CREATE INDEX idx_clientes_nome_id ON clientes (nome, id) WHERE excluido_em IS NULL;
+CREATE INDEX idx_clientes_criado_em_id ON clientes (criado_em, id) WHERE excluido_em IS NULL;
+
+-- Busca por início do nome e por e-mail, sem diferenciar maiúsculas.
+CREATE INDEX idx_clientes_nome_prefixo ON clientes (lower(nome) text_pattern_ops) WHERE excluido_em IS NULL;
+CREATE INDEX idx_clientes_email ON clientes (lower(email));
+
+-- Busca por CEP (só dígitos) e por cidade nos endereços.
+CREATE INDEX idx_cliente_enderecos_cep ON cliente_enderecos (regexp_replace(cep, '[^0-9]', '', 'g'));
+CREATE INDEX idx_cliente_enderecos_cidade ON cliente_enderecos (lower(cidade));
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS chaves_idempotencia;
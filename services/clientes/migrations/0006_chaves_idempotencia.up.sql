@@ -0,0 +1,15 @@
+-- Chaves de idempotência (header Idempotency-Key) e a resposta guardada para
+-- reproduzir nas repetições. Ver pkg/idempotencia.
+CREATE TABLE chaves_idempotencia (
+    escopo     TEXT NOT NULL,
+    chave      TEXT NOT NULL,
+    impressao  TEXT NOT NULL,
+    status     INTEGER,
+    cabecalhos JSONB,
+    corpo      BYTEA,
+    criado_em  TIMESTAMPTZ NOT NULL,
+    expira_em  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (escopo, chave)
+);
+
+CREATE INDEX idx_chaves_idempotencia_expira_em ON chaves_idempotencia (expira_em);
@@ -0,0 +1,3 @@
+-- Controle de concorrência otimista: incrementada a cada alteração do cliente
+-- ou dos seus endereços.
+ALTER TABLE clientes ADD COLUMN versao INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,21 @@
+-- Outbox transacional: eventos gravados na mesma transação das alterações e
+-- publicados depois pelo relay (pkg/outbox). sequencia dá a ordem de gravação.
+CREATE TABLE outbox (
+    id                   UUID PRIMARY KEY,
+    sequencia            BIGSERIAL NOT NULL,
+    tipo                 TEXT NOT NULL,
+    agregado             TEXT NOT NULL,
+    agregado_id          TEXT NOT NULL,
+    dados                JSONB NOT NULL,
+    ocorrido_em          TIMESTAMPTZ NOT NULL,
+    tentativas           INTEGER NOT NULL DEFAULT 0,
+    proxima_tentativa_em TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ultimo_erro          TEXT,
+    publicado_em         TIMESTAMPTZ,
+    descartado_em        TIMESTAMPTZ -- Preenchido quando as tentativas se esgotam (dead-letter).
+);
+
+CREATE INDEX idx_outbox_pendentes ON outbox (sequencia)
+    WHERE publicado_em IS NULL AND descartado_em IS NULL;
+CREATE INDEX idx_outbox_descartados ON outbox (sequencia) WHERE descartado_em IS NOT NULL;
+CREATE INDEX idx_outbox_publicado_em ON outbox (publicado_em) WHERE publicado_em IS NOT NULL;
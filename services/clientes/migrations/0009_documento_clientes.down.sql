@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS uq_clientes_documento;
+ALTER TABLE clientes DROP COLUMN documento;
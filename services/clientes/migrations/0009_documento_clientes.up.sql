@@ -0,0 +1,7 @@
+-- CPF (11 dígitos) ou CNPJ (14 caracteres) do cliente, sem pontuação. NULL
+-- para clientes sem documento informado.
+ALTER TABLE clientes ADD COLUMN documento VARCHAR(14);
+
+-- Um documento pertence a um único cliente ativo; clientes excluídos liberam
+-- o documento para um novo cadastro. Também atende a busca por documento.
+CREATE UNIQUE INDEX uq_clientes_documento ON clientes (documento) WHERE excluido_em IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS uq_clientes_email;
+ALTER TABLE clientes DROP COLUMN duplicado_pendente;
+ALTER TABLE clientes DROP COLUMN mesclado_com;
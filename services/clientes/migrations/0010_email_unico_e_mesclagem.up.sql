@@ -0,0 +1,25 @@
+-- Mesclagem de clientes duplicados: o cliente absorvido é excluído (lógica)
+-- e aponta para o cliente que ficou com seus endereços e pedidos.
+ALTER TABLE clientes ADD COLUMN mesclado_com UUID REFERENCES clientes (id);
+
+-- Clientes ativos que já repetiam o e-mail de outro antes do índice único.
+-- Quem fica com os endereços e pedidos é decidido por uma pessoa, com
+-- GET /clientes/duplicados e POST /clientes/{id}/mesclar; até lá, só o
+-- cadastro mais antigo de cada e-mail entra no índice. Ao mudar de e-mail,
+-- o cliente deixa de ser pendente; ao sair o cadastro indexado, o pendente
+-- mais antigo toma o seu lugar (ver o repositório).
+ALTER TABLE clientes ADD COLUMN duplicado_pendente BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE clientes c SET duplicado_pendente = true
+WHERE c.excluido_em IS NULL
+  AND EXISTS (
+      SELECT 1
+      FROM clientes o
+      WHERE o.excluido_em IS NULL
+        AND lower(o.email) = lower(c.email)
+        AND (o.criado_em, o.id) < (c.criado_em, c.id)
+  );
+
+-- E-mail único entre os clientes ativos, sem diferenciar maiúsculas. O índice
+-- de busca por e-mail continua: este não cobre os pendentes.
+CREATE UNIQUE INDEX uq_clientes_email ON clientes (lower(email)) WHERE excluido_em IS NULL AND NOT duplicado_pendente;
@@ -0,0 +1 @@
+ALTER TABLE chaves_idempotencia DROP COLUMN IF EXISTS dono;
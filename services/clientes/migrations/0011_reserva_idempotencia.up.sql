@@ -0,0 +1,9 @@
+-- A reserva de uma Idempotency-Key passa a ter dono (a requisição que a fez)
+-- e dura pouco: só a resposta concluída fica guardada pelo TTL completo.
+ALTER TABLE chaves_idempotencia ADD COLUMN dono TEXT;
+
+-- Reservas em andamento da versão anterior valiam pelo TTL inteiro; as que
+-- ficaram presas por uma requisição interrompida são soltas em um minuto.
+UPDATE chaves_idempotencia
+SET expira_em = LEAST(expira_em, now() + interval '1 minute')
+WHERE status IS NULL;
@@ -0,0 +1,4 @@
+-- O relay publica os eventos de um agregado na ordem de gravação: um evento só
+-- é lido sem nenhum anterior do mesmo agregado pendente.
+CREATE INDEX idx_outbox_pendentes_por_agregado ON outbox (agregado, agregado_id, sequencia)
+    WHERE publicado_em IS NULL AND descartado_em IS NULL;
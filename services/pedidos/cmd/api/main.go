@@ -1,14 +1,25 @@
 package main
 
 import (
+	"context"
 	"ecommerce/pedidos/internal/application"
+	"ecommerce/pedidos/internal/infra/catalogo"
+	"ecommerce/pedidos/internal/infra/clientes"
+	"ecommerce/pedidos/internal/infra/estoque"
 	httphandler "ecommerce/pedidos/internal/infra/http"
 	"ecommerce/pedidos/internal/infra/repository"
+	"ecommerce/pedidos/internal/infra/webhook"
+	"ecommerce/pedidos/migrations"
+	"ecommerce/pkg/common/assinatura"
+	"ecommerce/pkg/common/problema"
 	"ecommerce/pkg/db"
+	"ecommerce/pkg/idempotencia"
+	"ecommerce/pkg/outbox"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	_ "ecommerce/pedidos/docs" // Importa os docs gerados pelo swag (necessário)
 
@@ -32,26 +43,99 @@ func main() {
 	}
 
 	// 1. Inicializa a Conexão com o Banco de Dados
-	dbConn, err := db.NewConnection()
+	pool, err := db.NewPool(context.Background(), db.ComApplicationName("pedidos-service"))
 	if err != nil {
 		log.Fatalf("Não foi possível conectar ao banco de dados: %v", err)
 	}
-	defer dbConn.Close()
+	defer pool.Close()
 
-	// 2. Inicializa o Repositório, Serviço e Handler
-	repo := repository.NewPostgresPedidoRepository(dbConn)
-	pedidoService := application.NewPedidoService(repo)
+	// Migrações do schema: "migrate up|down|status" executa o comando e encerra;
+	// sem ele, o serviço só sobe com todas as migrações aplicadas.
+	migrador, err := db.NewMigrador(pool.SQLDB(), migrations.FS, "pedidos")
+	if err != nil {
+		log.Fatalf("Migrações inválidas: %v", err)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrador.ExecutarComando(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("Erro ao executar migração: %v", err)
+		}
+		return
+	}
+	if err := migrador.VerificarAtualizado(context.Background()); err != nil {
+		log.Fatalf("Não é possível iniciar: %v", err)
+	}
+
+	// 2. Inicializa o Repositório, o cliente do Catálogo, o Serviço e o Handler
+	catalogoURL, ok := os.LookupEnv("CATALOGO_URL")
+	if !ok {
+		log.Fatalf("A variável de ambiente CATALOGO_URL não foi definida")
+	}
+	clientesURL, ok := os.LookupEnv("CLIENTES_URL")
+	if !ok {
+		log.Fatalf("A variável de ambiente CLIENTES_URL não foi definida")
+	}
+	// Os eventos do serviço de clientes chegam assinados com este segredo, o mesmo nos dois serviços.
+	segredoEventos, err := assinatura.NewSegredo(os.Getenv("EVENTOS_SEGREDO"))
+	if err != nil {
+		log.Fatalf("EVENTOS_SEGREDO inválida: %v", err)
+	}
+
+	// Reservas de pedidos não pagos seguram o estoque por 30 minutos.
+	estoquePostgres := estoque.NewPostgresEstoque(pool, 30*time.Minute)
+	go estoquePostgres.IniciarExpiracao(context.Background(), time.Minute)
+
+	repo := repository.NewPostgresPedidoRepository(pool)
+	catalogoClient := catalogo.NewHTTPCatalogoClient(catalogoURL)
+	clienteGateway := clientes.NewHTTPClienteGateway(clientesURL)
+	pedidoService := application.NewPedidoService(repo, catalogoClient, estoquePostgres, clienteGateway, pool)
 	pedidoHandler := httphandler.NewPedidoHandler(pedidoService)
+	estoqueHandler := httphandler.NewEstoqueHandler(application.NewEstoqueService(estoquePostgres))
+	webhooks := webhook.NewPostgresWebhooks(pool)
+	webhookHandler := httphandler.NewWebhookHandler(application.NewWebhookService(webhooks))
+	// Eventos do serviço de clientes chegam por POST /eventos/clientes, enviados pelo relay da
+	// outbox dele: cliente.mesclado transfere os pedidos do cliente absorvido.
+	eventoHandler := httphandler.NewEventoHandler(pedidoService, segredoEventos)
 
 	// 3. Configuração do Roteador e Rotas
+	// Os eventos gravados na outbox são publicados no canal "pedidos_eventos" do Postgres
+	// (LISTEN pedidos_eventos para recebê-los) e viram entregas para as assinaturas de webhook,
+	// enviadas pelo entregador.
+	relay := outbox.NewRelay(pool, outbox.Multiplos(outbox.NewPostgresPublisher(pool, "pedidos_eventos"), webhooks))
+	go relay.Executar(context.Background())
+	go webhook.NewEntregador(pool).IniciarEntregas(context.Background(), time.Second)
+
+	// Respostas de criação ficam guardadas por 24 horas para repetições com a mesma Idempotency-Key.
+	chavesIdempotencia := idempotencia.NewPostgresArmazenamento(pool)
+	go chavesIdempotencia.IniciarLimpeza(context.Background(), time.Hour)
+	idempotente := idempotencia.New(chavesIdempotencia, 24*time.Hour)
+
 	r := chi.NewRouter()
+	r.Use(problema.Rastreamento)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
+	// Erros, inclusive de rotas inexistentes, são respondidos como application/problem+json.
+	r.NotFound(problema.NaoEncontrado)
+	r.MethodNotAllowed(problema.MetodoNaoPermitido)
 
 	// Rotas da API
-	r.Post("/pedidos", pedidoHandler.CriarPedidoHandler)
+	r.With(idempotente.Middleware).Post("/pedidos", pedidoHandler.CriarPedidoHandler)
 	r.Get("/pedidos/{id}", pedidoHandler.BuscarPedidoPorIDHandler)
 	r.Get("/pedidos", pedidoHandler.ListarTodosPedidos)
+	r.Post("/pedidos/{id}/pagar", pedidoHandler.PagarPedidoHandler)
+	r.Post("/pedidos/{id}/enviar", pedidoHandler.EnviarPedidoHandler)
+	r.Post("/pedidos/{id}/cancelar", pedidoHandler.CancelarPedidoHandler)
+	r.Get("/pedidos/{id}/historico", pedidoHandler.BuscarHistoricoHandler)
+	r.Get("/estoque/{produto_id}", estoqueHandler.BuscarEstoqueHandler)
+	r.Put("/estoque/{produto_id}", estoqueHandler.DefinirEstoqueHandler)
+	r.Post("/webhooks", webhookHandler.CriarAssinaturaHandler)
+	r.Get("/webhooks", webhookHandler.ListarAssinaturasHandler)
+	r.Get("/webhooks/{id}", webhookHandler.BuscarAssinaturaHandler)
+	r.Put("/webhooks/{id}", webhookHandler.AtualizarAssinaturaHandler)
+	r.Delete("/webhooks/{id}", webhookHandler.ExcluirAssinaturaHandler)
+	r.Get("/webhooks/{id}/entregas", webhookHandler.ListarEntregasHandler)
+	r.Get("/webhooks/{id}/entregas/{entregaId}", webhookHandler.BuscarEntregaHandler)
+	r.Post("/webhooks/{id}/entregas/{entregaId}/reenviar", webhookHandler.ReenviarEntregaHandler)
+	r.Post("/eventos/clientes", eventoHandler.ReceberEventoClienteHandler)
 
 	// Rota para a documentação do Swagger (AGORA CORRIGIDA)
 	r.Get("/swagger/*", httpSwagger.Handler())
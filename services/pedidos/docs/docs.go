@@ -15,42 +15,253 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/estoque/{produto_id}": {
+            "get": {
+                "description": "Retorna a quantidade disponível (não reservada) de um produto.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "estoque"
+                ],
+                "summary": "Consulta o estoque de um produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Produto",
+                        "name": "produto_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_application.SaldoEstoque"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao consultar estoque",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Define a quantidade disponível de um produto. Reservas já feitas não são afetadas.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "estoque"
+                ],
+                "summary": "Ajusta o estoque de um produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Produto",
+                        "name": "produto_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nova quantidade disponível",
+                        "name": "estoque",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_application.EstoqueInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_application.SaldoEstoque"
+                        }
+                    },
+                    "400": {
+                        "description": "Corpo da requisição inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "422": {
+                        "description": "Quantidade inválida",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao ajustar estoque",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/eventos/clientes": {
+            "post": {
+                "description": "Chamado pelo relay da outbox do serviço de clientes, que repete o evento com espera exponencial\nenquanto a resposta não for 2xx. cliente.mesclado transfere os pedidos do cliente absorvido\npara o que permaneceu; receber o mesmo evento de novo não muda nada. Outros tipos são ignorados.\nA requisição é assinada com o segredo EVENTOS_SEGREDO: X-Signature-Timestamp (segundos Unix, até 5\nminutos de diferença) e X-Signature = \"sha256=\" + HMAC-SHA256 em hexadecimal de timestamp + \".\" + corpo.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eventos"
+                ],
+                "summary": "Recebe um evento do serviço de clientes",
+                "parameters": [
+                    {
+                        "description": "Evento da outbox do serviço de clientes",
+                        "name": "evento",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_application.EventoClienteInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Segundos Unix da assinatura",
+                        "name": "X-Signature-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "sha256= + HMAC-SHA256 do timestamp, de um ponto e do corpo",
+                        "name": "X-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Evento aplicado ou ignorado"
+                    },
+                    "400": {
+                        "description": "Corpo da requisição inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "401": {
+                        "description": "Assinatura ausente, inválida ou expirada",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "422": {
+                        "description": "Evento sem identificação ou com clientes inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao aplicar o evento",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
         "/pedidos": {
             "get": {
-                "description": "Retorna pedidos e seus itens.",
+                "description": "Retorna uma página de pedidos com seus itens, do mais novo para o mais antigo.\nPara a próxima página, repita a chamada com cursor igual ao next_cursor recebido (null na última página).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "pedidos"
                 ],
-                "summary": "Lista todos pedidos",
+                "summary": "Lista pedidos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tamanho da página (1 a 100, padrão 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor da página anterior",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Somente pedidos deste cliente",
+                        "name": "cliente_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "aguardando_pagamento",
+                            "pago",
+                            "enviado",
+                            "cancelado"
+                        ],
+                        "type": "string",
+                        "description": "Somente pedidos neste status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Criados a partir desta data (RFC 3339 ou AAAA-MM-DD)",
+                        "name": "criado_de",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Criados antes desta data (RFC 3339 ou AAAA-MM-DD)",
+                        "name": "criado_ate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Total mínimo, em centavos",
+                        "name": "total_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Total máximo, em centavos",
+                        "name": "total_max",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/ecommerce_pedidos_internal_domain.Pedido"
-                            }
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_application.PaginaPedidosOutput"
                         }
                     },
-                    "404": {
-                        "description": "Sem pedidos na base",
+                    "400": {
+                        "description": "Filtro, limite ou cursor inválido",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problema.Problema"
                         }
                     },
                     "500": {
                         "description": "Erro interno ao listar pedidos",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problema.Problema"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Cria um novo pedido com base nos dados do cliente e itens fornecidos.",
+                "description": "Cria um novo pedido com base nos dados do cliente e itens fornecidos.\nA entrega pode referenciar um endereço do cliente (endereco_id) ou trazer o endereço completo.",
                 "consumes": [
                     "application/json"
                 ],
@@ -62,6 +273,12 @@ const docTemplate = `{
                 ],
                 "summary": "Cria um novo pedido",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chave para repetir a requisição sem criar outro pedido",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
                     {
                         "description": "Dados para criação do pedido",
                         "name": "pedido",
@@ -74,65 +291,723 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created"
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_domain.Pedido"
+                        }
+                    },
+                    "400": {
+                        "description": "Corpo da requisição inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "409": {
+                        "description": "Estoque insuficiente, ou Idempotency-Key ainda em andamento",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "422": {
+                        "description": "Cliente, endereço de entrega ou produto inexistente/indisponível, ou Idempotency-Key já usada com outro corpo",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao criar pedido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "503": {
+                        "description": "Serviço de clientes ou de catálogo indisponível",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}": {
+            "get": {
+                "description": "Retorna os detalhes de um pedido específico com base no seu UUID.\nA resposta traz a ETag da versão do pedido; com If-None-Match igual a ela, a resposta é 304 sem corpo.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Busca um pedido por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de uma resposta anterior",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_domain.Pedido"
+                        }
+                    },
+                    "304": {
+                        "description": "Pedido não mudou desde a ETag informada"
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao buscar pedido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/cancelar": {
+            "post": {
+                "description": "Cancela um pedido que ainda não foi enviado.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Cancela um pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag da versão do pedido lida pelo chamador",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Motivo da mudança de status",
+                        "name": "alteracao",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_application.AlteracaoStatusInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_domain.Pedido"
+                        }
+                    },
+                    "400": {
+                        "description": "Corpo da requisição ou If-Match inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "409": {
+                        "description": "Transição de status não permitida ou pedido alterado por outra requisição",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "412": {
+                        "description": "O pedido não está mais na versão do If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao alterar o status do pedido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/enviar": {
+            "post": {
+                "description": "Marca um pedido pago como enviado.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Envia um pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag da versão do pedido lida pelo chamador",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Motivo da mudança de status",
+                        "name": "alteracao",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_application.AlteracaoStatusInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_domain.Pedido"
+                        }
+                    },
+                    "400": {
+                        "description": "Corpo da requisição ou If-Match inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "409": {
+                        "description": "Transição de status não permitida ou pedido alterado por outra requisição",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "412": {
+                        "description": "O pedido não está mais na versão do If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao alterar o status do pedido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/historico": {
+            "get": {
+                "description": "Retorna a linha do tempo de mudanças de status do pedido: quando, de qual status, para qual, quem e por quê.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Histórico de status do pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ecommerce_pedidos_internal_domain.MudancaStatus"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao buscar histórico",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/pedidos/{id}/pagar": {
+            "post": {
+                "description": "Confirma o pagamento de um pedido aguardando pagamento.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pedidos"
+                ],
+                "summary": "Paga um pedido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Pedido (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag da versão do pedido lida pelo chamador",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Motivo da mudança de status",
+                        "name": "alteracao",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_application.AlteracaoStatusInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_domain.Pedido"
+                        }
+                    },
+                    "400": {
+                        "description": "Corpo da requisição ou If-Match inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "404": {
+                        "description": "Pedido não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "409": {
+                        "description": "Transição de status não permitida, reserva de estoque expirada ou pedido alterado por outra requisição",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "412": {
+                        "description": "O pedido não está mais na versão do If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao alterar o status do pedido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "Retorna todas as assinaturas, sem os segredos.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Lista as assinaturas de webhook",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ecommerce_pedidos_internal_application.AssinaturaOutput"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao listar as assinaturas",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Cadastra uma URL para receber eventos de pedido (pedido.criado, pedido.pago, pedido.enviado,\npedido.cancelado ou pedido.* para todos) por POST com o evento em JSON no corpo.\nCada requisição leva os cabeçalhos X-Webhook-Event, X-Webhook-Delivery, X-Webhook-Timestamp e\nX-Webhook-Signature = \"sha256=\" + HMAC-SHA256 em hexadecimal, com o segredo, de timestamp + \".\" + corpo.\nRespostas fora de 2xx são repetidas com espera exponencial; redirecionamentos não são seguidos.\nA URL precisa apontar para um endereço público: localhost, IPs privados ou link-local e nomes\ninternos são recusados. Sem segredo, um é gerado; ele só é devolvido nesta resposta.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Cria uma assinatura de webhook",
+                "parameters": [
+                    {
+                        "description": "Destino, eventos e segredo opcional",
+                        "name": "assinatura",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_application.AssinaturaInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_application.AssinaturaOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Corpo da requisição inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "422": {
+                        "description": "URL, eventos ou segredo inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao criar a assinatura",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "description": "Retorna uma assinatura pelo ID, sem o segredo.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Busca uma assinatura de webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da assinatura",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_application.AssinaturaOutput"
+                        }
+                    },
+                    "404": {
+                        "description": "Assinatura não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao buscar a assinatura",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Substitui URL e eventos. \"ativa\" ausente mantém a situação atual e \"segredo\" ausente mantém o\nsegredo; um segredo novo passa a valer para as próximas tentativas, inclusive das entregas pendentes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Altera uma assinatura de webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da assinatura",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Novos dados da assinatura",
+                        "name": "assinatura",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_application.AssinaturaInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_application.AssinaturaOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Corpo da requisição inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "404": {
+                        "description": "Assinatura não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "422": {
+                        "description": "URL, eventos ou segredo inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao alterar a assinatura",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a assinatura com suas entregas; as pendentes não são mais enviadas.",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Exclui uma assinatura de webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da assinatura",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Assinatura excluída"
+                    },
+                    "404": {
+                        "description": "Assinatura não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao excluir a assinatura",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/entregas": {
+            "get": {
+                "description": "Retorna as entregas mais recentes, da mais nova para a mais antiga, com status e tentativas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Lista as entregas de uma assinatura",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da assinatura",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Quantidade de entregas (1 a 100, padrão 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ecommerce_pedidos_internal_application.EntregaOutput"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Limite inválido",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
                     },
-                    "400": {
-                        "description": "Corpo da requisição inválido",
+                    "404": {
+                        "description": "Assinatura não encontrada",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problema.Problema"
                         }
                     },
                     "500": {
-                        "description": "Erro interno ao criar pedido",
+                        "description": "Erro interno ao listar as entregas",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problema.Problema"
                         }
                     }
                 }
             }
         },
-        "/pedidos/{id}": {
+        "/webhooks/{id}/entregas/{entregaId}": {
             "get": {
-                "description": "Retorna os detalhes de um pedido específico com base no seu UUID.",
+                "description": "Retorna a entrega com o payload enviado e o histórico de tentativas (status HTTP, erro e duração).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "pedidos"
+                    "webhooks"
                 ],
-                "summary": "Busca um pedido por ID",
+                "summary": "Busca uma entrega de webhook",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do Pedido (UUID)",
+                        "description": "ID da assinatura",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID da entrega",
+                        "name": "entregaId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ecommerce_pedidos_internal_domain.Pedido"
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_application.EntregaOutput"
                         }
                     },
-                    "400": {
-                        "description": "O ID do pedido é obrigatório",
+                    "404": {
+                        "description": "Entrega não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/problema.Problema"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno ao buscar a entrega",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problema.Problema"
                         }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/entregas/{entregaId}/reenviar": {
+            "post": {
+                "description": "Devolve a entrega à fila com as tentativas zeradas, inclusive se já foi entregue ou falhou.\nO envio é assíncrono; acompanhe o resultado pela consulta da entrega.",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Reenvia uma entrega de webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da assinatura",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID da entrega",
+                        "name": "entregaId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Entrega agendada para reenvio"
                     },
                     "404": {
-                        "description": "Pedido não encontrado",
+                        "description": "Entrega não encontrada",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problema.Problema"
                         }
                     },
                     "500": {
-                        "description": "Erro interno ao buscar pedido",
+                        "description": "Erro interno ao reenviar a entrega",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/problema.Problema"
                         }
                     }
                 }
@@ -140,6 +1015,155 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "ecommerce_pedidos_internal_application.AlteracaoStatusInput": {
+            "type": "object",
+            "properties": {
+                "motivo": {
+                    "type": "string"
+                }
+            }
+        },
+        "ecommerce_pedidos_internal_application.AssinaturaInput": {
+            "type": "object",
+            "properties": {
+                "ativa": {
+                    "type": "boolean"
+                },
+                "eventos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "segredo": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "ecommerce_pedidos_internal_application.AssinaturaOutput": {
+            "type": "object",
+            "properties": {
+                "ativa": {
+                    "type": "boolean"
+                },
+                "atualizado_em": {
+                    "type": "string"
+                },
+                "criado_em": {
+                    "type": "string"
+                },
+                "eventos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "segredo": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "ecommerce_pedidos_internal_application.EnderecoEntregaInput": {
+            "type": "object",
+            "properties": {
+                "cep": {
+                    "type": "string"
+                },
+                "cidade": {
+                    "type": "string"
+                },
+                "endereco_id": {
+                    "type": "integer"
+                },
+                "estado": {
+                    "type": "string"
+                },
+                "rua": {
+                    "type": "string"
+                }
+            }
+        },
+        "ecommerce_pedidos_internal_application.EntregaOutput": {
+            "type": "object",
+            "properties": {
+                "criado_em": {
+                    "type": "string"
+                },
+                "entregue_em": {
+                    "type": "string"
+                },
+                "evento_id": {
+                    "type": "string"
+                },
+                "historico": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ecommerce_pedidos_internal_application.TentativaOutput"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "proxima_tentativa_em": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tentativas": {
+                    "type": "integer"
+                },
+                "tipo": {
+                    "type": "string"
+                }
+            }
+        },
+        "ecommerce_pedidos_internal_application.EstoqueInput": {
+            "type": "object",
+            "properties": {
+                "disponivel": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ecommerce_pedidos_internal_application.EventoClienteInput": {
+            "type": "object",
+            "properties": {
+                "agregado": {
+                    "type": "string"
+                },
+                "agregado_id": {
+                    "type": "string"
+                },
+                "dados": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ocorrido_em": {
+                    "type": "string"
+                },
+                "tipo": {
+                    "type": "string"
+                }
+            }
+        },
         "ecommerce_pedidos_internal_application.ItensInput": {
             "type": "object",
             "properties": {
@@ -147,7 +1171,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "preco": {
-                    "type": "number"
+                    "$ref": "#/definitions/money.Money"
                 },
                 "produto_id": {
                     "type": "string"
@@ -157,6 +1181,70 @@ const docTemplate = `{
                 }
             }
         },
+        "ecommerce_pedidos_internal_application.PaginaPedidosOutput": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "pedidos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ecommerce_pedidos_internal_domain.Pedido"
+                    }
+                }
+            }
+        },
+        "ecommerce_pedidos_internal_application.SaldoEstoque": {
+            "type": "object",
+            "properties": {
+                "disponivel": {
+                    "type": "integer"
+                },
+                "produto_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "ecommerce_pedidos_internal_application.TentativaOutput": {
+            "type": "object",
+            "properties": {
+                "duracao_ms": {
+                    "type": "integer"
+                },
+                "erro": {
+                    "type": "string"
+                },
+                "ocorrido_em": {
+                    "type": "string"
+                },
+                "status_http": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ecommerce_pedidos_internal_domain.EnderecoEntrega": {
+            "type": "object",
+            "properties": {
+                "cep": {
+                    "type": "string"
+                },
+                "cidade": {
+                    "type": "string"
+                },
+                "enderecoOrigemID": {
+                    "description": "ID do endereço no serviço de clientes; zero quando informado direto no pedido.",
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "estado": {
+                    "type": "string"
+                },
+                "rua": {
+                    "type": "string"
+                }
+            }
+        },
         "ecommerce_pedidos_internal_domain.Item": {
             "type": "object",
             "properties": {
@@ -167,8 +1255,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "preco": {
-                    "type": "number",
-                    "format": "float64"
+                    "$ref": "#/definitions/money.Money"
                 },
                 "produtoID": {
                     "type": "string"
@@ -178,6 +1265,34 @@ const docTemplate = `{
                 }
             }
         },
+        "ecommerce_pedidos_internal_domain.MudancaStatus": {
+            "type": "object",
+            "properties": {
+                "autor": {
+                    "type": "string"
+                },
+                "motivo": {
+                    "type": "string"
+                },
+                "ocorridoEm": {
+                    "type": "string"
+                },
+                "pedidoID": {
+                    "type": "string"
+                },
+                "statusAnterior": {
+                    "description": "Vazio na criação do pedido.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ecommerce_pedidos_internal_domain.Status"
+                        }
+                    ]
+                },
+                "statusNovo": {
+                    "$ref": "#/definitions/ecommerce_pedidos_internal_domain.Status"
+                }
+            }
+        },
         "ecommerce_pedidos_internal_domain.Pedido": {
             "type": "object",
             "properties": {
@@ -190,6 +1305,9 @@ const docTemplate = `{
                 "criadoEm": {
                     "type": "string"
                 },
+                "entrega": {
+                    "$ref": "#/definitions/ecommerce_pedidos_internal_domain.EnderecoEntrega"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -199,12 +1317,19 @@ const docTemplate = `{
                         "$ref": "#/definitions/ecommerce_pedidos_internal_domain.Item"
                     }
                 },
+                "reservaID": {
+                    "description": "Reserva de estoque feita na criação do pedido.",
+                    "type": "string"
+                },
                 "status": {
                     "$ref": "#/definitions/ecommerce_pedidos_internal_domain.Status"
                 },
                 "total": {
-                    "type": "number",
-                    "format": "float64"
+                    "$ref": "#/definitions/money.Money"
+                },
+                "versao": {
+                    "description": "Versao começa em 1 e é incrementada pelo repositório a cada alteração\ngravada; é a base do controle de concorrência otimista.",
+                    "type": "integer"
                 }
             }
         },
@@ -229,6 +1354,9 @@ const docTemplate = `{
                 "cliente_id": {
                     "type": "string"
                 },
+                "entrega": {
+                    "$ref": "#/definitions/ecommerce_pedidos_internal_application.EnderecoEntregaInput"
+                },
                 "itens": {
                     "type": "array",
                     "items": {
@@ -236,6 +1364,72 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "money.Money": {
+            "type": "object",
+            "properties": {
+                "moeda": {
+                    "description": "Código ISO 4217, ex.: \"BRL\".",
+                    "type": "string"
+                },
+                "valor": {
+                    "description": "Em unidades menores da moeda (centavos para BRL).",
+                    "type": "integer"
+                }
+            }
+        },
+        "problema.ErroCampo": {
+            "type": "object",
+            "properties": {
+                "campo": {
+                    "type": "string"
+                },
+                "mensagem": {
+                    "type": "string"
+                },
+                "regra": {
+                    "description": "Código estável da regra violada, como \"obrigatorio\".",
+                    "type": "string"
+                }
+            }
+        },
+        "problema.Problema": {
+            "type": "object",
+            "properties": {
+                "campos": {
+                    "description": "Erros de validação, um por campo.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/problema.ErroCampo"
+                    }
+                },
+                "codigo": {
+                    "description": "Código estável, como \"pedido_nao_encontrado\".",
+                    "type": "string"
+                },
+                "detail": {
+                    "description": "Explicação desta ocorrência.",
+                    "type": "string"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status HTTP da resposta.",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Resumo fixo do problema, igual para todas as ocorrências.",
+                    "type": "string"
+                },
+                "trace_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "URI que identifica o problema: \"urn:problema:\" + código.",
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,46 @@
+package application
+
+import (
+	"context"
+	"ecommerce/pedidos/internal/domain"
+)
+
+// EstoqueService é a implementação dos casos de uso administrativos de estoque.
+type EstoqueService struct {
+	repo domain.EstoqueRepository
+}
+
+// NewEstoqueService é o construtor do serviço de estoque.
+func NewEstoqueService(repo domain.EstoqueRepository) *EstoqueService {
+	return &EstoqueService{
+		repo: repo,
+	}
+}
+
+// EstoqueInput é o DTO para ajustar o saldo disponível de um produto.
+type EstoqueInput struct {
+	Disponivel int `json:"disponivel"`
+}
+
+// SaldoEstoque é o DTO de resposta com o saldo disponível de um produto.
+type SaldoEstoque struct {
+	ProdutoID  string `json:"produto_id"`
+	Disponivel int    `json:"disponivel"`
+}
+
+// DefinirDisponivel é o caso de uso para ajustar o saldo de um produto (ex.: após inventário).
+func (s *EstoqueService) DefinirDisponivel(ctx context.Context, produtoID string, input EstoqueInput) (*SaldoEstoque, error) {
+	if err := s.repo.DefinirDisponivel(ctx, produtoID, input.Disponivel); err != nil {
+		return nil, err
+	}
+	return &SaldoEstoque{ProdutoID: produtoID, Disponivel: input.Disponivel}, nil
+}
+
+// BuscarDisponivel é o caso de uso para consultar o saldo de um produto.
+func (s *EstoqueService) BuscarDisponivel(ctx context.Context, produtoID string) (*SaldoEstoque, error) {
+	disponivel, err := s.repo.BuscarDisponivel(ctx, produtoID)
+	if err != nil {
+		return nil, err
+	}
+	return &SaldoEstoque{ProdutoID: produtoID, Disponivel: disponivel}, nil
+}
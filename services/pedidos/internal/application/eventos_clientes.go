@@ -0,0 +1,51 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// EventoClienteMesclado é publicado pelo serviço de clientes quando dois
+// cadastros são unidos: os pedidos do absorvido passam para o que ficou.
+const EventoClienteMesclado = "cliente.mesclado"
+
+// EventoClienteInput é um evento do serviço de clientes no formato da outbox
+// dele (pkg/outbox), entregue pelo relay daquele serviço.
+type EventoClienteInput struct {
+	ID         string          `json:"id"`
+	Tipo       string          `json:"tipo"`
+	Agregado   string          `json:"agregado"`
+	AgregadoID string          `json:"agregado_id"`
+	Dados      json.RawMessage `json:"dados" swaggertype:"object"`
+	OcorridoEm time.Time       `json:"ocorrido_em"`
+}
+
+// dadosClienteMesclado é o conteúdo de cliente.mesclado: cliente_id foi
+// absorvido por mesclado_com.
+type dadosClienteMesclado struct {
+	ClienteID   string `json:"cliente_id"`
+	MescladoCom string `json:"mesclado_com"`
+}
+
+// TratarEventoCliente é o caso de uso que aplica um evento do serviço de
+// clientes. Só cliente.mesclado altera pedidos; os demais tipos são aceitos e
+// ignorados, para que o relay de clientes não os repita.
+//
+// O relay entrega cada evento ao menos uma vez e repete enquanto houver erro,
+// então o tratamento pode receber o mesmo evento de novo: a transferência
+// repetida não encontra mais pedidos do cliente absorvido e não muda nada.
+// Mesclagens de clientes diferentes podem chegar fora de ordem: A→B recebida
+// depois de B→C leva os pedidos de A até C (ver TransferirCliente).
+func (s *PedidoService) TratarEventoCliente(ctx context.Context, evento EventoClienteInput) error {
+	if evento.Tipo != EventoClienteMesclado {
+		return nil
+	}
+
+	// O formato já foi conferido por Validar.
+	var dados dadosClienteMesclado
+	if err := json.Unmarshal(evento.Dados, &dados); err != nil {
+		return err
+	}
+	return s.TransferirPedidosDoCliente(ctx, dados.ClienteID, dados.MescladoCom)
+}
@@ -0,0 +1,50 @@
+package application
+
+import (
+	"ecommerce/pedidos/internal/domain"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Limites de tamanho de página da listagem de pedidos.
+const (
+	limitePadrao = 20
+	limiteMaximo = 100
+)
+
+// codificarCursor transforma a posição do último pedido da página em um
+// cursor opaco para o cliente da API.
+func codificarCursor(c *domain.CursorPedido) *string {
+	if c == nil {
+		return nil
+	}
+	cursor := base64.RawURLEncoding.EncodeToString([]byte(c.CriadoEm.UTC().Format(time.RFC3339Nano) + "|" + c.ID))
+	return &cursor
+}
+
+// decodificarCursor faz o caminho inverso de codificarCursor.
+func decodificarCursor(cursor string) (*domain.CursorPedido, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	texto, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cursor inválido", domain.ErrFiltroInvalido)
+	}
+	criadoEmTexto, id, ok := strings.Cut(string(texto), "|")
+	criadoEm, err := time.Parse(time.RFC3339Nano, criadoEmTexto)
+	if !ok || err != nil {
+		return nil, fmt.Errorf("%w: cursor inválido", domain.ErrFiltroInvalido)
+	}
+	// O ID vai direto para a query: um cursor adulterado não pode chegar ao banco.
+	pedidoID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cursor inválido", domain.ErrFiltroInvalido)
+	}
+	return &domain.CursorPedido{CriadoEm: criadoEm, ID: pedidoID.String()}, nil
+}
@@ -3,57 +3,313 @@ package application
 import (
 	"context"
 	"ecommerce/pedidos/internal/domain"
+	"ecommerce/pkg/money"
+	"fmt"
+	"log"
+	"time"
 )
 
 // PedidoService é a implementação dos nossos casos de uso de pedido.
 type PedidoService struct {
-	repo domain.PedidoRepository
+	repo     domain.PedidoRepository
+	catalogo domain.CatalogoClient
+	estoque  domain.Estoque
+	clientes domain.ClienteGateway
+	tx       domain.Transacao
 }
 
-// NewPedidoService é o construtor do nosso serviço de aplicação.
-func NewPedidoService(repo domain.PedidoRepository) *PedidoService {
+// NewPedidoService é o construtor do nosso serviço de aplicação. tx une numa
+// transação a mudança de status do pedido e o efeito dela no estoque.
+func NewPedidoService(repo domain.PedidoRepository, catalogo domain.CatalogoClient, estoque domain.Estoque, clientes domain.ClienteGateway, tx domain.Transacao) *PedidoService {
 	return &PedidoService{
-		repo: repo,
+		repo:     repo,
+		catalogo: catalogo,
+		estoque:  estoque,
+		clientes: clientes,
+		tx:       tx,
 	}
 }
 
 // ItensInput é um DTO para os itens na criação do pedido.
+// Nome e Preco são ignorados: o catálogo é a fonte da verdade. Eles continuam
+// aceitos (Preco como {"valor": 1990, "moeda": "BRL"} ou o decimal 19.90)
+// apenas para não quebrar clientes antigos.
 type ItensInput struct {
-	ProdutoID  string  `json:"produto_id"`
-	Nome       string  `json:"nome"`
-	Preco      float64 `json:"preco"`
-	Quantidade int     `json:"quantidade"`
+	ProdutoID  string      `json:"produto_id"`
+	Nome       string      `json:"nome"`
+	Preco      money.Money `json:"preco"`
+	Quantidade int         `json:"quantidade"`
+}
+
+// EnderecoEntregaInput é o DTO do endereço de entrega do pedido: ou a referência
+// a um endereço do cliente (EnderecoID) ou o endereço completo informado direto.
+type EnderecoEntregaInput struct {
+	EnderecoID int64  `json:"endereco_id,omitempty"`
+	Rua        string `json:"rua,omitempty"`
+	Cidade     string `json:"cidade,omitempty"`
+	Estado     string `json:"estado,omitempty"`
+	CEP        string `json:"cep,omitempty"`
 }
 
 // CriarPedido é o caso de uso para criar um novo pedido.
-func (s *PedidoService) CriarPedido(ctx context.Context, clienteID string, itensInput []ItensInput) (*domain.Pedido, error) {
+// O cliente precisa existir no serviço de clientes, nome e preço de cada item são
+// resolvidos no catálogo e as quantidades são reservadas no estoque antes de o
+// pedido ser gravado. O endereço de entrega é copiado para o pedido.
+func (s *PedidoService) CriarPedido(ctx context.Context, clienteID string, itensInput []ItensInput, entregaInput EnderecoEntregaInput) (*domain.Pedido, error) {
+	if clienteID == "" {
+		return nil, domain.ErrClienteNaoEncontrado
+	}
+	existe, err := s.clientes.ClienteExiste(ctx, clienteID)
+	if err != nil {
+		return nil, err
+	}
+	if !existe {
+		return nil, domain.ErrClienteNaoEncontrado
+	}
+
+	entrega, err := s.resolverEntrega(ctx, clienteID, entregaInput)
+	if err != nil {
+		return nil, err
+	}
+
 	var itensDominio []*domain.Item
 	for _, itemInput := range itensInput {
+		produto, err := s.catalogo.BuscarProduto(ctx, itemInput.ProdutoID)
+		if err != nil {
+			return nil, err
+		}
+		if !produto.Ativo {
+			return nil, domain.ErrProdutoIndisponivel
+		}
+
 		itensDominio = append(itensDominio, &domain.Item{
-			ProdutoID:  itemInput.ProdutoID,
-			Nome:       itemInput.Nome,
-			Preco:      itemInput.Preco,
+			ProdutoID:  produto.ID,
+			Nome:       produto.Nome,
+			Preco:      produto.Preco,
 			Quantidade: itemInput.Quantidade,
 		})
 	}
 
-	novoPedido, err := domain.NewPedido(clienteID, itensDominio)
+	novoPedido, err := domain.NewPedido(clienteID, itensDominio, entrega)
+	if err != nil {
+		return nil, err
+	}
+
+	var itensReserva []domain.ItemReserva
+	for _, item := range novoPedido.Itens {
+		itensReserva = append(itensReserva, domain.ItemReserva{ProdutoID: item.ProdutoID, Quantidade: item.Quantidade})
+	}
+	novoPedido.ReservaID, err = s.estoque.Reservar(ctx, itensReserva)
 	if err != nil {
 		return nil, err
 	}
 
 	err = s.repo.Save(ctx, novoPedido)
 	if err != nil {
+		// Compensa a reserva: sem o pedido gravado, ninguém mais a liberaria.
+		if errLiberar := s.estoque.Liberar(ctx, novoPedido.ReservaID); errLiberar != nil {
+			log.Printf("Erro ao liberar a reserva %s após falha ao gravar o pedido: %v", novoPedido.ReservaID, errLiberar)
+		}
 		return nil, err
 	}
 
 	return novoPedido, nil
 }
 
+// resolverEntrega busca no serviço de clientes o endereço referenciado ou usa o
+// endereço informado direto na requisição.
+func (s *PedidoService) resolverEntrega(ctx context.Context, clienteID string, input EnderecoEntregaInput) (domain.EnderecoEntrega, error) {
+	if input.EnderecoID == 0 {
+		return domain.EnderecoEntrega{
+			Rua:    input.Rua,
+			Cidade: input.Cidade,
+			Estado: input.Estado,
+			CEP:    input.CEP,
+		}, nil
+	}
+
+	endereco, err := s.clientes.BuscarEndereco(ctx, clienteID, input.EnderecoID)
+	if err != nil {
+		return domain.EnderecoEntrega{}, err
+	}
+	return *endereco, nil
+}
+
 func (s *PedidoService) BuscarPedidoPorID(ctx context.Context, id string) (*domain.Pedido, error) {
 	return s.repo.FindByID(ctx, id)
 }
 
-func (s *PedidoService) ListarPedidos(ctx context.Context) ([]*domain.Pedido, error) {
-	return s.repo.ListAll(ctx)
+// ListarPedidos é o caso de uso que lista os pedidos em páginas, com filtros opcionais.
+func (s *PedidoService) ListarPedidos(ctx context.Context, input ListarPedidosInput) (*PaginaPedidosOutput, error) {
+	filtro, err := input.paraFiltro()
+	if err != nil {
+		return nil, err
+	}
+
+	// Com cliente_id, a busca vai direto ao histórico do cliente.
+	var pagina *domain.PaginaPedidos
+	if filtro.ClienteID != "" {
+		pagina, err = s.repo.FindByClienteID(ctx, filtro.ClienteID, filtro)
+	} else {
+		pagina, err = s.repo.List(ctx, filtro)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaginaPedidosOutput{
+		Pedidos:    pagina.Pedidos,
+		NextCursor: codificarCursor(pagina.Proximo),
+	}, nil
+}
+
+// ListarPedidosInput são os filtros e a posição da página na listagem de pedidos.
+// Campos vazios não filtram; Limite zero usa o tamanho de página padrão.
+type ListarPedidosInput struct {
+	ClienteID string
+	Status    string
+	CriadoDe  time.Time
+	CriadoAte time.Time
+	TotalMin  *int64 // Em centavos.
+	TotalMax  *int64 // Em centavos.
+	Cursor    string // next_cursor da página anterior.
+	Limite    int
+}
+
+// PaginaPedidosOutput é o envelope de uma página da listagem de pedidos.
+// NextCursor é null na última página.
+type PaginaPedidosOutput struct {
+	Pedidos    []*domain.Pedido `json:"pedidos"`
+	NextCursor *string          `json:"next_cursor"`
+}
+
+func (in ListarPedidosInput) paraFiltro() (domain.FiltroPedidos, error) {
+	filtro := domain.FiltroPedidos{
+		ClienteID: in.ClienteID,
+		Status:    domain.Status(in.Status),
+		CriadoDe:  in.CriadoDe,
+		CriadoAte: in.CriadoAte,
+		TotalMin:  in.TotalMin,
+		TotalMax:  in.TotalMax,
+		Limite:    in.Limite,
+	}
+
+	if filtro.Status != "" && !filtro.Status.Valido() {
+		return filtro, fmt.Errorf("%w: status %q desconhecido", domain.ErrFiltroInvalido, in.Status)
+	}
+	if !in.CriadoDe.IsZero() && !in.CriadoAte.IsZero() && !in.CriadoDe.Before(in.CriadoAte) {
+		return filtro, fmt.Errorf("%w: criado_de deve ser anterior a criado_ate", domain.ErrFiltroInvalido)
+	}
+	if in.TotalMin != nil && in.TotalMax != nil && *in.TotalMin > *in.TotalMax {
+		return filtro, fmt.Errorf("%w: total_min maior que total_max", domain.ErrFiltroInvalido)
+	}
+
+	switch {
+	case filtro.Limite == 0:
+		filtro.Limite = limitePadrao
+	case filtro.Limite < 0 || filtro.Limite > limiteMaximo:
+		return filtro, fmt.Errorf("%w: limit deve estar entre 1 e %d", domain.ErrFiltroInvalido, limiteMaximo)
+	}
+
+	apos, err := decodificarCursor(in.Cursor)
+	if err != nil {
+		return filtro, err
+	}
+	filtro.Apos = apos
+	return filtro, nil
+}
+
+// AlteracaoStatusInput é o DTO com os dados de auditoria de uma mudança de status.
+type AlteracaoStatusInput struct {
+	Autor  string `json:"-"` // Preenchido a partir da identidade do chamador, não do corpo.
+	Motivo string `json:"motivo"`
+	Versao int    `json:"-"` // Versão lida pelo chamador (If-Match); zero não verifica.
+}
+
+// PagarPedido é o caso de uso que confirma o pagamento de um pedido
+// e, com ele, a reserva de estoque.
+func (s *PedidoService) PagarPedido(ctx context.Context, id string, input AlteracaoStatusInput) (*domain.Pedido, error) {
+	return s.alterarStatus(ctx, id, input.Versao,
+		func(p *domain.Pedido) error { return p.Pagar(input.Autor, input.Motivo) },
+		s.estoque.Confirmar,
+	)
+}
+
+// EnviarPedido é o caso de uso que marca um pedido pago como enviado.
+func (s *PedidoService) EnviarPedido(ctx context.Context, id string, input AlteracaoStatusInput) (*domain.Pedido, error) {
+	return s.alterarStatus(ctx, id, input.Versao,
+		func(p *domain.Pedido) error { return p.Enviar(input.Autor, input.Motivo) },
+		nil,
+	)
+}
+
+// CancelarPedido é o caso de uso que cancela um pedido e devolve os itens ao estoque.
+func (s *PedidoService) CancelarPedido(ctx context.Context, id string, input AlteracaoStatusInput) (*domain.Pedido, error) {
+	return s.alterarStatus(ctx, id, input.Versao,
+		func(p *domain.Pedido) error { return p.Cancelar(input.Autor, input.Motivo) },
+		s.estoque.Liberar,
+	)
+}
+
+// TransferirPedidosDoCliente é o caso de uso que acompanha a mesclagem de
+// clientes no serviço de clientes: os pedidos do cliente absorvido passam
+// para o cliente que permaneceu.
+func (s *PedidoService) TransferirPedidosDoCliente(ctx context.Context, deClienteID, paraClienteID string) error {
+	transferidos, err := s.repo.TransferirCliente(ctx, deClienteID, paraClienteID)
+	if err != nil {
+		return err
+	}
+	if transferidos > 0 {
+		log.Printf("%d pedido(s) transferido(s) na mesclagem do cliente %s em %s", transferidos, deClienteID, paraClienteID)
+	}
+	return nil
+}
+
+// BuscarHistorico é o caso de uso que retorna a linha do tempo de status de um pedido.
+func (s *PedidoService) BuscarHistorico(ctx context.Context, id string) ([]*domain.MudancaStatus, error) {
+	// Garante o 404 para pedidos inexistentes em vez de um histórico vazio.
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.FindHistorico(ctx, id)
+}
+
+// alterarStatus carrega o pedido, aplica a transição pedida, persiste o
+// resultado e, se houver, aplica o efeito da transição na reserva de estoque.
+// Transições não permitidas retornam domain.ErrStatusInvalido; um pedido fora da
+// versão esperada, ou alterado por outra requisição antes do Update, retorna
+// domain.ErrConflitoDeVersao.
+//
+// Tudo roda numa única transação, com o Update antes do estoque: uma requisição
+// que perde a corrida para outra (pagar e cancelar ao mesmo tempo) falha no
+// Update sem tocar na reserva, e uma falha no estoque desfaz o Update.
+func (s *PedidoService) alterarStatus(ctx context.Context, id string, versao int, transicao func(*domain.Pedido) error, reserva func(ctx context.Context, reservaID string) error) (*domain.Pedido, error) {
+	var pedido *domain.Pedido
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		// Lido dentro da transação: se ela for repetida, a transição parte do zero.
+		var err error
+		pedido, err = s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := pedido.VerificarVersao(versao); err != nil {
+			return err
+		}
+
+		if err := transicao(pedido); err != nil {
+			return err
+		}
+		if err := s.repo.Update(ctx, pedido); err != nil {
+			return err
+		}
+
+		if reserva == nil || pedido.ReservaID == "" {
+			return nil
+		}
+		return reserva(ctx, pedido.ReservaID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pedido, nil
 }
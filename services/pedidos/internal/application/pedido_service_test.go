@@ -0,0 +1,290 @@
+package application_test
+
+import (
+	"context"
+	"ecommerce/pedidos/internal/application"
+	"ecommerce/pedidos/internal/domain"
+	"ecommerce/pedidos/internal/infra/catalogo"
+	"ecommerce/pedidos/internal/infra/clientes"
+	"ecommerce/pedidos/internal/infra/estoque"
+	"ecommerce/pedidos/internal/infra/repository"
+	"ecommerce/pkg/money"
+	"errors"
+	"testing"
+)
+
+const (
+	clienteTeste = "cliente-1"
+	produtoTeste = "produto-1"
+)
+
+var entregaTeste = application.EnderecoEntregaInput{Rua: "Av. Paulista, 1000", Cidade: "São Paulo", Estado: "SP", CEP: "01310-100"}
+
+// ambienteTeste é o serviço de pedidos montado sobre os fakes em memória.
+type ambienteTeste struct {
+	service  *application.PedidoService
+	repo     domain.PedidoRepository
+	catalogo *catalogo.CatalogoEmMemoria
+	estoque  *estoque.EstoqueEmMemoria
+}
+
+// novoAmbiente monta o serviço; envolver, se informado, troca o repositório
+// visto pelo serviço (o ambiente continua com acesso ao original).
+func novoAmbiente(t *testing.T, envolver func(domain.PedidoRepository) domain.PedidoRepository) *ambienteTeste {
+	t.Helper()
+	amb := &ambienteTeste{
+		repo:     repository.NewPedidoRepositoryEmMemoria(),
+		catalogo: catalogo.NewCatalogoEmMemoria(domain.Produto{ID: produtoTeste, Nome: "Caneca", Preco: money.New(2990, "BRL"), Ativo: true}),
+		estoque:  estoque.NewEstoqueEmMemoria(map[string]int{produtoTeste: 10}),
+	}
+	repo := amb.repo
+	if envolver != nil {
+		repo = envolver(repo)
+	}
+	amb.service = application.NewPedidoService(repo, amb.catalogo, amb.estoque, clientes.NewClientesEmMemoria(clienteTeste), &repository.TransacaoEmMemoria{})
+	return amb
+}
+
+func (amb *ambienteTeste) criarPedido(t *testing.T, quantidade int) *domain.Pedido {
+	t.Helper()
+	pedido, err := amb.service.CriarPedido(context.Background(), clienteTeste,
+		[]application.ItensInput{{ProdutoID: produtoTeste, Quantidade: quantidade}}, entregaTeste)
+	if err != nil {
+		t.Fatalf("CriarPedido: %v", err)
+	}
+	return pedido
+}
+
+func (amb *ambienteTeste) disponivel(t *testing.T) int {
+	t.Helper()
+	disponivel, _ := amb.estoque.BuscarDisponivel(context.Background(), produtoTeste)
+	return disponivel
+}
+
+// repoConcorrente simula outra requisição que altera o pedido entre a leitura
+// e o Update: depois de cada FindByID, a versão gravada avança.
+type repoConcorrente struct {
+	domain.PedidoRepository
+}
+
+func (r repoConcorrente) FindByID(ctx context.Context, id string) (*domain.Pedido, error) {
+	pedido, err := r.PedidoRepository.FindByID(ctx, id)
+	if err == nil {
+		outra, _ := r.PedidoRepository.FindByID(ctx, id)
+		r.PedidoRepository.Update(ctx, outra)
+	}
+	return pedido, err
+}
+
+func TestAlteracaoDeStatusComVersaoDesatualizadaNaoMudaNada(t *testing.T) {
+	alteracoes := []struct {
+		nome    string
+		alterar func(*application.PedidoService, context.Context, string, application.AlteracaoStatusInput) (*domain.Pedido, error)
+	}{
+		{"pagar", (*application.PedidoService).PagarPedido},
+		{"cancelar", (*application.PedidoService).CancelarPedido},
+	}
+	cenarios := []struct {
+		nome   string
+		versao func(pedido *domain.Pedido) int
+		repo   func(domain.PedidoRepository) domain.PedidoRepository
+	}{
+		{
+			nome:   "If-Match com versão antiga",
+			versao: func(p *domain.Pedido) int { return p.Versao + 1 },
+			repo:   nil,
+		},
+		{
+			nome:   "pedido alterado por outra requisição antes do Update",
+			versao: func(*domain.Pedido) int { return 0 },
+			repo:   func(r domain.PedidoRepository) domain.PedidoRepository { return repoConcorrente{r} },
+		},
+	}
+
+	for _, alteracao := range alteracoes {
+		for _, cenario := range cenarios {
+			t.Run(alteracao.nome+"/"+cenario.nome, func(t *testing.T) {
+				amb := novoAmbiente(t, cenario.repo)
+				pedido := amb.criarPedido(t, 2)
+				if got := amb.disponivel(t); got != 8 {
+					t.Fatalf("disponível após reservar = %d, esperado 8", got)
+				}
+
+				_, err := alteracao.alterar(amb.service, context.Background(), pedido.ID,
+					application.AlteracaoStatusInput{Autor: "teste", Versao: cenario.versao(pedido)})
+				if !errors.Is(err, domain.ErrConflitoDeVersao) {
+					t.Fatalf("erro = %v, esperado ErrConflitoDeVersao", err)
+				}
+
+				if got := amb.disponivel(t); got != 8 {
+					t.Errorf("disponível = %d, esperado 8: a reserva não deveria mudar", got)
+				}
+				gravado, _ := amb.repo.FindByID(context.Background(), pedido.ID)
+				if gravado.Status != domain.StatusAguardandoPagamento {
+					t.Errorf("status = %s, esperado %s", gravado.Status, domain.StatusAguardandoPagamento)
+				}
+				// A reserva continua pendente: o pagamento ainda pode ser confirmado.
+				if err := amb.estoque.Confirmar(context.Background(), pedido.ReservaID); err != nil {
+					t.Errorf("reserva não está mais pendente: %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestCancelarDepoisDePagoDevolveOEstoqueUmaVez(t *testing.T) {
+	amb := novoAmbiente(t, nil)
+	pedido := amb.criarPedido(t, 3)
+	ctx := context.Background()
+
+	if _, err := amb.service.PagarPedido(ctx, pedido.ID, application.AlteracaoStatusInput{Autor: "teste"}); err != nil {
+		t.Fatalf("PagarPedido: %v", err)
+	}
+	if _, err := amb.service.CancelarPedido(ctx, pedido.ID, application.AlteracaoStatusInput{Autor: "teste"}); err != nil {
+		t.Fatalf("CancelarPedido: %v", err)
+	}
+	if _, err := amb.service.CancelarPedido(ctx, pedido.ID, application.AlteracaoStatusInput{Autor: "teste"}); !errors.Is(err, domain.ErrStatusInvalido) {
+		t.Fatalf("segundo cancelamento: erro = %v, esperado ErrStatusInvalido", err)
+	}
+	if got := amb.disponivel(t); got != 10 {
+		t.Errorf("disponível = %d, esperado 10", got)
+	}
+}
+
+func TestCriarPedidoPrecificaPeloCatalogo(t *testing.T) {
+	casos := []struct {
+		nome       string
+		produto    domain.Produto
+		item       application.ItensInput
+		erro       error
+		total      money.Money
+		disponivel int
+	}{
+		{
+			nome:       "nome e preço vêm do catálogo, não do cliente",
+			produto:    domain.Produto{ID: "p1", Nome: "Caneca", Preco: money.New(2990, "BRL"), Ativo: true},
+			item:       application.ItensInput{ProdutoID: "p1", Nome: "Outro nome", Preco: money.New(1, "BRL"), Quantidade: 3},
+			total:      money.New(8970, "BRL"),
+			disponivel: 7,
+		},
+		{
+			nome:       "produto desativado não é vendido",
+			produto:    domain.Produto{ID: "p1", Nome: "Caneca", Preco: money.New(2990, "BRL"), Ativo: false},
+			item:       application.ItensInput{ProdutoID: "p1", Quantidade: 1},
+			erro:       domain.ErrProdutoIndisponivel,
+			disponivel: 10,
+		},
+		{
+			nome:       "produto fora do catálogo",
+			produto:    domain.Produto{ID: "p1", Nome: "Caneca", Preco: money.New(2990, "BRL"), Ativo: true},
+			item:       application.ItensInput{ProdutoID: "p2", Quantidade: 1},
+			erro:       domain.ErrProdutoNaoEncontrado,
+			disponivel: 10,
+		},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			est := estoque.NewEstoqueEmMemoria(map[string]int{"p1": 10, "p2": 10})
+			service := application.NewPedidoService(repository.NewPedidoRepositoryEmMemoria(), catalogo.NewCatalogoEmMemoria(c.produto),
+				est, clientes.NewClientesEmMemoria(clienteTeste), &repository.TransacaoEmMemoria{})
+
+			pedido, err := service.CriarPedido(context.Background(), clienteTeste, []application.ItensInput{c.item}, entregaTeste)
+			if !errors.Is(err, c.erro) {
+				t.Fatalf("erro = %v, esperado %v", err, c.erro)
+			}
+			if c.erro == nil {
+				if pedido.Total != c.total {
+					t.Errorf("total = %s, esperado %s", pedido.Total, c.total)
+				}
+				if item := pedido.Itens[0]; item.Nome != c.produto.Nome || item.Preco != c.produto.Preco {
+					t.Errorf("item = %s a %s, esperado %s a %s", item.Nome, item.Preco, c.produto.Nome, c.produto.Preco)
+				}
+			}
+			if got, _ := est.BuscarDisponivel(context.Background(), "p1"); got != c.disponivel {
+				t.Errorf("disponível = %d, esperado %d", got, c.disponivel)
+			}
+		})
+	}
+}
+
+func TestTratarEventoCliente(t *testing.T) {
+	const absorvido, permaneceu, depois = clienteTeste, "cliente-2", "cliente-3"
+	mesclagem := func(id, de, para string) application.EventoClienteInput {
+		return application.EventoClienteInput{
+			ID:    id,
+			Tipo:  application.EventoClienteMesclado,
+			Dados: []byte(`{"cliente_id":"` + de + `","mesclado_com":"` + para + `"}`),
+		}
+	}
+	mesclado := mesclagem("evento-1", absorvido, permaneceu)
+	// cliente-2 foi absorvido por cliente-3 depois de absorver o cliente de teste.
+	remesclado := mesclagem("evento-3", permaneceu, depois)
+	casos := []struct {
+		nome    string
+		eventos []application.EventoClienteInput
+		pedidos map[string]int // Pedidos de cada cliente depois dos eventos.
+	}{
+		{"mesclagem transfere os pedidos", []application.EventoClienteInput{mesclado}, map[string]int{absorvido: 0, permaneceu: 2}},
+		{"evento repetido não muda nada", []application.EventoClienteInput{mesclado, mesclado}, map[string]int{absorvido: 0, permaneceu: 2}},
+		{"mesclagens em ordem", []application.EventoClienteInput{mesclado, remesclado}, map[string]int{absorvido: 0, permaneceu: 0, depois: 2}},
+		{
+			nome:    "mesclagem que chega depois da seguinte vai até o fim da cadeia",
+			eventos: []application.EventoClienteInput{remesclado, mesclado},
+			pedidos: map[string]int{absorvido: 0, permaneceu: 0, depois: 2},
+		},
+		{
+			nome:    "outros tipos são ignorados",
+			eventos: []application.EventoClienteInput{{ID: "evento-2", Tipo: "cliente.cadastrado", Dados: []byte(`{}`)}},
+			pedidos: map[string]int{absorvido: 2, permaneceu: 0},
+		},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			amb := novoAmbiente(t, nil)
+			amb.criarPedido(t, 1)
+			amb.criarPedido(t, 1)
+
+			for _, evento := range c.eventos {
+				if err := amb.service.TratarEventoCliente(context.Background(), evento); err != nil {
+					t.Fatalf("TratarEventoCliente(%s): %v", evento.ID, err)
+				}
+			}
+			for cliente, esperado := range c.pedidos {
+				pagina, err := amb.repo.FindByClienteID(context.Background(), cliente, domain.FiltroPedidos{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(pagina.Pedidos) != esperado {
+					t.Errorf("pedidos de %s = %d, esperado %d", cliente, len(pagina.Pedidos), esperado)
+				}
+			}
+		})
+	}
+}
+
+func TestEventoClienteInputValidar(t *testing.T) {
+	const id = "3f1e2d4c-5b6a-4978-8a9b-0c1d2e3f4a5b"
+	casos := []struct {
+		nome   string
+		evento application.EventoClienteInput
+		valido bool
+	}{
+		{"mesclagem", application.EventoClienteInput{ID: "e1", Tipo: application.EventoClienteMesclado,
+			Dados: []byte(`{"cliente_id":"` + id + `","mesclado_com":"` + id + `"}`)}, true},
+		{"outro tipo com dados livres", application.EventoClienteInput{ID: "e1", Tipo: "cliente.cadastrado", Dados: []byte(`[]`)}, true},
+		{"sem id", application.EventoClienteInput{Tipo: "cliente.cadastrado"}, false},
+		{"sem tipo", application.EventoClienteInput{ID: "e1"}, false},
+		{"mesclagem sem dados", application.EventoClienteInput{ID: "e1", Tipo: application.EventoClienteMesclado}, false},
+		{"mesclagem sem destino", application.EventoClienteInput{ID: "e1", Tipo: application.EventoClienteMesclado,
+			Dados: []byte(`{"cliente_id":"` + id + `"}`)}, false},
+		{"mesclagem com cliente que não é UUID", application.EventoClienteInput{ID: "e1", Tipo: application.EventoClienteMesclado,
+			Dados: []byte(`{"cliente_id":"x","mesclado_com":"` + id + `"}`)}, false},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			if err := c.evento.Validar(); (err == nil) != c.valido {
+				t.Errorf("Validar = %v, esperado válido: %v", err, c.valido)
+			}
+		})
+	}
+}
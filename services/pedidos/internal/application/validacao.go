@@ -0,0 +1,115 @@
+package application
+
+import (
+	"ecommerce/pkg/common/validacao"
+	"encoding/json"
+)
+
+// Limites dos corpos de requisição de pedidos.
+const (
+	maximoItens           = 100
+	quantidadeMaximaItem  = 1000
+	tamanhoMaximoNomeItem = 150
+	tamanhoMaximoMotivo   = 500
+	tamanhoMaximoRua      = 200
+	tamanhoMaximoCidade   = 100
+	tamanhoMaximoEstado   = 100
+	tamanhoMaximoURL      = 2048
+	tamanhoMaximoSegredo  = 256
+)
+
+// ValidarNovoPedido confere o formato dos dados de criação de um pedido,
+// antes das consultas a clientes, catálogo e estoque.
+func ValidarNovoPedido(clienteID string, itens []ItensInput, entrega EnderecoEntregaInput) error {
+	v := validacao.New()
+	if v.Obrigatorio("cliente_id", clienteID) {
+		v.UUID("cliente_id", clienteID)
+	}
+
+	v.ItensEntre("itens", len(itens), 1, maximoItens)
+	for i, item := range itens {
+		if v.Obrigatorio(validacao.Indice("itens", i, "produto_id"), item.ProdutoID) {
+			v.UUID(validacao.Indice("itens", i, "produto_id"), item.ProdutoID)
+		}
+		if v.Minimo(validacao.Indice("itens", i, "quantidade"), int64(item.Quantidade), 1) {
+			v.Maximo(validacao.Indice("itens", i, "quantidade"), int64(item.Quantidade), quantidadeMaximaItem)
+		}
+		// Nome e preço vêm do catálogo; quando enviados, só precisam ser coerentes.
+		v.TamanhoMaximo(validacao.Indice("itens", i, "nome"), item.Nome, tamanhoMaximoNomeItem)
+		if item.Preco.Moeda != "" {
+			v.Positivo(validacao.Indice("itens", i, "preco"), item.Preco.Valor)
+		}
+	}
+
+	entrega.validar(v)
+	return v.Erro()
+}
+
+// validar exige o endereço completo quando a entrega não referencia um
+// endereço do cliente.
+func (in EnderecoEntregaInput) validar(v *validacao.Validador) {
+	if !v.Minimo("entrega.endereco_id", in.EnderecoID, 0) || in.EnderecoID > 0 {
+		return
+	}
+	if v.Obrigatorio("entrega.rua", in.Rua) {
+		v.TamanhoMaximo("entrega.rua", in.Rua, tamanhoMaximoRua)
+	}
+	if v.Obrigatorio("entrega.cidade", in.Cidade) {
+		v.TamanhoMaximo("entrega.cidade", in.Cidade, tamanhoMaximoCidade)
+	}
+	if v.Obrigatorio("entrega.estado", in.Estado) {
+		v.TamanhoMaximo("entrega.estado", in.Estado, tamanhoMaximoEstado)
+	}
+	if v.Obrigatorio("entrega.cep", in.CEP) {
+		v.CEP("entrega.cep", in.CEP)
+	}
+}
+
+// Validar limita o tamanho do motivo da mudança de status.
+func (in AlteracaoStatusInput) Validar() error {
+	v := validacao.New()
+	v.TamanhoMaximo("motivo", in.Motivo, tamanhoMaximoMotivo)
+	return v.Erro()
+}
+
+// Validar recusa saldos negativos.
+func (in EstoqueInput) Validar() error {
+	v := validacao.New()
+	v.Minimo("disponivel", int64(in.Disponivel), 0)
+	return v.Erro()
+}
+
+// Validar confere a presença e o tamanho dos campos da assinatura; o
+// formato da URL e os tipos de evento são regras do domínio.
+func (in AssinaturaInput) Validar() error {
+	v := validacao.New()
+	if v.Obrigatorio("url", in.URL) {
+		v.TamanhoMaximo("url", in.URL, tamanhoMaximoURL)
+	}
+	v.ItensEntre("eventos", len(in.Eventos), 1, 10)
+	v.TamanhoMaximo("segredo", in.Segredo, tamanhoMaximoSegredo)
+	return v.Erro()
+}
+
+// Validar confere a identificação do evento e, em cliente.mesclado, os
+// clientes envolvidos.
+func (in EventoClienteInput) Validar() error {
+	v := validacao.New()
+	v.Obrigatorio("id", in.ID)
+	v.Obrigatorio("tipo", in.Tipo)
+
+	if in.Tipo == EventoClienteMesclado {
+		var dados dadosClienteMesclado
+		if err := json.Unmarshal(in.Dados, &dados); err != nil {
+			v.Falhar("dados", validacao.RegraTipo, "object")
+			return v.Erro()
+		}
+		if v.Obrigatorio("dados.cliente_id", dados.ClienteID) {
+			v.UUID("dados.cliente_id", dados.ClienteID)
+		}
+		if v.Obrigatorio("dados.mesclado_com", dados.MescladoCom) {
+			v.UUID("dados.mesclado_com", dados.MescladoCom)
+		}
+	}
+	return v.Erro()
+}
@@ -0,0 +1,221 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"ecommerce/pedidos/internal/domain"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// WebhookService é a implementação dos casos de uso de assinaturas de webhook.
+type WebhookService struct {
+	repo domain.WebhookRepository
+}
+
+// NewWebhookService é o construtor do serviço de webhooks.
+func NewWebhookService(repo domain.WebhookRepository) *WebhookService {
+	return &WebhookService{
+		repo: repo,
+	}
+}
+
+// AssinaturaInput é o DTO para criar ou alterar uma assinatura.
+// Sem segredo, um é gerado na criação e mantido na alteração; Ativa ausente
+// mantém a situação atual (assinaturas novas começam ativas).
+type AssinaturaInput struct {
+	URL     string   `json:"url"`
+	Eventos []string `json:"eventos"`
+	Segredo string   `json:"segredo,omitempty"`
+	Ativa   *bool    `json:"ativa,omitempty"`
+}
+
+// AssinaturaOutput é o DTO de resposta de uma assinatura. O segredo só é
+// devolvido quando é definido (na criação ou na troca).
+type AssinaturaOutput struct {
+	ID           string    `json:"id"`
+	URL          string    `json:"url"`
+	Eventos      []string  `json:"eventos"`
+	Segredo      string    `json:"segredo,omitempty"`
+	Ativa        bool      `json:"ativa"`
+	CriadoEm     time.Time `json:"criado_em"`
+	AtualizadoEm time.Time `json:"atualizado_em"`
+}
+
+// EntregaOutput é o DTO de resposta de uma entrega de webhook.
+type EntregaOutput struct {
+	ID                 string            `json:"id"`
+	EventoID           string            `json:"evento_id"`
+	Tipo               string            `json:"tipo"`
+	Status             string            `json:"status"`
+	Tentativas         int               `json:"tentativas"`
+	ProximaTentativaEm *time.Time        `json:"proxima_tentativa_em,omitempty"`
+	EntregueEm         *time.Time        `json:"entregue_em,omitempty"`
+	CriadoEm           time.Time         `json:"criado_em"`
+	Payload            json.RawMessage   `json:"payload,omitempty"`
+	Historico          []TentativaOutput `json:"historico,omitempty"`
+}
+
+// TentativaOutput é uma requisição feita ao destino; status_http ausente
+// indica que não houve resposta.
+type TentativaOutput struct {
+	StatusHTTP int       `json:"status_http,omitempty"`
+	Erro       string    `json:"erro,omitempty"`
+	DuracaoMs  int64     `json:"duracao_ms"`
+	OcorridoEm time.Time `json:"ocorrido_em"`
+}
+
+// CriarAssinatura é o caso de uso para cadastrar um destino de webhooks.
+func (s *WebhookService) CriarAssinatura(ctx context.Context, input AssinaturaInput) (*AssinaturaOutput, error) {
+	segredo := input.Segredo
+	if segredo == "" {
+		segredo = gerarSegredo()
+	}
+
+	assinatura, err := domain.NewAssinatura(input.URL, input.Eventos, segredo)
+	if err != nil {
+		return nil, err
+	}
+	if input.Ativa != nil {
+		assinatura.Ativa = *input.Ativa
+	}
+	if err := s.repo.SaveAssinatura(ctx, assinatura); err != nil {
+		return nil, err
+	}
+	return paraAssinaturaOutput(assinatura, true), nil
+}
+
+// ListarAssinaturas é o caso de uso para listar os destinos cadastrados.
+func (s *WebhookService) ListarAssinaturas(ctx context.Context) ([]*AssinaturaOutput, error) {
+	assinaturas, err := s.repo.ListAssinaturas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	saida := make([]*AssinaturaOutput, len(assinaturas))
+	for i, a := range assinaturas {
+		saida[i] = paraAssinaturaOutput(a, false)
+	}
+	return saida, nil
+}
+
+// BuscarAssinatura é o caso de uso para consultar uma assinatura.
+func (s *WebhookService) BuscarAssinatura(ctx context.Context, id string) (*AssinaturaOutput, error) {
+	assinatura, err := s.repo.FindAssinatura(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return paraAssinaturaOutput(assinatura, false), nil
+}
+
+// AtualizarAssinatura é o caso de uso para alterar destino, eventos e
+// situação de uma assinatura e, opcionalmente, trocar o segredo.
+func (s *WebhookService) AtualizarAssinatura(ctx context.Context, id string, input AssinaturaInput) (*AssinaturaOutput, error) {
+	assinatura, err := s.repo.FindAssinatura(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ativa := assinatura.Ativa
+	if input.Ativa != nil {
+		ativa = *input.Ativa
+	}
+	if err := assinatura.Alterar(input.URL, input.Eventos, ativa); err != nil {
+		return nil, err
+	}
+	if input.Segredo != "" {
+		if err := assinatura.TrocarSegredo(input.Segredo); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateAssinatura(ctx, assinatura); err != nil {
+		return nil, err
+	}
+	return paraAssinaturaOutput(assinatura, input.Segredo != ""), nil
+}
+
+// ExcluirAssinatura é o caso de uso para remover uma assinatura e suas entregas.
+func (s *WebhookService) ExcluirAssinatura(ctx context.Context, id string) error {
+	return s.repo.DeleteAssinatura(ctx, id)
+}
+
+// ListarEntregas é o caso de uso para consultar as entregas recentes de uma
+// assinatura. Limite zero usa o tamanho de página padrão.
+func (s *WebhookService) ListarEntregas(ctx context.Context, assinaturaID string, limite int) ([]*EntregaOutput, error) {
+	switch {
+	case limite == 0:
+		limite = limitePadrao
+	case limite < 0 || limite > limiteMaximo:
+		return nil, fmt.Errorf("%w: limit deve estar entre 1 e %d", domain.ErrFiltroInvalido, limiteMaximo)
+	}
+
+	entregas, err := s.repo.ListEntregas(ctx, assinaturaID, limite)
+	if err != nil {
+		return nil, err
+	}
+	saida := make([]*EntregaOutput, len(entregas))
+	for i, e := range entregas {
+		saida[i] = paraEntregaOutput(e)
+	}
+	return saida, nil
+}
+
+// BuscarEntrega é o caso de uso para consultar uma entrega com o payload
+// enviado e o histórico de tentativas.
+func (s *WebhookService) BuscarEntrega(ctx context.Context, assinaturaID, entregaID string) (*EntregaOutput, error) {
+	entrega, err := s.repo.FindEntrega(ctx, assinaturaID, entregaID)
+	if err != nil {
+		return nil, err
+	}
+
+	saida := paraEntregaOutput(entrega)
+	saida.Payload = entrega.Payload
+	saida.Historico = make([]TentativaOutput, len(entrega.Historico))
+	for i, t := range entrega.Historico {
+		saida.Historico[i] = TentativaOutput(t)
+	}
+	return saida, nil
+}
+
+// ReenviarEntrega é o caso de uso para enviar uma entrega de novo, já
+// entregue ou não, na próxima passagem do entregador.
+func (s *WebhookService) ReenviarEntrega(ctx context.Context, assinaturaID, entregaID string) error {
+	return s.repo.Reenviar(ctx, assinaturaID, entregaID)
+}
+
+// gerarSegredo cria um segredo aleatório de 32 bytes em hexadecimal.
+func gerarSegredo() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func paraAssinaturaOutput(a *domain.Assinatura, comSegredo bool) *AssinaturaOutput {
+	saida := &AssinaturaOutput{
+		ID:           a.ID,
+		URL:          a.URL,
+		Eventos:      a.Eventos,
+		Ativa:        a.Ativa,
+		CriadoEm:     a.CriadoEm,
+		AtualizadoEm: a.AtualizadoEm,
+	}
+	if comSegredo {
+		saida.Segredo = a.Segredo
+	}
+	return saida
+}
+
+func paraEntregaOutput(e *domain.Entrega) *EntregaOutput {
+	return &EntregaOutput{
+		ID:                 e.ID,
+		EventoID:           e.EventoID,
+		Tipo:               e.Tipo,
+		Status:             string(e.Status),
+		Tentativas:         e.Tentativas,
+		ProximaTentativaEm: e.ProximaTentativaEm,
+		EntregueEm:         e.EntregueEm,
+		CriadoEm:           e.CriadoEm,
+	}
+}
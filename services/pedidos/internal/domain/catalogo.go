@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"ecommerce/pkg/money"
+)
+
+// Produto é a visão que o pedido tem de um produto do catálogo:
+// apenas o necessário para precificar um item.
+type Produto struct {
+	ID    string
+	Nome  string
+	Preco money.Money
+	Ativo bool
+}
+
+// CatalogoClient é a porta para o serviço de catálogo, a fonte da verdade de nomes e preços.
+type CatalogoClient interface {
+	// BuscarProduto retorna ErrProdutoNaoEncontrado quando o produto não existe no catálogo
+	// e ErrCatalogoIndisponivel quando não é possível consultar o serviço.
+	BuscarProduto(ctx context.Context, produtoID string) (*Produto, error)
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "context"
+
+// ClienteGateway é a porta para o serviço de clientes, usada para garantir
+// que os pedidos pertençam a clientes existentes.
+type ClienteGateway interface {
+	// ClienteExiste retorna ErrClientesIndisponivel quando não é possível consultar o serviço.
+	ClienteExiste(ctx context.Context, clienteID string) (bool, error)
+	// BuscarEndereco retorna ErrEnderecoNaoEncontrado quando o endereço não pertence ao cliente.
+	BuscarEndereco(ctx context.Context, clienteID string, enderecoID int64) (*EnderecoEntrega, error)
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "strings"
+
+// EnderecoEntrega é a cópia do endereço de entrega tirada no momento do pedido.
+// É um objeto de valor: alterações posteriores no cadastro do cliente não o afetam.
+type EnderecoEntrega struct {
+	EnderecoOrigemID int64 // ID do endereço no serviço de clientes; zero quando informado direto no pedido.
+	Rua              string
+	Cidade           string
+	Estado           string
+	CEP              string
+}
+
+// validar confere se o endereço tem os dados mínimos para a entrega.
+func (e EnderecoEntrega) validar() error {
+	if strings.TrimSpace(e.Rua) == "" || strings.TrimSpace(e.Cidade) == "" ||
+		strings.TrimSpace(e.Estado) == "" || strings.TrimSpace(e.CEP) == "" {
+		return ErrEnderecoEntregaInvalido
+	}
+	return nil
+}
@@ -4,7 +4,22 @@ import "errors"
 
 // Erros que podem ser retornados pela camada de domínio.
 var (
-	ErrPedidoNaoEncontrado = errors.New("pedido não encontrado")
-	ErrStatusInvalido      = errors.New("status do pedido inválido")
-	ErrItemInvalido        = errors.New("item do pedido inválido")
+	ErrPedidoNaoEncontrado     = errors.New("pedido não encontrado")
+	ErrStatusInvalido          = errors.New("status do pedido inválido")
+	ErrItemInvalido            = errors.New("item do pedido inválido")
+	ErrProdutoNaoEncontrado    = errors.New("produto não encontrado no catálogo")
+	ErrCatalogoIndisponivel    = errors.New("serviço de catálogo indisponível")
+	ErrProdutoIndisponivel     = errors.New("produto indisponível para venda")
+	ErrEstoqueInsuficiente     = errors.New("estoque insuficiente para o pedido")
+	ErrReservaExpirada         = errors.New("reserva de estoque expirada ou liberada")
+	ErrQuantidadeInvalida      = errors.New("quantidade de estoque inválida")
+	ErrClienteNaoEncontrado    = errors.New("cliente do pedido não encontrado")
+	ErrClientesIndisponivel    = errors.New("serviço de clientes indisponível")
+	ErrEnderecoEntregaInvalido = errors.New("endereço de entrega ausente ou inválido")
+	ErrEnderecoNaoEncontrado   = errors.New("endereço não encontrado para o cliente")
+	ErrFiltroInvalido          = errors.New("filtro de pedidos inválido")
+	ErrConflitoDeVersao        = errors.New("pedido alterado por outra requisição")
+	ErrAssinaturaNaoEncontrada = errors.New("assinatura de webhook não encontrada")
+	ErrAssinaturaInvalida      = errors.New("assinatura de webhook inválida")
+	ErrEntregaNaoEncontrada    = errors.New("entrega de webhook não encontrada")
 )
@@ -0,0 +1,28 @@
+package domain
+
+import "context"
+
+// ItemReserva é a quantidade de um produto a reservar para um pedido.
+type ItemReserva struct {
+	ProdutoID  string
+	Quantidade int
+}
+
+// Estoque é a porta para o controle de estoque. A implementação pode rodar
+// no mesmo processo (Postgres) ou ser um cliente de um serviço separado.
+type Estoque interface {
+	// Reservar separa as quantidades de forma atômica: ou reserva todos os itens
+	// ou nenhum (ErrEstoqueInsuficiente). Retorna o ID da reserva.
+	Reservar(ctx context.Context, itens []ItemReserva) (string, error)
+	// Confirmar efetiva a reserva (pagamento). Reservas expiradas ou liberadas
+	// retornam ErrReservaExpirada.
+	Confirmar(ctx context.Context, reservaID string) error
+	// Liberar devolve as quantidades ao estoque. É idempotente.
+	Liberar(ctx context.Context, reservaID string) error
+}
+
+// EstoqueRepository define as operações administrativas sobre o saldo de estoque.
+type EstoqueRepository interface {
+	DefinirDisponivel(ctx context.Context, produtoID string, quantidade int) error
+	BuscarDisponivel(ctx context.Context, produtoID string) (int, error)
+}
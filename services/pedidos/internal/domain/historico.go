@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// MudancaStatus é o evento de domínio registrado pelo Pedido a cada mudança de status.
+// É a base do histórico (trilha de auditoria) do pedido.
+type MudancaStatus struct {
+	PedidoID       string
+	StatusAnterior Status // Vazio na criação do pedido.
+	StatusNovo     Status
+	Autor          string
+	Motivo         string
+	OcorridoEm     time.Time
+}
+
+// Tipos dos eventos de pedido publicados para outros sistemas. As demais
+// mudanças de status viram "pedido." + o novo status.
+const (
+	EventoPedidoCriado    = "pedido.criado"
+	EventoPedidoPago      = "pedido.pago"
+	EventoPedidoEnviado   = "pedido.enviado"
+	EventoPedidoCancelado = "pedido.cancelado"
+)
+
+// TipoEvento é o tipo do evento publicado para esta mudança de status.
+func (m MudancaStatus) TipoEvento() string {
+	if m.StatusAnterior == "" {
+		return EventoPedidoCriado
+	}
+	return "pedido." + string(m.StatusNovo)
+}
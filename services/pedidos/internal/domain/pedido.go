@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"ecommerce/pkg/money"
+	"fmt"
 	"time"
 )
 
@@ -15,12 +17,37 @@ const (
 	StatusCancelado           Status = "cancelado"
 )
 
+// transicoesPermitidas define a máquina de estados do pedido:
+// para cada status, os status para os quais ele pode ir.
+var transicoesPermitidas = map[Status][]Status{
+	StatusAguardandoPagamento: {StatusPago, StatusCancelado},
+	StatusPago:                {StatusEnviado, StatusCancelado},
+	StatusEnviado:             {},
+	StatusCancelado:           {},
+}
+
+// Valido informa se s é um dos status conhecidos.
+func (s Status) Valido() bool {
+	_, ok := transicoesPermitidas[s]
+	return ok
+}
+
+// PodeTransicionarPara informa se a mudança do status atual para o novo é permitida.
+func (s Status) PodeTransicionarPara(novo Status) bool {
+	for _, permitido := range transicoesPermitidas[s] {
+		if permitido == novo {
+			return true
+		}
+	}
+	return false
+}
+
 // Item representa um item dentro do pedido.
 type Item struct {
 	ID         string
 	ProdutoID  string
 	Nome       string
-	Preco      float64
+	Preco      money.Money
 	Quantidade int
 }
 
@@ -30,30 +57,122 @@ type Pedido struct {
 	ClienteID    string
 	Itens        []*Item
 	Status       Status
-	Total        float64
+	Total        money.Money
+	ReservaID    string // Reserva de estoque feita na criação do pedido.
+	Entrega      EnderecoEntrega
 	CriadoEm     time.Time
 	AtualizadoEm time.Time
+	// Versao começa em 1 e é incrementada pelo repositório a cada alteração
+	// gravada; é a base do controle de concorrência otimista.
+	Versao int
+
+	// eventos guarda as mudanças de status ainda não persistidas.
+	eventos []MudancaStatus
 }
 
 // NewPedido é o construtor do nosso agregado.
 // Ele garante que o pedido seja criado de forma consistente.
-func NewPedido(clienteID string, itens []*Item) (*Pedido, error) {
+func NewPedido(clienteID string, itens []*Item, entrega EnderecoEntrega) (*Pedido, error) {
 	if len(itens) == 0 {
 		return nil, ErrItemInvalido
 	}
+	for _, item := range itens {
+		if item.Quantidade <= 0 {
+			return nil, fmt.Errorf("%w: quantidade %d do produto %s deve ser maior que zero", ErrItemInvalido, item.Quantidade, item.ProdutoID)
+		}
+	}
+	if err := entrega.validar(); err != nil {
+		return nil, err
+	}
 
-	total := 0.0
+	// Todos os itens precisam estar na mesma moeda; a do primeiro item define a do pedido.
+	total := money.Zero(itens[0].Preco.Moeda)
 	for _, item := range itens {
-		total += item.Preco * float64(item.Quantidade)
+		var err error
+		total, err = total.Add(item.Preco.Mul(item.Quantidade))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrItemInvalido, err)
+		}
 	}
 
-	return &Pedido{
+	agora := time.Now()
+	pedido := &Pedido{
 		ID:           "", // O ID será gerado na camada de infraestrutura
 		ClienteID:    clienteID,
 		Itens:        itens,
 		Status:       StatusAguardandoPagamento,
 		Total:        total,
-		CriadoEm:     time.Now(),
-		AtualizadoEm: time.Now(),
-	}, nil
+		Entrega:      entrega,
+		CriadoEm:     agora,
+		AtualizadoEm: agora,
+	}
+
+	// A criação é o primeiro registro do histórico; quem cria o pedido é o próprio cliente.
+	pedido.registrar(MudancaStatus{
+		StatusNovo: StatusAguardandoPagamento,
+		Autor:      clienteID,
+		Motivo:     "pedido criado",
+		OcorridoEm: agora,
+	})
+
+	return pedido, nil
+}
+
+// Pagar marca o pedido como pago.
+func (p *Pedido) Pagar(autor, motivo string) error {
+	return p.transicionarPara(StatusPago, autor, motivo)
+}
+
+// Enviar marca o pedido como enviado. Apenas pedidos pagos podem ser enviados.
+func (p *Pedido) Enviar(autor, motivo string) error {
+	return p.transicionarPara(StatusEnviado, autor, motivo)
+}
+
+// Cancelar cancela o pedido. Pedidos já enviados não podem ser cancelados.
+func (p *Pedido) Cancelar(autor, motivo string) error {
+	return p.transicionarPara(StatusCancelado, autor, motivo)
+}
+
+// VerificarVersao confere se o pedido ainda está na versão em que o chamador o
+// leu. Zero dispensa a verificação.
+func (p *Pedido) VerificarVersao(esperada int) error {
+	if esperada != 0 && esperada != p.Versao {
+		return ErrConflitoDeVersao
+	}
+	return nil
+}
+
+// Eventos retorna as mudanças de status registradas e ainda não persistidas.
+func (p *Pedido) Eventos() []MudancaStatus {
+	return p.eventos
+}
+
+// LimparEventos descarta os eventos pendentes; deve ser chamado após a persistência.
+func (p *Pedido) LimparEventos() {
+	p.eventos = nil
+}
+
+// transicionarPara aplica a mudança de status, respeitando a máquina de estados,
+// e registra o evento correspondente no histórico.
+func (p *Pedido) transicionarPara(novo Status, autor, motivo string) error {
+	if !p.Status.PodeTransicionarPara(novo) {
+		return ErrStatusInvalido
+	}
+
+	agora := time.Now()
+	p.registrar(MudancaStatus{
+		PedidoID:       p.ID,
+		StatusAnterior: p.Status,
+		StatusNovo:     novo,
+		Autor:          autor,
+		Motivo:         motivo,
+		OcorridoEm:     agora,
+	})
+	p.Status = novo
+	p.AtualizadoEm = agora
+	return nil
+}
+
+func (p *Pedido) registrar(evento MudancaStatus) {
+	p.eventos = append(p.eventos, evento)
 }
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"ecommerce/pkg/money"
+	"errors"
+	"testing"
+)
+
+var entregaTeste = EnderecoEntrega{Rua: "Av. Paulista, 1000", Cidade: "São Paulo", Estado: "SP", CEP: "01310-100"}
+
+func novoPedidoTeste(t *testing.T) *Pedido {
+	t.Helper()
+	pedido, err := NewPedido("cliente-1", []*Item{{ProdutoID: "p1", Preco: money.New(1990, "BRL"), Quantidade: 2}}, entregaTeste)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pedido
+}
+
+func TestTransicoesDeStatus(t *testing.T) {
+	transicoes := map[Status]func(*Pedido) error{
+		StatusPago:      func(p *Pedido) error { return p.Pagar("admin", "") },
+		StatusEnviado:   func(p *Pedido) error { return p.Enviar("admin", "") },
+		StatusCancelado: func(p *Pedido) error { return p.Cancelar("admin", "") },
+	}
+	casos := []struct {
+		de, para  Status
+		permitida bool
+	}{
+		{StatusAguardandoPagamento, StatusPago, true},
+		{StatusAguardandoPagamento, StatusCancelado, true},
+		{StatusAguardandoPagamento, StatusEnviado, false},
+		{StatusPago, StatusEnviado, true},
+		{StatusPago, StatusCancelado, true},
+		{StatusPago, StatusPago, false},
+		{StatusEnviado, StatusPago, false},
+		{StatusEnviado, StatusEnviado, false},
+		{StatusEnviado, StatusCancelado, false},
+		{StatusCancelado, StatusPago, false},
+		{StatusCancelado, StatusEnviado, false},
+		{StatusCancelado, StatusCancelado, false},
+	}
+	for _, c := range casos {
+		t.Run(string(c.de)+"→"+string(c.para), func(t *testing.T) {
+			if got := c.de.PodeTransicionarPara(c.para); got != c.permitida {
+				t.Errorf("PodeTransicionarPara = %v, esperado %v", got, c.permitida)
+			}
+
+			pedido := novoPedidoTeste(t)
+			pedido.Status = c.de
+			pedido.LimparEventos()
+			err := transicoes[c.para](pedido)
+
+			if !c.permitida {
+				if !errors.Is(err, ErrStatusInvalido) {
+					t.Fatalf("erro = %v, esperado ErrStatusInvalido", err)
+				}
+				if pedido.Status != c.de || len(pedido.Eventos()) != 0 {
+					t.Errorf("transição recusada mudou o pedido: status %s, %d eventos", pedido.Status, len(pedido.Eventos()))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			eventos := pedido.Eventos()
+			if pedido.Status != c.para || len(eventos) != 1 || eventos[0].StatusAnterior != c.de || eventos[0].StatusNovo != c.para {
+				t.Errorf("status = %s com eventos %+v, esperado %s→%s", pedido.Status, eventos, c.de, c.para)
+			}
+		})
+	}
+
+	// Todo status conhecido está na tabela de transições, e nenhum outro.
+	for _, s := range []Status{StatusAguardandoPagamento, StatusPago, StatusEnviado, StatusCancelado} {
+		if !s.Valido() {
+			t.Errorf("%s deveria ser válido", s)
+		}
+	}
+	if Status("devolvido").Valido() || Status("devolvido").PodeTransicionarPara(StatusPago) {
+		t.Error("status desconhecido não deveria ser válido nem transicionar")
+	}
+}
+
+func TestNewPedido(t *testing.T) {
+	item := func(quantidade int, moeda string) *Item {
+		return &Item{ProdutoID: "p1", Preco: money.New(1990, moeda), Quantidade: quantidade}
+	}
+	casos := []struct {
+		nome    string
+		itens   []*Item
+		entrega EnderecoEntrega
+		total   money.Money
+		erro    error
+	}{
+		{"soma os itens", []*Item{item(2, "BRL"), item(1, "BRL")}, entregaTeste, money.New(5970, "BRL"), nil},
+		{"sem itens", nil, entregaTeste, money.Money{}, ErrItemInvalido},
+		{"quantidade zero", []*Item{item(1, "BRL"), item(0, "BRL")}, entregaTeste, money.Money{}, ErrItemInvalido},
+		{"quantidade negativa", []*Item{item(-1, "BRL")}, entregaTeste, money.Money{}, ErrItemInvalido},
+		{"moedas diferentes", []*Item{item(1, "BRL"), item(1, "USD")}, entregaTeste, money.Money{}, ErrItemInvalido},
+		{"sem endereço de entrega", []*Item{item(1, "BRL")}, EnderecoEntrega{}, money.Money{}, ErrEnderecoEntregaInvalido},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			pedido, err := NewPedido("cliente-1", c.itens, c.entrega)
+			if c.erro != nil {
+				if !errors.Is(err, c.erro) {
+					t.Fatalf("erro = %v, esperado %v", err, c.erro)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pedido.Total != c.total || pedido.Status != StatusAguardandoPagamento {
+				t.Errorf("pedido com total %v e status %s, esperado %v aguardando pagamento", pedido.Total, pedido.Status, c.total)
+			}
+		})
+	}
+}
@@ -1,11 +1,54 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // PedidoRepository define os métodos para persistir e recuperar pedidos.
 type PedidoRepository interface {
 	Save(ctx context.Context, pedido *Pedido) error
 	FindByID(ctx context.Context, id string) (*Pedido, error)
-	ListAll(ctx context.Context) ([]*Pedido, error)
+	List(ctx context.Context, filtro FiltroPedidos) (*PaginaPedidos, error)
+	FindByClienteID(ctx context.Context, clienteID string, filtro FiltroPedidos) (*PaginaPedidos, error)
+	Update(ctx context.Context, pedido *Pedido) error
+	FindHistorico(ctx context.Context, pedidoID string) ([]*MudancaStatus, error)
+	// TransferirCliente registra que um cliente foi absorvido por outro e
+	// passa os pedidos dele para o cliente que permaneceu no fim da cadeia de
+	// mesclagens registradas, em qualquer ordem de chegada, retornando quantos
+	// foram transferidos. Repetir a transferência não tem efeito.
+	TransferirCliente(ctx context.Context, deClienteID, paraClienteID string) (int64, error)
 	// Outros métodos de consulta, como FindAll, etc.
 }
+
+// Transacao executa fn numa única transação: o que repositórios e estoque
+// fazem com o ctx recebido por fn é confirmado ou desfeito junto.
+type Transacao interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// CursorPedido é a posição de um pedido na listagem, ordenada do mais novo
+// para o mais antigo por (CriadoEm, ID).
+type CursorPedido struct {
+	CriadoEm time.Time
+	ID       string
+}
+
+// FiltroPedidos restringe e pagina a listagem de pedidos. Campos vazios não filtram.
+type FiltroPedidos struct {
+	ClienteID string
+	Status    Status
+	CriadoDe  time.Time // Inclusivo.
+	CriadoAte time.Time // Exclusivo.
+	TotalMin  *int64    // Em unidades menores da moeda, inclusivo.
+	TotalMax  *int64    // Em unidades menores da moeda, inclusivo.
+
+	Apos   *CursorPedido // Começa depois deste pedido; nil para a primeira página.
+	Limite int
+}
+
+// PaginaPedidos é uma página da listagem. Proximo é nil na última página.
+type PaginaPedidos struct {
+	Pedidos []*Pedido
+	Proximo *CursorPedido
+}
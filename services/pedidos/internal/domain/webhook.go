@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// EventoTodosPedidos assina todos os eventos de pedido.
+const EventoTodosPedidos = "pedido.*"
+
+// tiposEventoAssinaveis são os valores aceitos na lista de eventos de uma assinatura.
+var tiposEventoAssinaveis = []string{
+	EventoTodosPedidos, EventoPedidoCriado, EventoPedidoPago, EventoPedidoEnviado, EventoPedidoCancelado,
+}
+
+// tamanhoMinimoSegredo garante segredos que não se adivinham por força bruta.
+const tamanhoMinimoSegredo = 16
+
+// Assinatura é o cadastro de um parceiro que recebe eventos de pedido por
+// webhook: as requisições vão para URL, assinadas com HMAC-SHA256 do Segredo.
+type Assinatura struct {
+	ID           string
+	URL          string
+	Eventos      []string
+	Segredo      string
+	Ativa        bool
+	CriadoEm     time.Time
+	AtualizadoEm time.Time
+}
+
+// NewAssinatura cria uma assinatura ativa, validando URL, eventos e segredo.
+func NewAssinatura(endereco string, eventos []string, segredo string) (*Assinatura, error) {
+	agora := time.Now()
+	a := &Assinatura{Ativa: true, CriadoEm: agora}
+	if err := a.Alterar(endereco, eventos, true); err != nil {
+		return nil, err
+	}
+	if err := a.TrocarSegredo(segredo); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Alterar substitui destino, eventos e situação da assinatura.
+func (a *Assinatura) Alterar(endereco string, eventos []string, ativa bool) error {
+	endereco = strings.TrimSpace(endereco)
+	u, err := url.Parse(endereco)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url deve ser absoluta, com http ou https", ErrAssinaturaInvalida)
+	}
+	if !HostPublico(u.Hostname()) {
+		return fmt.Errorf("%w: url deve apontar para um endereço público da internet", ErrAssinaturaInvalida)
+	}
+	if len(eventos) == 0 {
+		return fmt.Errorf("%w: informe ao menos um evento", ErrAssinaturaInvalida)
+	}
+	for _, evento := range eventos {
+		if !eventoAssinavel(evento) {
+			return fmt.Errorf("%w: evento %q desconhecido, use um de %s",
+				ErrAssinaturaInvalida, evento, strings.Join(tiposEventoAssinaveis, ", "))
+		}
+	}
+
+	a.URL = endereco
+	a.Eventos = eventos
+	a.Ativa = ativa
+	a.AtualizadoEm = time.Now()
+	return nil
+}
+
+// TrocarSegredo substitui o segredo usado nas assinaturas HMAC.
+func (a *Assinatura) TrocarSegredo(segredo string) error {
+	if len(segredo) < tamanhoMinimoSegredo {
+		return fmt.Errorf("%w: segredo deve ter ao menos %d caracteres", ErrAssinaturaInvalida, tamanhoMinimoSegredo)
+	}
+	a.Segredo = segredo
+	a.AtualizadoEm = time.Now()
+	return nil
+}
+
+// Recebe informa se a assinatura está ativa e inclui o tipo de evento.
+func (a *Assinatura) Recebe(tipo string) bool {
+	if !a.Ativa {
+		return false
+	}
+	for _, evento := range a.Eventos {
+		if evento == tipo || (evento == EventoTodosPedidos && strings.HasPrefix(tipo, "pedido.")) {
+			return true
+		}
+	}
+	return false
+}
+
+// faixasReservadas são faixas IPv4 fora da internet pública que netip não
+// classifica: "esta rede" e o CGNAT (RFC 6598).
+var faixasReservadas = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// HostPublico informa se o host de uma URL de webhook pode ser um destino na
+// internet pública. Recusa IPs internos (ver IPPublico), localhost, nomes
+// sem domínio (resolvidos na rede interna) e os sufixos .internal e .local,
+// como o metadata.google.internal do Cloud Run. Nomes são conferidos de novo,
+// já resolvidos, a cada entrega.
+func HostPublico(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return IPPublico(ip)
+	}
+	if host == "localhost" || !strings.Contains(host, ".") {
+		return false
+	}
+	for _, sufixo := range []string{".localhost", ".internal", ".local"} {
+		if strings.HasSuffix(host, sufixo) {
+			return false
+		}
+	}
+	return true
+}
+
+// IPPublico informa se o IP pode receber entregas de webhook: recusa loopback,
+// redes privadas (RFC 1918 e fc00::/7), link-local (169.254.0.0/16, onde fica
+// o servidor de metadados), multicast e endereços não especificados.
+func IPPublico(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, faixa := range faixasReservadas {
+		if faixa.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// IPPublicoDoEndereco é IPPublico para um endereço "ip:porta", como os
+// recebidos pelo net.Dialer depois da resolução do nome.
+func IPPublicoDoEndereco(endereco string) bool {
+	host, _, err := net.SplitHostPort(endereco)
+	if err != nil {
+		return false
+	}
+	ip, err := netip.ParseAddr(host)
+	return err == nil && IPPublico(ip)
+}
+
+func eventoAssinavel(evento string) bool {
+	for _, tipo := range tiposEventoAssinaveis {
+		if evento == tipo {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusEntrega é a situação de uma entrega de webhook.
+type StatusEntrega string
+
+// Os possíveis estados de uma entrega.
+const (
+	EntregaPendente StatusEntrega = "pendente" // Aguardando a primeira tentativa ou uma nova.
+	EntregaEntregue StatusEntrega = "entregue" // O destino respondeu 2xx.
+	EntregaFalhou   StatusEntrega = "falhou"   // Tentativas esgotadas; só volta com reenvio manual.
+)
+
+// Entrega é o envio de um evento para uma assinatura, com o registro das
+// tentativas feitas.
+type Entrega struct {
+	ID                 string
+	AssinaturaID       string
+	EventoID           string
+	Tipo               string
+	Payload            json.RawMessage
+	Status             StatusEntrega
+	Tentativas         int // Falhas desde a criação ou o último reenvio manual.
+	ProximaTentativaEm *time.Time
+	EntregueEm         *time.Time
+	CriadoEm           time.Time
+	Historico          []TentativaEntrega // Preenchido apenas na consulta de uma entrega.
+}
+
+// TentativaEntrega é uma requisição feita ao destino de uma assinatura.
+// StatusHTTP é zero quando não houve resposta (ex.: timeout).
+type TentativaEntrega struct {
+	StatusHTTP int
+	Erro       string
+	DuracaoMs  int64
+	OcorridoEm time.Time
+}
+
+// WebhookRepository define a persistência das assinaturas e entregas de webhook.
+type WebhookRepository interface {
+	SaveAssinatura(ctx context.Context, assinatura *Assinatura) error
+	FindAssinatura(ctx context.Context, id string) (*Assinatura, error)
+	ListAssinaturas(ctx context.Context) ([]*Assinatura, error)
+	UpdateAssinatura(ctx context.Context, assinatura *Assinatura) error
+	DeleteAssinatura(ctx context.Context, id string) error
+
+	// ListEntregas retorna as entregas mais recentes de uma assinatura, da mais nova para a mais antiga.
+	ListEntregas(ctx context.Context, assinaturaID string, limite int) ([]*Entrega, error)
+	// FindEntrega retorna a entrega com o histórico de tentativas.
+	FindEntrega(ctx context.Context, assinaturaID, entregaID string) (*Entrega, error)
+	// Reenviar devolve a entrega à fila para uma nova tentativa imediata,
+	// com as tentativas zeradas, mesmo que já tenha sido entregue.
+	Reenviar(ctx context.Context, assinaturaID, entregaID string) error
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAssinaturaRecusaDestinoInterno(t *testing.T) {
+	casos := []struct {
+		url    string
+		valida bool
+	}{
+		{"https://parceiro.example/hooks", true},
+		{"http://203.0.113.10:8080/hooks", true},
+		{"https://[2001:db8::1]/hooks", true},
+		{"http://localhost:8080/hooks", false},
+		{"http://api.localhost/hooks", false},
+		{"http://127.0.0.1/hooks", false},
+		{"http://10.0.0.5/hooks", false},
+		{"http://172.16.3.4/hooks", false},
+		{"http://192.168.0.1/hooks", false},
+		{"http://169.254.169.254/computeMetadata/v1/", false},
+		{"http://metadata.google.internal/computeMetadata/v1/", false},
+		{"http://metadata/computeMetadata/v1/", false},
+		{"http://100.64.0.1/hooks", false},
+		{"http://0.0.0.0/hooks", false},
+		{"http://[::1]/hooks", false},
+		{"http://[fd00::1]/hooks", false},
+		{"http://[fe80::1]/hooks", false},
+		{"http://[::ffff:127.0.0.1]/hooks", false},
+		{"http://2130706433/hooks", false},
+		{"ftp://parceiro.example/hooks", false},
+	}
+	for _, c := range casos {
+		t.Run(c.url, func(t *testing.T) {
+			_, err := NewAssinatura(c.url, []string{EventoTodosPedidos}, "segredo-de-teste-123")
+			if c.valida && err != nil {
+				t.Errorf("esperado válida, erro = %v", err)
+			}
+			if !c.valida && !errors.Is(err, ErrAssinaturaInvalida) {
+				t.Errorf("esperado ErrAssinaturaInvalida, erro = %v", err)
+			}
+		})
+	}
+}
+
+func TestIPPublicoDoEndereco(t *testing.T) {
+	casos := map[string]bool{
+		"203.0.113.10:443":    true,
+		"[2001:db8::1]:443":   true,
+		"127.0.0.1:80":        false,
+		"169.254.169.254:80":  false,
+		"10.1.2.3:443":        false,
+		"[::1]:443":           false,
+		"sem-porta":           false,
+		"nome.example.com:80": false, // O Dialer entrega IPs; nomes não são aceitos.
+	}
+	for endereco, esperado := range casos {
+		if got := IPPublicoDoEndereco(endereco); got != esperado {
+			t.Errorf("IPPublicoDoEndereco(%q) = %v, esperado %v", endereco, got, esperado)
+		}
+	}
+}
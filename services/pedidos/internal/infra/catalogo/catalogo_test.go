@@ -0,0 +1,130 @@
+package catalogo
+
+import (
+	"context"
+	"ecommerce/pedidos/internal/domain"
+	"ecommerce/pkg/money"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCatalogoEmMemoria(t *testing.T) {
+	caneca := domain.Produto{ID: "p1", Nome: "Caneca", Preco: money.New(2990, "BRL"), Ativo: true}
+	catalogo := NewCatalogoEmMemoria(caneca)
+
+	casos := []struct {
+		nome    string
+		id      string
+		produto *domain.Produto
+		erro    error
+	}{
+		{"produto cadastrado", "p1", &caneca, nil},
+		{"produto desconhecido", "p2", nil, domain.ErrProdutoNaoEncontrado},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			produto, err := catalogo.BuscarProduto(context.Background(), c.id)
+			if !errors.Is(err, c.erro) {
+				t.Fatalf("erro = %v, esperado %v", err, c.erro)
+			}
+			if c.produto != nil && *produto != *c.produto {
+				t.Errorf("produto = %+v, esperado %+v", *produto, *c.produto)
+			}
+		})
+	}
+
+	// Adicionar substitui o produto: um reajuste vale para os próximos pedidos.
+	caneca.Preco = money.New(3490, "BRL")
+	catalogo.Adicionar(caneca)
+	if produto, _ := catalogo.BuscarProduto(context.Background(), "p1"); produto.Preco != caneca.Preco {
+		t.Errorf("preço = %s, esperado %s", produto.Preco, caneca.Preco)
+	}
+}
+
+func TestHTTPCatalogoClient(t *testing.T) {
+	casos := []struct {
+		nome    string
+		status  int
+		corpo   string
+		produto *domain.Produto
+		erro    error
+	}{
+		{
+			nome:    "produto encontrado",
+			status:  http.StatusOK,
+			corpo:   `{"ID":"p1","Nome":"Caneca","Preco":{"valor":2990,"moeda":"BRL"},"Ativo":true}`,
+			produto: &domain.Produto{ID: "p1", Nome: "Caneca", Preco: money.New(2990, "BRL"), Ativo: true},
+		},
+		{
+			nome:    "produto desativado",
+			status:  http.StatusOK,
+			corpo:   `{"ID":"p1","Nome":"Caneca","Preco":{"valor":2990,"moeda":"BRL"},"Ativo":false}`,
+			produto: &domain.Produto{ID: "p1", Nome: "Caneca", Preco: money.New(2990, "BRL"), Ativo: false},
+		},
+		{nome: "produto inexistente", status: http.StatusNotFound, erro: domain.ErrProdutoNaoEncontrado},
+		{nome: "falha no catálogo", status: http.StatusInternalServerError, erro: domain.ErrCatalogoIndisponivel},
+		{nome: "resposta inválida", status: http.StatusOK, corpo: `{`, erro: domain.ErrCatalogoIndisponivel},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/produtos/p1" {
+					t.Errorf("caminho = %s, esperado /produtos/p1", r.URL.Path)
+				}
+				w.WriteHeader(c.status)
+				io.WriteString(w, c.corpo)
+			}))
+			defer servidor.Close()
+
+			produto, err := NewHTTPCatalogoClient(servidor.URL+"/").BuscarProduto(context.Background(), "p1")
+			switch {
+			case c.produto != nil:
+				if err != nil {
+					t.Fatalf("erro = %v", err)
+				}
+				if *produto != *c.produto {
+					t.Errorf("produto = %+v, esperado %+v", *produto, *c.produto)
+				}
+			default:
+				if !errors.Is(err, c.erro) {
+					t.Errorf("erro = %v, esperado %v", err, c.erro)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPCatalogoClientAbreOCircuito(t *testing.T) {
+	chamadas := 0
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chamadas++
+		if r.URL.Path == "/produtos/inexistente" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer servidor.Close()
+	client := NewHTTPCatalogoClient(servidor.URL)
+
+	// Produto inexistente não conta como falha do serviço.
+	for i := 0; i < limiteFalhas; i++ {
+		if _, err := client.BuscarProduto(context.Background(), "inexistente"); !errors.Is(err, domain.ErrProdutoNaoEncontrado) {
+			t.Fatalf("erro = %v, esperado %v", err, domain.ErrProdutoNaoEncontrado)
+		}
+	}
+	for i := 0; i < limiteFalhas; i++ {
+		if _, err := client.BuscarProduto(context.Background(), "p1"); !errors.Is(err, domain.ErrCatalogoIndisponivel) {
+			t.Fatalf("erro = %v, esperado %v", err, domain.ErrCatalogoIndisponivel)
+		}
+	}
+
+	chamadas = 0
+	_, err := client.BuscarProduto(context.Background(), "p1")
+	if !errors.Is(err, domain.ErrCatalogoIndisponivel) || chamadas != 0 {
+		t.Errorf("com o circuito aberto: erro = %v, chamadas = %d; esperado %v sem chamar o catálogo", err, chamadas, domain.ErrCatalogoIndisponivel)
+	}
+}
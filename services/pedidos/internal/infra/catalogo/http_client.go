@@ -0,0 +1,95 @@
+package catalogo
+
+import (
+	"context"
+	"ecommerce/pedidos/internal/domain"
+	"ecommerce/pkg/circuitbreaker"
+	"ecommerce/pkg/money"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Parâmetros de resiliência da chamada ao serviço de catálogo.
+const (
+	timeoutPadrao       = 5 * time.Second
+	limiteFalhas        = 5
+	tempoCircuitoAberto = 30 * time.Second
+)
+
+type httpCatalogoClient struct {
+	baseURL string
+	client  *http.Client
+	breaker *circuitbreaker.CircuitBreaker
+}
+
+// NewHTTPCatalogoClient cria o cliente que consulta o serviço de catálogo via HTTP,
+// com timeout e circuit breaker para não travar a criação de pedidos quando ele estiver fora.
+// baseURL é a raiz do serviço, ex.: https://catalogo-service.run.app.
+func NewHTTPCatalogoClient(baseURL string) domain.CatalogoClient {
+	return &httpCatalogoClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeoutPadrao},
+		breaker: circuitbreaker.New(limiteFalhas, tempoCircuitoAberto),
+	}
+}
+
+// produtoResponse espelha o JSON retornado por GET /produtos/{id} no catálogo.
+type produtoResponse struct {
+	ID    string
+	Nome  string
+	Preco money.Money
+	Ativo bool
+}
+
+// BuscarProduto consulta GET /produtos/{id} no serviço de catálogo, protegido pelo circuit breaker.
+func (c *httpCatalogoClient) BuscarProduto(ctx context.Context, produtoID string) (*domain.Produto, error) {
+	var (
+		body       produtoResponse
+		encontrado bool
+	)
+	err := c.breaker.Executar(ctx, func() error {
+		endpoint := c.baseURL + "/produtos/" + url.PathEscape(produtoID)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		// 404 é uma resposta válida do serviço e não conta como falha para o circuito.
+		switch resp.StatusCode {
+		case http.StatusOK:
+			encontrado = true
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				return fmt.Errorf("resposta inválida do catálogo: %w", err)
+			}
+			return nil
+		case http.StatusNotFound:
+			return nil
+		default:
+			return fmt.Errorf("catálogo respondeu com status %d", resp.StatusCode)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrCatalogoIndisponivel, err)
+	}
+	if !encontrado {
+		return nil, domain.ErrProdutoNaoEncontrado
+	}
+
+	return &domain.Produto{
+		ID:    body.ID,
+		Nome:  body.Nome,
+		Preco: body.Preco,
+		Ativo: body.Ativo,
+	}, nil
+}
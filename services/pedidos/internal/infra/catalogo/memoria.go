@@ -0,0 +1,40 @@
+package catalogo
+
+import (
+	"context"
+	"ecommerce/pedidos/internal/domain"
+	"sync"
+)
+
+// CatalogoEmMemoria é um catálogo falso, para testes e desenvolvimento local.
+type CatalogoEmMemoria struct {
+	mu       sync.RWMutex
+	produtos map[string]domain.Produto
+}
+
+// NewCatalogoEmMemoria cria o catálogo falso já populado com os produtos informados.
+func NewCatalogoEmMemoria(produtos ...domain.Produto) *CatalogoEmMemoria {
+	c := &CatalogoEmMemoria{produtos: make(map[string]domain.Produto)}
+	for _, p := range produtos {
+		c.Adicionar(p)
+	}
+	return c
+}
+
+// Adicionar inclui ou substitui um produto no catálogo falso.
+func (c *CatalogoEmMemoria) Adicionar(produto domain.Produto) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.produtos[produto.ID] = produto
+}
+
+// BuscarProduto implementa domain.CatalogoClient.
+func (c *CatalogoEmMemoria) BuscarProduto(_ context.Context, produtoID string) (*domain.Produto, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	produto, ok := c.produtos[produtoID]
+	if !ok {
+		return nil, domain.ErrProdutoNaoEncontrado
+	}
+	return &produto, nil
+}
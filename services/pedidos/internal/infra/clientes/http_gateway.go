@@ -0,0 +1,114 @@
+package clientes
+
+import (
+	"context"
+	"ecommerce/pedidos/internal/domain"
+	"ecommerce/pkg/circuitbreaker"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parâmetros de resiliência da chamada ao serviço de clientes.
+const (
+	timeoutPadrao       = 3 * time.Second
+	limiteFalhas        = 5
+	tempoCircuitoAberto = 30 * time.Second
+)
+
+type httpClienteGateway struct {
+	baseURL string
+	client  *http.Client
+	breaker *circuitbreaker.CircuitBreaker
+}
+
+// NewHTTPClienteGateway cria o gateway que consulta o serviço de clientes,
+// com timeout e circuit breaker para não travar a criação de pedidos quando ele estiver fora.
+func NewHTTPClienteGateway(baseURL string) domain.ClienteGateway {
+	return &httpClienteGateway{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeoutPadrao},
+		breaker: circuitbreaker.New(limiteFalhas, tempoCircuitoAberto),
+	}
+}
+
+// enderecoResponse espelha o JSON de GET /clientes/{id}/enderecos/{enderecoId}.
+type enderecoResponse struct {
+	ID     int64
+	Tipo   string
+	Rua    string
+	Cidade string
+	Estado string
+	CEP    string
+}
+
+// ClienteExiste implementa domain.ClienteGateway.
+func (g *httpClienteGateway) ClienteExiste(ctx context.Context, clienteID string) (bool, error) {
+	return g.buscar(ctx, "/clientes/"+url.PathEscape(clienteID), nil)
+}
+
+// BuscarEndereco implementa domain.ClienteGateway. Apenas endereços de entrega são aceitos.
+func (g *httpClienteGateway) BuscarEndereco(ctx context.Context, clienteID string, enderecoID int64) (*domain.EnderecoEntrega, error) {
+	var body enderecoResponse
+	caminho := "/clientes/" + url.PathEscape(clienteID) + "/enderecos/" + strconv.FormatInt(enderecoID, 10)
+	encontrado, err := g.buscar(ctx, caminho, &body)
+	if err != nil {
+		return nil, err
+	}
+	if !encontrado {
+		return nil, domain.ErrEnderecoNaoEncontrado
+	}
+	if body.Tipo != "entrega" {
+		return nil, domain.ErrEnderecoEntregaInvalido
+	}
+
+	return &domain.EnderecoEntrega{
+		EnderecoOrigemID: body.ID,
+		Rua:              body.Rua,
+		Cidade:           body.Cidade,
+		Estado:           body.Estado,
+		CEP:              body.CEP,
+	}, nil
+}
+
+// buscar faz um GET protegido pelo circuit breaker. Retorna false quando o
+// recurso não existe (404) e decodifica a resposta em destino, se informado.
+func (g *httpClienteGateway) buscar(ctx context.Context, caminho string, destino any) (bool, error) {
+	var encontrado bool
+	err := g.breaker.Executar(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+caminho, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := g.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		// 404 é uma resposta válida do serviço e não conta como falha para o circuito.
+		switch resp.StatusCode {
+		case http.StatusOK:
+			encontrado = true
+			if destino == nil {
+				return nil
+			}
+			return json.NewDecoder(resp.Body).Decode(destino)
+		case http.StatusNotFound:
+			encontrado = false
+			return nil
+		default:
+			return fmt.Errorf("serviço de clientes respondeu com status %d", resp.StatusCode)
+		}
+	})
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", domain.ErrClientesIndisponivel, err)
+	}
+	return encontrado, nil
+}
@@ -0,0 +1,62 @@
+package clientes
+
+import (
+	"context"
+	"ecommerce/pedidos/internal/domain"
+	"sync"
+)
+
+// ClientesEmMemoria é um gateway de clientes falso, para testes e desenvolvimento local.
+type ClientesEmMemoria struct {
+	mu        sync.RWMutex
+	ids       map[string]bool
+	enderecos map[string]map[int64]domain.EnderecoEntrega
+}
+
+// NewClientesEmMemoria cria o gateway falso com os IDs de clientes existentes.
+func NewClientesEmMemoria(clienteIDs ...string) *ClientesEmMemoria {
+	g := &ClientesEmMemoria{
+		ids:       make(map[string]bool),
+		enderecos: make(map[string]map[int64]domain.EnderecoEntrega),
+	}
+	for _, id := range clienteIDs {
+		g.Adicionar(id)
+	}
+	return g
+}
+
+// Adicionar registra um cliente como existente.
+func (g *ClientesEmMemoria) Adicionar(clienteID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.ids[clienteID] = true
+}
+
+// ClienteExiste implementa domain.ClienteGateway.
+func (g *ClientesEmMemoria) ClienteExiste(_ context.Context, clienteID string) (bool, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.ids[clienteID], nil
+}
+
+// AdicionarEndereco registra um endereço de entrega para o cliente.
+func (g *ClientesEmMemoria) AdicionarEndereco(clienteID string, endereco domain.EnderecoEntrega) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.ids[clienteID] = true
+	if g.enderecos[clienteID] == nil {
+		g.enderecos[clienteID] = make(map[int64]domain.EnderecoEntrega)
+	}
+	g.enderecos[clienteID][endereco.EnderecoOrigemID] = endereco
+}
+
+// BuscarEndereco implementa domain.ClienteGateway.
+func (g *ClientesEmMemoria) BuscarEndereco(_ context.Context, clienteID string, enderecoID int64) (*domain.EnderecoEntrega, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	endereco, ok := g.enderecos[clienteID][enderecoID]
+	if !ok {
+		return nil, domain.ErrEnderecoNaoEncontrado
+	}
+	return &endereco, nil
+}
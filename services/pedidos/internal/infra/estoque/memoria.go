@@ -0,0 +1,101 @@
+package estoque
+
+import (
+	"context"
+	"ecommerce/pedidos/internal/domain"
+	"strconv"
+	"sync"
+)
+
+// EstoqueEmMemoria é um estoque falso, para testes e desenvolvimento local.
+// Reservas em memória não expiram.
+type EstoqueEmMemoria struct {
+	mu         sync.Mutex
+	disponivel map[string]int
+	reservas   map[string][]domain.ItemReserva
+	status     map[string]string
+	proximoID  int
+}
+
+// NewEstoqueEmMemoria cria o estoque falso com os saldos informados por produto.
+func NewEstoqueEmMemoria(saldos map[string]int) *EstoqueEmMemoria {
+	e := &EstoqueEmMemoria{
+		disponivel: make(map[string]int),
+		reservas:   make(map[string][]domain.ItemReserva),
+		status:     make(map[string]string),
+	}
+	for produtoID, quantidade := range saldos {
+		e.disponivel[produtoID] = quantidade
+	}
+	return e
+}
+
+// Reservar implementa domain.Estoque.
+func (e *EstoqueEmMemoria) Reservar(_ context.Context, itens []domain.ItemReserva) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	agrupados := agruparPorProduto(itens)
+	for _, item := range agrupados {
+		if e.disponivel[item.ProdutoID] < item.Quantidade {
+			return "", domain.ErrEstoqueInsuficiente
+		}
+	}
+	for _, item := range agrupados {
+		e.disponivel[item.ProdutoID] -= item.Quantidade
+	}
+
+	e.proximoID++
+	reservaID := "reserva-" + strconv.Itoa(e.proximoID)
+	e.reservas[reservaID] = agrupados
+	e.status[reservaID] = reservaPendente
+	return reservaID, nil
+}
+
+// Confirmar implementa domain.Estoque.
+func (e *EstoqueEmMemoria) Confirmar(_ context.Context, reservaID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch e.status[reservaID] {
+	case reservaPendente, reservaConfirmada:
+		e.status[reservaID] = reservaConfirmada
+		return nil
+	default:
+		return domain.ErrReservaExpirada
+	}
+}
+
+// Liberar implementa domain.Estoque.
+func (e *EstoqueEmMemoria) Liberar(_ context.Context, reservaID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	status := e.status[reservaID]
+	if status != reservaPendente && status != reservaConfirmada {
+		return nil
+	}
+	for _, item := range e.reservas[reservaID] {
+		e.disponivel[item.ProdutoID] += item.Quantidade
+	}
+	e.status[reservaID] = reservaLiberada
+	return nil
+}
+
+// DefinirDisponivel implementa domain.EstoqueRepository.
+func (e *EstoqueEmMemoria) DefinirDisponivel(_ context.Context, produtoID string, quantidade int) error {
+	if quantidade < 0 {
+		return domain.ErrQuantidadeInvalida
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.disponivel[produtoID] = quantidade
+	return nil
+}
+
+// BuscarDisponivel implementa domain.EstoqueRepository.
+func (e *EstoqueEmMemoria) BuscarDisponivel(_ context.Context, produtoID string) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.disponivel[produtoID], nil
+}
@@ -0,0 +1,226 @@
+package estoque
+
+import (
+	"context"
+	"ecommerce/pedidos/internal/domain"
+	"ecommerce/pkg/db"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Os possíveis estados de uma reserva.
+const (
+	reservaPendente   = "pendente"
+	reservaConfirmada = "confirmada"
+	reservaLiberada   = "liberada"
+)
+
+// PostgresEstoque é a implementação em processo do estoque, sobre as tabelas
+// estoque, estoque_reservas e estoque_reserva_itens.
+type PostgresEstoque struct {
+	db       *db.Pool
+	validade time.Duration
+}
+
+// NewPostgresEstoque cria o estoque. validade é quanto tempo uma reserva
+// pendente (pedido não pago) segura as quantidades antes de expirar.
+func NewPostgresEstoque(pool *db.Pool, validade time.Duration) *PostgresEstoque {
+	return &PostgresEstoque{db: pool, validade: validade}
+}
+
+// Reservar debita as quantidades de cada produto numa única transação.
+// O UPDATE condicional (disponivel >= quantidade) trava a linha do produto,
+// então reservas concorrentes nunca deixam o saldo negativo.
+func (e *PostgresEstoque) Reservar(ctx context.Context, itens []domain.ItemReserva) (string, error) {
+	reservaID := uuid.NewString()
+	err := e.db.WithTx(ctx, func(ctx context.Context) error {
+		q := e.db.Querier(ctx)
+
+		agora := time.Now()
+		_, err := q.Exec(ctx,
+			`INSERT INTO estoque_reservas (id, status, criado_em, expira_em) VALUES ($1, $2, $3, $4)`,
+			reservaID, reservaPendente, agora, agora.Add(e.validade),
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range agruparPorProduto(itens) {
+			tag, err := q.Exec(ctx,
+				`UPDATE estoque SET disponivel = disponivel - $2, atualizado_em = $3
+				 WHERE produto_id = $1 AND disponivel >= $2`,
+				item.ProdutoID, item.Quantidade, agora,
+			)
+			if err != nil {
+				return err
+			}
+			if tag.RowsAffected() == 0 {
+				return domain.ErrEstoqueInsuficiente
+			}
+
+			_, err = q.Exec(ctx,
+				`INSERT INTO estoque_reserva_itens (reserva_id, produto_id, quantidade) VALUES ($1, $2, $3)`,
+				reservaID, item.ProdutoID, item.Quantidade,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return reservaID, nil
+}
+
+// Confirmar marca a reserva como confirmada, desde que ainda esteja pendente e válida.
+func (e *PostgresEstoque) Confirmar(ctx context.Context, reservaID string) error {
+	tag, err := e.db.Querier(ctx).Exec(ctx,
+		`UPDATE estoque_reservas SET status = $2
+		 WHERE id = $1 AND (status = $3 AND expira_em > now() OR status = $2)`,
+		reservaID, reservaConfirmada, reservaPendente,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrReservaExpirada
+	}
+	return nil
+}
+
+// Liberar devolve ao estoque as quantidades de uma reserva pendente ou confirmada.
+// Reservas já liberadas são ignoradas, o que torna a operação idempotente.
+func (e *PostgresEstoque) Liberar(ctx context.Context, reservaID string) error {
+	return e.db.WithTx(ctx, func(ctx context.Context) error {
+		return liberarNaTransacao(ctx, e.db.Querier(ctx), reservaID)
+	})
+}
+
+// LiberarExpiradas devolve ao estoque as reservas pendentes que passaram da validade.
+// SKIP LOCKED permite rodar em várias instâncias sem que uma espere pela outra.
+func (e *PostgresEstoque) LiberarExpiradas(ctx context.Context) (int, error) {
+	var liberadas int
+	err := e.db.WithTx(ctx, func(ctx context.Context) error {
+		q := e.db.Querier(ctx)
+
+		rows, err := q.Query(ctx,
+			`SELECT id FROM estoque_reservas
+			 WHERE status = $1 AND expira_em <= now()
+			 ORDER BY expira_em
+			 LIMIT 100
+			 FOR UPDATE SKIP LOCKED`,
+			reservaPendente,
+		)
+		if err != nil {
+			return err
+		}
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if err := liberarNaTransacao(ctx, q, id); err != nil {
+				return err
+			}
+		}
+		liberadas = len(ids)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return liberadas, nil
+}
+
+// IniciarExpiracao roda LiberarExpiradas periodicamente até o contexto ser cancelado.
+func (e *PostgresEstoque) IniciarExpiracao(ctx context.Context, intervalo time.Duration) {
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			liberadas, err := e.LiberarExpiradas(ctx)
+			if err != nil {
+				log.Printf("Erro ao liberar reservas expiradas: %v", err)
+				continue
+			}
+			if liberadas > 0 {
+				log.Printf("%d reserva(s) de estoque expirada(s) liberada(s)", liberadas)
+			}
+		}
+	}
+}
+
+// DefinirDisponivel ajusta o saldo disponível de um produto, criando-o se necessário.
+func (e *PostgresEstoque) DefinirDisponivel(ctx context.Context, produtoID string, quantidade int) error {
+	if quantidade < 0 {
+		return domain.ErrQuantidadeInvalida
+	}
+	_, err := e.db.Querier(ctx).Exec(ctx,
+		`INSERT INTO estoque (produto_id, disponivel, atualizado_em) VALUES ($1, $2, now())
+		 ON CONFLICT (produto_id) DO UPDATE SET disponivel = EXCLUDED.disponivel, atualizado_em = EXCLUDED.atualizado_em`,
+		produtoID, quantidade,
+	)
+	return err
+}
+
+// BuscarDisponivel retorna o saldo disponível de um produto. Produtos sem
+// registro de estoque têm saldo zero.
+func (e *PostgresEstoque) BuscarDisponivel(ctx context.Context, produtoID string) (int, error) {
+	var disponivel int
+	err := e.db.Querier(ctx).QueryRow(ctx, `SELECT disponivel FROM estoque WHERE produto_id = $1`, produtoID).Scan(&disponivel)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return disponivel, err
+}
+
+// liberarNaTransacao marca a reserva como liberada e devolve suas quantidades.
+func liberarNaTransacao(ctx context.Context, q db.Querier, reservaID string) error {
+	tag, err := q.Exec(ctx,
+		`UPDATE estoque_reservas SET status = $2 WHERE id = $1 AND status IN ($3, $4)`,
+		reservaID, reservaLiberada, reservaPendente, reservaConfirmada,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil // Já liberada (ou inexistente): nada a devolver.
+	}
+
+	_, err = q.Exec(ctx,
+		`UPDATE estoque e SET disponivel = e.disponivel + i.quantidade, atualizado_em = now()
+		 FROM estoque_reserva_itens i
+		 WHERE i.reserva_id = $1 AND e.produto_id = i.produto_id`,
+		reservaID,
+	)
+	return err
+}
+
+// agruparPorProduto soma as quantidades por produto e ordena pelo ID,
+// para que transações concorrentes travem as linhas sempre na mesma ordem
+// (evitando deadlocks).
+func agruparPorProduto(itens []domain.ItemReserva) []domain.ItemReserva {
+	totais := make(map[string]int)
+	for _, item := range itens {
+		totais[item.ProdutoID] += item.Quantidade
+	}
+
+	agrupados := make([]domain.ItemReserva, 0, len(totais))
+	for produtoID, quantidade := range totais {
+		agrupados = append(agrupados, domain.ItemReserva{ProdutoID: produtoID, Quantidade: quantidade})
+	}
+	sort.Slice(agrupados, func(i, j int) bool { return agrupados[i].ProdutoID < agrupados[j].ProdutoID })
+	return agrupados
+}
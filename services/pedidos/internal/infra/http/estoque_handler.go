@@ -0,0 +1,73 @@
+package http
+
+import (
+	"ecommerce/pedidos/internal/application"
+	_ "ecommerce/pkg/common/problema" // Necessário para o swag resolver os tipos das respostas
+	"ecommerce/pkg/common/validacao"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// EstoqueHandler lida com as requisições HTTP de consulta e ajuste de estoque.
+type EstoqueHandler struct {
+	service *application.EstoqueService
+}
+
+// NewEstoqueHandler cria uma nova instância do handler de estoque.
+func NewEstoqueHandler(service *application.EstoqueService) *EstoqueHandler {
+	return &EstoqueHandler{
+		service: service,
+	}
+}
+
+// @Summary Consulta o estoque de um produto
+// @Description Retorna a quantidade disponível (não reservada) de um produto.
+// @Tags estoque
+// @Produce json
+// @Param produto_id path string true "ID do Produto"
+// @Success 200 {object} application.SaldoEstoque
+// @Failure 500 {object} problema.Problema "Erro interno ao consultar estoque"
+// @Router /estoque/{produto_id} [get]
+func (h *EstoqueHandler) BuscarEstoqueHandler(w http.ResponseWriter, r *http.Request) {
+	saldo, err := h.service.BuscarDisponivel(r.Context(), chi.URLParam(r, "produto_id"))
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(saldo)
+}
+
+// @Summary Ajusta o estoque de um produto
+// @Description Define a quantidade disponível de um produto. Reservas já feitas não são afetadas.
+// @Tags estoque
+// @Accept json
+// @Produce json
+// @Param produto_id path string true "ID do Produto"
+// @Param estoque body application.EstoqueInput true "Nova quantidade disponível"
+// @Success 200 {object} application.SaldoEstoque
+// @Failure 400 {object} problema.Problema "Corpo da requisição inválido"
+// @Failure 422 {object} problema.Problema "Quantidade inválida"
+// @Failure 500 {object} problema.Problema "Erro interno ao ajustar estoque"
+// @Router /estoque/{produto_id} [put]
+func (h *EstoqueHandler) DefinirEstoqueHandler(w http.ResponseWriter, r *http.Request) {
+	var input application.EstoqueInput
+	if err := validacao.Decodificar(w, r, &input); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	saldo, err := h.service.DefinirDisponivel(r.Context(), chi.URLParam(r, "produto_id"), input)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(saldo)
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"ecommerce/pedidos/internal/application"
+	"ecommerce/pkg/common/assinatura"
+	_ "ecommerce/pkg/common/problema" // Necessário para o swag resolver os tipos das respostas
+	"ecommerce/pkg/common/validacao"
+	"net/http"
+	"time"
+)
+
+// EventoHandler recebe os eventos que outros serviços publicam para o de pedidos.
+// A rota não passa pelo Kong: só são aceitos eventos assinados com o segredo
+// compartilhado pelos serviços.
+type EventoHandler struct {
+	service *application.PedidoService
+	segredo assinatura.Segredo
+}
+
+// NewEventoHandler cria uma nova instância do handler de eventos.
+func NewEventoHandler(service *application.PedidoService, segredo assinatura.Segredo) *EventoHandler {
+	return &EventoHandler{
+		service: service,
+		segredo: segredo,
+	}
+}
+
+// @Summary Recebe um evento do serviço de clientes
+// @Description Chamado pelo relay da outbox do serviço de clientes, que repete o evento com espera exponencial
+// @Description enquanto a resposta não for 2xx. cliente.mesclado transfere os pedidos do cliente absorvido
+// @Description para o que permaneceu; receber o mesmo evento de novo não muda nada. Outros tipos são ignorados.
+// @Description A requisição é assinada com o segredo EVENTOS_SEGREDO: X-Signature-Timestamp (segundos Unix, até 5
+// @Description minutos de diferença) e X-Signature = "sha256=" + HMAC-SHA256 em hexadecimal de timestamp + "." + corpo.
+// @Tags eventos
+// @Accept json
+// @Param evento body application.EventoClienteInput true "Evento da outbox do serviço de clientes"
+// @Param X-Signature-Timestamp header string true "Segundos Unix da assinatura"
+// @Param X-Signature header string true "sha256= + HMAC-SHA256 do timestamp, de um ponto e do corpo"
+// @Success 204 "Evento aplicado ou ignorado"
+// @Failure 400 {object} problema.Problema "Corpo da requisição inválido"
+// @Failure 401 {object} problema.Problema "Assinatura ausente, inválida ou expirada"
+// @Failure 422 {object} problema.Problema "Evento sem identificação ou com clientes inválidos"
+// @Failure 500 {object} problema.Problema "Erro interno ao aplicar o evento"
+// @Router /eventos/clientes [post]
+func (h *EventoHandler) ReceberEventoClienteHandler(w http.ResponseWriter, r *http.Request) {
+	corpo, err := validacao.LerCorpo(w, r)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+	if err := h.segredo.Conferir(r, corpo, time.Now()); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	var evento application.EventoClienteInput
+	if err := validacao.DecodificarJSON(corpo, &evento); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	if err := h.service.TratarEventoCliente(r.Context(), evento); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent) // Status 204 No Content
+}
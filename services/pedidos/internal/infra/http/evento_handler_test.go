@@ -0,0 +1,72 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"ecommerce/pedidos/internal/application"
+	"ecommerce/pedidos/internal/domain"
+	"ecommerce/pedidos/internal/infra/catalogo"
+	"ecommerce/pedidos/internal/infra/clientes"
+	"ecommerce/pedidos/internal/infra/estoque"
+	"ecommerce/pedidos/internal/infra/repository"
+	"ecommerce/pkg/common/assinatura"
+	"ecommerce/pkg/money"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const (
+	absorvido  = "3f1e2d4c-5b6a-4978-8a9b-0c1d2e3f4a5b"
+	permaneceu = "9a8b7c6d-5e4f-4321-8abc-def012345678"
+	produto    = "0b6f1c2d-3e4f-4a5b-8c7d-9e0f1a2b3c4d"
+)
+
+var segredoTeste = assinatura.Segredo("segredo-de-teste-com-32-caracteres")
+
+func TestReceberEventoClienteExigeAssinatura(t *testing.T) {
+	corpo := []byte(`{"id":"evento-1","tipo":"cliente.mesclado","agregado":"cliente","agregado_id":"` + absorvido +
+		`","dados":{"cliente_id":"` + absorvido + `","mesclado_com":"` + permaneceu + `"},"ocorrido_em":"2026-01-01T00:00:00Z"}`)
+
+	casos := []struct {
+		nome       string
+		assinar    func(r *http.Request)
+		status     int
+		transferiu bool
+	}{
+		{"assinado", func(r *http.Request) { segredoTeste.Assinar(r, corpo, time.Now()) }, http.StatusNoContent, true},
+		{"sem assinatura", func(*http.Request) {}, http.StatusUnauthorized, false},
+		{"assinado com outro segredo", func(r *http.Request) {
+			assinatura.Segredo("outro-segredo-de-teste-com-32-caracteres").Assinar(r, corpo, time.Now())
+		}, http.StatusUnauthorized, false},
+		{"assinatura antiga", func(r *http.Request) { segredoTeste.Assinar(r, corpo, time.Now().Add(-time.Hour)) }, http.StatusUnauthorized, false},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			repo := repository.NewPedidoRepositoryEmMemoria()
+			service := application.NewPedidoService(repo,
+				catalogo.NewCatalogoEmMemoria(domain.Produto{ID: produto, Nome: "Caneca", Preco: money.New(2990, "BRL"), Ativo: true}),
+				estoque.NewEstoqueEmMemoria(map[string]int{produto: 10}),
+				clientes.NewClientesEmMemoria(absorvido), &repository.TransacaoEmMemoria{})
+			_, err := service.CriarPedido(context.Background(), absorvido, []application.ItensInput{{ProdutoID: produto, Quantidade: 1}},
+				application.EnderecoEntregaInput{Rua: "Av. Paulista, 1000", Cidade: "São Paulo", Estado: "SP", CEP: "01310-100"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/eventos/clientes", bytes.NewReader(corpo))
+			c.assinar(r)
+			w := httptest.NewRecorder()
+			NewEventoHandler(service, segredoTeste).ReceberEventoClienteHandler(w, r)
+
+			if w.Code != c.status {
+				t.Errorf("status = %d, esperado %d: %s", w.Code, c.status, w.Body)
+			}
+			pagina, _ := repo.FindByClienteID(context.Background(), permaneceu, domain.FiltroPedidos{})
+			if transferiu := len(pagina.Pedidos) == 1; transferiu != c.transferiu {
+				t.Errorf("pedidos transferidos: %v, esperado %v", transferiu, c.transferiu)
+			}
+		})
+	}
+}
@@ -1,12 +1,18 @@
 package http
 
 import (
-	"database/sql"
+	"context"
 	"ecommerce/pedidos/internal/application" // Verifique o import
-	_ "ecommerce/pedidos/internal/domain"
+	"ecommerce/pedidos/internal/domain"
+	"ecommerce/pkg/common/etag"
+	_ "ecommerce/pkg/common/problema" // Necessário para o swag resolver os tipos das respostas
+	"ecommerce/pkg/common/validacao"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -25,66 +31,77 @@ func NewPedidoHandler(service *application.PedidoService) *PedidoHandler {
 
 // requestBody define a estrutura esperada no corpo da requisição para criar um pedido.
 type createRequestBody struct {
-	ClienteID string                   `json:"cliente_id"`
-	Itens     []application.ItensInput `json:"itens"`
+	ClienteID string                           `json:"cliente_id"`
+	Itens     []application.ItensInput         `json:"itens"`
+	Entrega   application.EnderecoEntregaInput `json:"entrega"`
+}
+
+// Validar confere o formato do corpo; é chamado por validacao.Decodificar.
+func (b createRequestBody) Validar() error {
+	return application.ValidarNovoPedido(b.ClienteID, b.Itens, b.Entrega)
 }
 
 // @Summary Cria um novo pedido
 // @Description Cria um novo pedido com base nos dados do cliente e itens fornecidos.
+// @Description A entrega pode referenciar um endereço do cliente (endereco_id) ou trazer o endereço completo.
 // @Tags pedidos
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Chave para repetir a requisição sem criar outro pedido"
 // @Param pedido body createRequestBody true "Dados para criação do pedido"
-// @Success 201
-// @Failure 400 {string} string "Corpo da requisição inválido"
-// @Failure 500 {string} string "Erro interno ao criar pedido"
+// @Success 201 {object} domain.Pedido
+// @Failure 400 {object} problema.Problema "Corpo da requisição inválido"
+// @Failure 409 {object} problema.Problema "Estoque insuficiente, ou Idempotency-Key ainda em andamento"
+// @Failure 422 {object} problema.Problema "Cliente, endereço de entrega ou produto inexistente/indisponível, ou Idempotency-Key já usada com outro corpo"
+// @Failure 500 {object} problema.Problema "Erro interno ao criar pedido"
+// @Failure 503 {object} problema.Problema "Serviço de clientes ou de catálogo indisponível"
 // @Router /pedidos [post]
 func (h *PedidoHandler) CriarPedidoHandler(w http.ResponseWriter, r *http.Request) {
 	var body createRequestBody
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		http.Error(w, "Corpo da requisição inválido", http.StatusBadRequest)
+	if err := validacao.Decodificar(w, r, &body); err != nil {
+		escreverErro(w, r, err)
 		return
 	}
 
-	_, err := h.service.CriarPedido(r.Context(), body.ClienteID, body.Itens)
+	pedido, err := h.service.CriarPedido(r.Context(), body.ClienteID, body.Itens, body.Entrega)
 	if err != nil {
-		http.Error(w, "Erro ao criar pedido: "+err.Error(), http.StatusInternalServerError)
+		escreverErro(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag.Formatar(pedido.Versao))
 	w.WriteHeader(http.StatusCreated) // Status 201 Created
-	json.NewEncoder(w)
+	json.NewEncoder(w).Encode(pedido)
 }
 
 // @Summary Busca um pedido por ID
 // @Description Retorna os detalhes de um pedido específico com base no seu UUID.
 // @Tags pedidos
 // @Produce json
+// @Description A resposta traz a ETag da versão do pedido; com If-None-Match igual a ela, a resposta é 304 sem corpo.
 // @Param id path string true "ID do Pedido (UUID)"
+// @Param If-None-Match header string false "ETag de uma resposta anterior"
 // @Success 200 {object} domain.Pedido
-// @Failure 400 {string} string "O ID do pedido é obrigatório"
-// @Failure 404 {string} string "Pedido não encontrado"
-// @Failure 500 {string} string "Erro interno ao buscar pedido"
+// @Success 304 "Pedido não mudou desde a ETag informada"
+// @Failure 404 {object} problema.Problema "Pedido não encontrado"
+// @Failure 500 {object} problema.Problema "Erro interno ao buscar pedido"
 // @Router /pedidos/{id} [get]
 func (h *PedidoHandler) BuscarPedidoPorIDHandler(w http.ResponseWriter, r *http.Request) {
 	// Extrai o 'id' do parâmetro da URL usando chi.
 	pedidoID := chi.URLParam(r, "id")
-	if pedidoID == "" {
-		http.Error(w, "O ID do pedido é obrigatório", http.StatusBadRequest)
-		return
-	}
 
 	// Chama o serviço da camada de aplicação.
 	pedido, err := h.service.BuscarPedidoPorID(r.Context(), pedidoID)
 	if err != nil {
-		// Se o erro for 'sql.ErrNoRows', significa que não encontramos o pedido.
-		if errors.Is(err, sql.ErrNoRows) {
-			http.Error(w, "Pedido não encontrado", http.StatusNotFound)
-			return
-		}
-		// Para outros tipos de erro, retornamos um erro genérico.
-		http.Error(w, "Erro ao buscar pedido: "+err.Error(), http.StatusInternalServerError)
+		escreverErro(w, r, err)
+		return
+	}
+
+	tag := etag.Formatar(pedido.Versao)
+	w.Header().Set("ETag", tag)
+	if etag.NaoModificado(r, tag) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
@@ -93,29 +110,208 @@ func (h *PedidoHandler) BuscarPedidoPorIDHandler(w http.ResponseWriter, r *http.
 	json.NewEncoder(w).Encode(pedido)
 }
 
-// @Summary Lista todos pedidos
-// @Description Retorna pedidos e seus itens.
+// @Summary Lista pedidos
+// @Description Retorna uma página de pedidos com seus itens, do mais novo para o mais antigo.
+// @Description Para a próxima página, repita a chamada com cursor igual ao next_cursor recebido (null na última página).
 // @Tags pedidos
 // @Produce json
-// @Success 200 {object} []domain.Pedido
-// @Failure 404 {string} string "Sem pedidos na base"
-// @Failure 500 {string} string "Erro interno ao listar pedidos"
+// @Param limit query int false "Tamanho da página (1 a 100, padrão 20)"
+// @Param cursor query string false "next_cursor da página anterior"
+// @Param cliente_id query string false "Somente pedidos deste cliente"
+// @Param status query string false "Somente pedidos neste status" Enums(aguardando_pagamento, pago, enviado, cancelado)
+// @Param criado_de query string false "Criados a partir desta data (RFC 3339 ou AAAA-MM-DD)"
+// @Param criado_ate query string false "Criados antes desta data (RFC 3339 ou AAAA-MM-DD)"
+// @Param total_min query int false "Total mínimo, em centavos"
+// @Param total_max query int false "Total máximo, em centavos"
+// @Success 200 {object} application.PaginaPedidosOutput
+// @Failure 400 {object} problema.Problema "Filtro, limite ou cursor inválido"
+// @Failure 500 {object} problema.Problema "Erro interno ao listar pedidos"
 // @Router /pedidos [get]
 func (h *PedidoHandler) ListarTodosPedidos(w http.ResponseWriter, r *http.Request) {
+	input, err := lerFiltrosListagem(r)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
 
-	pedidos, err := h.service.ListarPedidos(r.Context())
+	pagina, err := h.service.ListarPedidos(r.Context(), input)
 	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(pagina)
+}
+
+// lerFiltrosListagem converte os parâmetros de query de GET /pedidos.
+func lerFiltrosListagem(r *http.Request) (application.ListarPedidosInput, error) {
+	q := r.URL.Query()
+	input := application.ListarPedidosInput{
+		ClienteID: q.Get("cliente_id"),
+		Status:    q.Get("status"),
+		Cursor:    q.Get("cursor"),
+	}
 
-		// Se o erro for 'sql.ErrNoRows', significa que não encontramos o pedido.
-		if errors.Is(err, sql.ErrNoRows) {
-			http.Error(w, "Sem pedidos na base", http.StatusNotFound)
-			return
+	var err error
+	if v := q.Get("limit"); v != "" {
+		if input.Limite, err = strconv.Atoi(v); err != nil {
+			return input, fmt.Errorf("%w: limit deve ser um número", domain.ErrFiltroInvalido)
 		}
+	}
+	if input.CriadoDe, err = lerData(q.Get("criado_de")); err != nil {
+		return input, fmt.Errorf("%w: criado_de: %v", domain.ErrFiltroInvalido, err)
+	}
+	if input.CriadoAte, err = lerData(q.Get("criado_ate")); err != nil {
+		return input, fmt.Errorf("%w: criado_ate: %v", domain.ErrFiltroInvalido, err)
+	}
+	if input.TotalMin, err = lerCentavos(q.Get("total_min")); err != nil {
+		return input, fmt.Errorf("%w: total_min deve ser um valor em centavos", domain.ErrFiltroInvalido)
+	}
+	if input.TotalMax, err = lerCentavos(q.Get("total_max")); err != nil {
+		return input, fmt.Errorf("%w: total_max deve ser um valor em centavos", domain.ErrFiltroInvalido)
+	}
+	return input, nil
+}
+
+// lerData aceita data e hora RFC 3339 ou apenas a data (AAAA-MM-DD, meia-noite UTC).
+func lerData(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("data %q inválida, use RFC 3339 ou AAAA-MM-DD", v)
+	}
+	return t, nil
+}
 
-		http.Error(w, "Erro ao listar pedidos "+err.Error(), http.StatusBadRequest)
+func lerCentavos(v string) (*int64, error) {
+	if v == "" {
+		return nil, nil
+	}
+	centavos, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &centavos, nil
+}
+
+// @Summary Paga um pedido
+// @Description Confirma o pagamento de um pedido aguardando pagamento.
+// @Tags pedidos
+// @Produce json
+// @Accept json
+// @Param id path string true "ID do Pedido (UUID)"
+// @Param If-Match header string false "ETag da versão do pedido lida pelo chamador"
+// @Param alteracao body application.AlteracaoStatusInput false "Motivo da mudança de status"
+// @Success 200 {object} domain.Pedido
+// @Failure 400 {object} problema.Problema "Corpo da requisição ou If-Match inválido"
+// @Failure 404 {object} problema.Problema "Pedido não encontrado"
+// @Failure 409 {object} problema.Problema "Transição de status não permitida, reserva de estoque expirada ou pedido alterado por outra requisição"
+// @Failure 412 {object} problema.Problema "O pedido não está mais na versão do If-Match"
+// @Failure 500 {object} problema.Problema "Erro interno ao alterar o status do pedido"
+// @Router /pedidos/{id}/pagar [post]
+func (h *PedidoHandler) PagarPedidoHandler(w http.ResponseWriter, r *http.Request) {
+	h.alterarStatus(w, r, h.service.PagarPedido)
+}
+
+// @Summary Envia um pedido
+// @Description Marca um pedido pago como enviado.
+// @Tags pedidos
+// @Produce json
+// @Accept json
+// @Param id path string true "ID do Pedido (UUID)"
+// @Param If-Match header string false "ETag da versão do pedido lida pelo chamador"
+// @Param alteracao body application.AlteracaoStatusInput false "Motivo da mudança de status"
+// @Success 200 {object} domain.Pedido
+// @Failure 400 {object} problema.Problema "Corpo da requisição ou If-Match inválido"
+// @Failure 404 {object} problema.Problema "Pedido não encontrado"
+// @Failure 409 {object} problema.Problema "Transição de status não permitida ou pedido alterado por outra requisição"
+// @Failure 412 {object} problema.Problema "O pedido não está mais na versão do If-Match"
+// @Failure 500 {object} problema.Problema "Erro interno ao alterar o status do pedido"
+// @Router /pedidos/{id}/enviar [post]
+func (h *PedidoHandler) EnviarPedidoHandler(w http.ResponseWriter, r *http.Request) {
+	h.alterarStatus(w, r, h.service.EnviarPedido)
+}
+
+// @Summary Cancela um pedido
+// @Description Cancela um pedido que ainda não foi enviado.
+// @Tags pedidos
+// @Produce json
+// @Accept json
+// @Param id path string true "ID do Pedido (UUID)"
+// @Param If-Match header string false "ETag da versão do pedido lida pelo chamador"
+// @Param alteracao body application.AlteracaoStatusInput false "Motivo da mudança de status"
+// @Success 200 {object} domain.Pedido
+// @Failure 400 {object} problema.Problema "Corpo da requisição ou If-Match inválido"
+// @Failure 404 {object} problema.Problema "Pedido não encontrado"
+// @Failure 409 {object} problema.Problema "Transição de status não permitida ou pedido alterado por outra requisição"
+// @Failure 412 {object} problema.Problema "O pedido não está mais na versão do If-Match"
+// @Failure 500 {object} problema.Problema "Erro interno ao alterar o status do pedido"
+// @Router /pedidos/{id}/cancelar [post]
+func (h *PedidoHandler) CancelarPedidoHandler(w http.ResponseWriter, r *http.Request) {
+	h.alterarStatus(w, r, h.service.CancelarPedido)
+}
+
+// @Summary Histórico de status do pedido
+// @Description Retorna a linha do tempo de mudanças de status do pedido: quando, de qual status, para qual, quem e por quê.
+// @Tags pedidos
+// @Produce json
+// @Param id path string true "ID do Pedido (UUID)"
+// @Success 200 {array} domain.MudancaStatus
+// @Failure 404 {object} problema.Problema "Pedido não encontrado"
+// @Failure 500 {object} problema.Problema "Erro interno ao buscar histórico"
+// @Router /pedidos/{id}/historico [get]
+func (h *PedidoHandler) BuscarHistoricoHandler(w http.ResponseWriter, r *http.Request) {
+	historico, err := h.service.BuscarHistorico(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		escreverErro(w, r, err)
 		return
 	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(historico)
+}
+
+// autorDaRequisicao identifica quem fez a requisição. O Kong preenche
+// X-Consumer-Username após a autenticação por key-auth.
+func autorDaRequisicao(r *http.Request) string {
+	if autor := r.Header.Get("X-Consumer-Username"); autor != "" {
+		return autor
+	}
+	return "anonimo"
+}
+
+// alterarStatus executa um caso de uso de transição de status e traduz os erros do domínio para HTTP.
+func (h *PedidoHandler) alterarStatus(w http.ResponseWriter, r *http.Request, casoDeUso func(ctx context.Context, id string, input application.AlteracaoStatusInput) (*domain.Pedido, error)) {
+	// O corpo é opcional: sem ele, a mudança é registrada sem motivo.
+	var input application.AlteracaoStatusInput
+	if err := validacao.Decodificar(w, r, &input); err != nil && !errors.Is(err, validacao.ErrCorpoVazio) {
+		escreverErro(w, r, err)
+		return
+	}
+	input.Autor = autorDaRequisicao(r)
+
+	versao, err := etag.VersaoEsperada(r)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+	input.Versao = versao
+
+	pedido, err := casoDeUso(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag.Formatar(pedido.Versao))
 	w.WriteHeader(http.StatusOK) // Status 200 OK
-	json.NewEncoder(w).Encode(pedidos)
+	json.NewEncoder(w).Encode(pedido)
 }
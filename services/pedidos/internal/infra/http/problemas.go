@@ -0,0 +1,45 @@
+package http
+
+import (
+	"ecommerce/pedidos/internal/domain"
+	"ecommerce/pkg/common/assinatura"
+	"ecommerce/pkg/common/etag"
+	"ecommerce/pkg/common/problema"
+	"errors"
+	"net/http"
+)
+
+// problemas associa os erros do domínio de pedidos aos códigos estáveis das
+// respostas problem+json. Os códigos fazem parte do contrato da API: não mude
+// um código existente, crie outro.
+var problemas = problema.NewCatalogo().
+	Registrar(etag.ErrIfMatchInvalido, http.StatusBadRequest, "if_match_invalido", "If-Match inválido").
+	Registrar(domain.ErrFiltroInvalido, http.StatusBadRequest, "filtro_invalido", "Filtro inválido").
+	Registrar(assinatura.ErrAssinaturaInvalida, http.StatusUnauthorized, "requisicao_nao_assinada", "Requisição sem assinatura válida").
+	Registrar(domain.ErrPedidoNaoEncontrado, http.StatusNotFound, "pedido_nao_encontrado", "Pedido não encontrado").
+	Registrar(domain.ErrAssinaturaNaoEncontrada, http.StatusNotFound, "assinatura_nao_encontrada", "Assinatura de webhook não encontrada").
+	Registrar(domain.ErrEntregaNaoEncontrada, http.StatusNotFound, "entrega_nao_encontrada", "Entrega de webhook não encontrada").
+	Registrar(domain.ErrConflitoDeVersao, http.StatusConflict, "conflito_de_versao", "Pedido alterado por outra requisição").
+	Registrar(domain.ErrStatusInvalido, http.StatusConflict, "transicao_de_status_invalida", "Transição de status não permitida").
+	Registrar(domain.ErrEstoqueInsuficiente, http.StatusConflict, "estoque_insuficiente", "Estoque insuficiente").
+	Registrar(domain.ErrReservaExpirada, http.StatusConflict, "reserva_expirada", "Reserva de estoque expirada").
+	Registrar(domain.ErrItemInvalido, http.StatusUnprocessableEntity, "item_invalido", "Item do pedido inválido").
+	Registrar(domain.ErrClienteNaoEncontrado, http.StatusUnprocessableEntity, "cliente_nao_encontrado", "Cliente não encontrado").
+	Registrar(domain.ErrEnderecoNaoEncontrado, http.StatusUnprocessableEntity, "endereco_nao_encontrado", "Endereço não encontrado para o cliente").
+	Registrar(domain.ErrEnderecoEntregaInvalido, http.StatusUnprocessableEntity, "endereco_entrega_invalido", "Endereço de entrega inválido").
+	Registrar(domain.ErrProdutoNaoEncontrado, http.StatusUnprocessableEntity, "produto_nao_encontrado", "Produto não encontrado no catálogo").
+	Registrar(domain.ErrProdutoIndisponivel, http.StatusUnprocessableEntity, "produto_indisponivel", "Produto indisponível para venda").
+	Registrar(domain.ErrQuantidadeInvalida, http.StatusUnprocessableEntity, "quantidade_invalida", "Quantidade de estoque inválida").
+	Registrar(domain.ErrAssinaturaInvalida, http.StatusUnprocessableEntity, "assinatura_invalida", "Assinatura de webhook inválida").
+	Registrar(domain.ErrClientesIndisponivel, http.StatusServiceUnavailable, "clientes_indisponivel", "Serviço de clientes indisponível").
+	Registrar(domain.ErrCatalogoIndisponivel, http.StatusServiceUnavailable, "catalogo_indisponivel", "Serviço de catálogo indisponível")
+
+// escreverErro responde err como problem+json. O conflito de versão é 412
+// quando a requisição trouxe If-Match (ver etag.StatusConflito).
+func escreverErro(w http.ResponseWriter, r *http.Request, err error) {
+	p := problemas.Problema(r, err)
+	if errors.Is(err, domain.ErrConflitoDeVersao) {
+		p.Status = etag.StatusConflito(r)
+	}
+	problema.Escrever(w, r, p)
+}
@@ -0,0 +1,26 @@
+package http
+
+import (
+	"ecommerce/pedidos/internal/domain"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProblemasDoDominio(t *testing.T) {
+	casos := []struct {
+		err    error
+		status int
+		codigo string
+	}{
+		{domain.ErrStatusInvalido, http.StatusConflict, "transicao_de_status_invalida"},
+		{fmt.Errorf("%w: quantidade 0", domain.ErrItemInvalido), http.StatusUnprocessableEntity, "item_invalido"},
+	}
+	r := httptest.NewRequest(http.MethodPost, "/pedidos/1/pagar", nil)
+	for _, c := range casos {
+		if p := problemas.Problema(r, c.err); p.Status != c.status || p.Codigo != c.codigo {
+			t.Errorf("%v: %d %s, esperado %d %s", c.err, p.Status, p.Codigo, c.status, c.codigo)
+		}
+	}
+}
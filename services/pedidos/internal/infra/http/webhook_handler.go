@@ -0,0 +1,221 @@
+package http
+
+import (
+	"ecommerce/pedidos/internal/application"
+	"ecommerce/pedidos/internal/domain"
+	_ "ecommerce/pkg/common/problema" // Necessário para o swag resolver os tipos das respostas
+	"ecommerce/pkg/common/validacao"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// WebhookHandler lida com as requisições HTTP de assinaturas e entregas de webhook.
+type WebhookHandler struct {
+	service *application.WebhookService
+}
+
+// NewWebhookHandler cria uma nova instância do handler de webhooks.
+func NewWebhookHandler(service *application.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+	}
+}
+
+// @Summary Cria uma assinatura de webhook
+// @Description Cadastra uma URL para receber eventos de pedido (pedido.criado, pedido.pago, pedido.enviado,
+// @Description pedido.cancelado ou pedido.* para todos) por POST com o evento em JSON no corpo.
+// @Description Cada requisição leva os cabeçalhos X-Webhook-Event, X-Webhook-Delivery, X-Webhook-Timestamp e
+// @Description X-Webhook-Signature = "sha256=" + HMAC-SHA256 em hexadecimal, com o segredo, de timestamp + "." + corpo.
+// @Description Respostas fora de 2xx são repetidas com espera exponencial; redirecionamentos não são seguidos.
+// @Description A URL precisa apontar para um endereço público: localhost, IPs privados ou link-local e nomes
+// @Description internos são recusados. Sem segredo, um é gerado; ele só é devolvido nesta resposta.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param assinatura body application.AssinaturaInput true "Destino, eventos e segredo opcional"
+// @Success 201 {object} application.AssinaturaOutput
+// @Failure 400 {object} problema.Problema "Corpo da requisição inválido"
+// @Failure 422 {object} problema.Problema "URL, eventos ou segredo inválidos"
+// @Failure 500 {object} problema.Problema "Erro interno ao criar a assinatura"
+// @Router /webhooks [post]
+func (h *WebhookHandler) CriarAssinaturaHandler(w http.ResponseWriter, r *http.Request) {
+	var input application.AssinaturaInput
+	if err := validacao.Decodificar(w, r, &input); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	assinatura, err := h.service.CriarAssinatura(r.Context(), input)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated) // Status 201 Created
+	json.NewEncoder(w).Encode(assinatura)
+}
+
+// @Summary Lista as assinaturas de webhook
+// @Description Retorna todas as assinaturas, sem os segredos.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} application.AssinaturaOutput
+// @Failure 500 {object} problema.Problema "Erro interno ao listar as assinaturas"
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListarAssinaturasHandler(w http.ResponseWriter, r *http.Request) {
+	assinaturas, err := h.service.ListarAssinaturas(r.Context())
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(assinaturas)
+}
+
+// @Summary Busca uma assinatura de webhook
+// @Description Retorna uma assinatura pelo ID, sem o segredo.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "ID da assinatura"
+// @Success 200 {object} application.AssinaturaOutput
+// @Failure 404 {object} problema.Problema "Assinatura não encontrada"
+// @Failure 500 {object} problema.Problema "Erro interno ao buscar a assinatura"
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) BuscarAssinaturaHandler(w http.ResponseWriter, r *http.Request) {
+	assinatura, err := h.service.BuscarAssinatura(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(assinatura)
+}
+
+// @Summary Altera uma assinatura de webhook
+// @Description Substitui URL e eventos. "ativa" ausente mantém a situação atual e "segredo" ausente mantém o
+// @Description segredo; um segredo novo passa a valer para as próximas tentativas, inclusive das entregas pendentes.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "ID da assinatura"
+// @Param assinatura body application.AssinaturaInput true "Novos dados da assinatura"
+// @Success 200 {object} application.AssinaturaOutput
+// @Failure 400 {object} problema.Problema "Corpo da requisição inválido"
+// @Failure 404 {object} problema.Problema "Assinatura não encontrada"
+// @Failure 422 {object} problema.Problema "URL, eventos ou segredo inválidos"
+// @Failure 500 {object} problema.Problema "Erro interno ao alterar a assinatura"
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) AtualizarAssinaturaHandler(w http.ResponseWriter, r *http.Request) {
+	var input application.AssinaturaInput
+	if err := validacao.Decodificar(w, r, &input); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	assinatura, err := h.service.AtualizarAssinatura(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(assinatura)
+}
+
+// @Summary Exclui uma assinatura de webhook
+// @Description Remove a assinatura com suas entregas; as pendentes não são mais enviadas.
+// @Tags webhooks
+// @Param id path string true "ID da assinatura"
+// @Success 204 "Assinatura excluída"
+// @Failure 404 {object} problema.Problema "Assinatura não encontrada"
+// @Failure 500 {object} problema.Problema "Erro interno ao excluir a assinatura"
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) ExcluirAssinaturaHandler(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.ExcluirAssinatura(r.Context(), chi.URLParam(r, "id")); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent) // Status 204 No Content
+}
+
+// @Summary Lista as entregas de uma assinatura
+// @Description Retorna as entregas mais recentes, da mais nova para a mais antiga, com status e tentativas.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "ID da assinatura"
+// @Param limit query int false "Quantidade de entregas (1 a 100, padrão 20)"
+// @Success 200 {array} application.EntregaOutput
+// @Failure 400 {object} problema.Problema "Limite inválido"
+// @Failure 404 {object} problema.Problema "Assinatura não encontrada"
+// @Failure 500 {object} problema.Problema "Erro interno ao listar as entregas"
+// @Router /webhooks/{id}/entregas [get]
+func (h *WebhookHandler) ListarEntregasHandler(w http.ResponseWriter, r *http.Request) {
+	var limite int
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		if limite, err = strconv.Atoi(v); err != nil {
+			escreverErro(w, r, fmt.Errorf("%w: limit deve ser um número", domain.ErrFiltroInvalido))
+			return
+		}
+	}
+
+	entregas, err := h.service.ListarEntregas(r.Context(), chi.URLParam(r, "id"), limite)
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(entregas)
+}
+
+// @Summary Busca uma entrega de webhook
+// @Description Retorna a entrega com o payload enviado e o histórico de tentativas (status HTTP, erro e duração).
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "ID da assinatura"
+// @Param entregaId path string true "ID da entrega"
+// @Success 200 {object} application.EntregaOutput
+// @Failure 404 {object} problema.Problema "Entrega não encontrada"
+// @Failure 500 {object} problema.Problema "Erro interno ao buscar a entrega"
+// @Router /webhooks/{id}/entregas/{entregaId} [get]
+func (h *WebhookHandler) BuscarEntregaHandler(w http.ResponseWriter, r *http.Request) {
+	entrega, err := h.service.BuscarEntrega(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "entregaId"))
+	if err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK) // Status 200 OK
+	json.NewEncoder(w).Encode(entrega)
+}
+
+// @Summary Reenvia uma entrega de webhook
+// @Description Devolve a entrega à fila com as tentativas zeradas, inclusive se já foi entregue ou falhou.
+// @Description O envio é assíncrono; acompanhe o resultado pela consulta da entrega.
+// @Tags webhooks
+// @Param id path string true "ID da assinatura"
+// @Param entregaId path string true "ID da entrega"
+// @Success 202 "Entrega agendada para reenvio"
+// @Failure 404 {object} problema.Problema "Entrega não encontrada"
+// @Failure 500 {object} problema.Problema "Erro interno ao reenviar a entrega"
+// @Router /webhooks/{id}/entregas/{entregaId}/reenviar [post]
+func (h *WebhookHandler) ReenviarEntregaHandler(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.ReenviarEntrega(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "entregaId")); err != nil {
+		escreverErro(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted) // Status 202 Accepted
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"ecommerce/pedidos/internal/domain"
+	"ecommerce/pkg/db"
+	"ecommerce/pkg/money"
+	"ecommerce/pkg/outbox"
+)
+
+// agregadoPedido identifica os eventos de pedido na outbox; os tipos estão
+// em domain (EventoPedidoCriado etc.).
+const agregadoPedido = "pedido"
+
+// dadosEventoPedido é o conteúdo (campo "dados") dos eventos de pedido.
+type dadosEventoPedido struct {
+	PedidoID       string            `json:"pedido_id"`
+	ClienteID      string            `json:"cliente_id"`
+	StatusAnterior domain.Status     `json:"status_anterior,omitempty"`
+	Status         domain.Status     `json:"status"`
+	Total          money.Money       `json:"total"`
+	Versao         int               `json:"versao"`
+	Autor          string            `json:"autor"`
+	Motivo         string            `json:"motivo,omitempty"`
+	Itens          []dadosEventoItem `json:"itens,omitempty"` // Só em pedido.criado.
+}
+
+type dadosEventoItem struct {
+	ProdutoID  string      `json:"produto_id"`
+	Nome       string      `json:"nome"`
+	Preco      money.Money `json:"preco"`
+	Quantidade int         `json:"quantidade"`
+}
+
+// gravarEventos registra na outbox um evento para cada mudança de status
+// pendente do pedido, dentro da transação que grava essas mudanças.
+// versao é a versão do pedido após a gravação.
+func gravarEventos(ctx context.Context, q db.Querier, pedido *domain.Pedido, versao int) error {
+	for _, mudanca := range pedido.Eventos() {
+		dados := dadosEventoPedido{
+			PedidoID:       pedido.ID,
+			ClienteID:      pedido.ClienteID,
+			StatusAnterior: mudanca.StatusAnterior,
+			Status:         mudanca.StatusNovo,
+			Total:          pedido.Total,
+			Versao:         versao,
+			Autor:          mudanca.Autor,
+			Motivo:         mudanca.Motivo,
+		}
+
+		tipo := mudanca.TipoEvento()
+		if tipo == domain.EventoPedidoCriado {
+			for _, item := range pedido.Itens {
+				dados.Itens = append(dados.Itens, dadosEventoItem{
+					ProdutoID:  item.ProdutoID,
+					Nome:       item.Nome,
+					Preco:      item.Preco,
+					Quantidade: item.Quantidade,
+				})
+			}
+		}
+
+		evento, err := outbox.NovoEvento(tipo, agregadoPedido, pedido.ID, dados)
+		if err != nil {
+			return err
+		}
+		evento.OcorridoEm = mudanca.OcorridoEm
+		if err := outbox.Gravar(ctx, q, evento); err != nil {
+			return err
+		}
+	}
+	return nil
+}
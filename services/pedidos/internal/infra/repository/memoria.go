@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"ecommerce/pedidos/internal/domain"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PedidoRepositoryEmMemoria é um repositório de pedidos falso, para testes e
+// desenvolvimento local. Guarda cópias dos pedidos e segue as mesmas regras
+// de versão do repositório Postgres.
+type PedidoRepositoryEmMemoria struct {
+	mu        sync.RWMutex
+	pedidos   map[string]domain.Pedido
+	historico map[string][]*domain.MudancaStatus
+	mesclados map[string]string
+}
+
+// NewPedidoRepositoryEmMemoria cria o repositório falso vazio.
+func NewPedidoRepositoryEmMemoria() *PedidoRepositoryEmMemoria {
+	return &PedidoRepositoryEmMemoria{
+		pedidos:   make(map[string]domain.Pedido),
+		historico: make(map[string][]*domain.MudancaStatus),
+		mesclados: make(map[string]string),
+	}
+}
+
+// Save implementa domain.PedidoRepository.
+func (r *PedidoRepositoryEmMemoria) Save(_ context.Context, pedido *domain.Pedido) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pedido.ID = uuid.NewString()
+	pedido.AtualizadoEm = time.Now()
+	pedido.Versao = 1
+	r.registrarHistorico(pedido)
+	r.pedidos[pedido.ID] = *pedido
+	return nil
+}
+
+// FindByID implementa domain.PedidoRepository.
+func (r *PedidoRepositoryEmMemoria) FindByID(_ context.Context, id string) (*domain.Pedido, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pedido, ok := r.pedidos[id]
+	if !ok {
+		return nil, domain.ErrPedidoNaoEncontrado
+	}
+	return &pedido, nil
+}
+
+// List implementa domain.PedidoRepository. Só os filtros de cliente e status
+// são aplicados.
+func (r *PedidoRepositoryEmMemoria) List(_ context.Context, filtro domain.FiltroPedidos) (*domain.PaginaPedidos, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var pedidos []*domain.Pedido
+	for _, pedido := range r.pedidos {
+		if filtro.ClienteID != "" && pedido.ClienteID != filtro.ClienteID ||
+			filtro.Status != "" && pedido.Status != filtro.Status {
+			continue
+		}
+		copia := pedido
+		pedidos = append(pedidos, &copia)
+	}
+	// Do mais novo para o mais antigo, como no Postgres.
+	sort.Slice(pedidos, func(i, j int) bool {
+		if !pedidos[i].CriadoEm.Equal(pedidos[j].CriadoEm) {
+			return pedidos[i].CriadoEm.After(pedidos[j].CriadoEm)
+		}
+		return pedidos[i].ID > pedidos[j].ID
+	})
+
+	if filtro.Apos != nil {
+		for i, pedido := range pedidos {
+			if pedido.ID == filtro.Apos.ID {
+				pedidos = pedidos[i+1:]
+				break
+			}
+		}
+	}
+	pagina := &domain.PaginaPedidos{Pedidos: pedidos}
+	if filtro.Limite > 0 && len(pedidos) > filtro.Limite {
+		pagina.Pedidos = pedidos[:filtro.Limite]
+		ultimo := pagina.Pedidos[filtro.Limite-1]
+		pagina.Proximo = &domain.CursorPedido{CriadoEm: ultimo.CriadoEm, ID: ultimo.ID}
+	}
+	return pagina, nil
+}
+
+// FindByClienteID implementa domain.PedidoRepository.
+func (r *PedidoRepositoryEmMemoria) FindByClienteID(ctx context.Context, clienteID string, filtro domain.FiltroPedidos) (*domain.PaginaPedidos, error) {
+	filtro.ClienteID = clienteID
+	return r.List(ctx, filtro)
+}
+
+// Update implementa domain.PedidoRepository: só grava se o pedido ainda
+// estiver na versão em que foi lido.
+func (r *PedidoRepositoryEmMemoria) Update(_ context.Context, pedido *domain.Pedido) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	atual, ok := r.pedidos[pedido.ID]
+	if !ok {
+		return domain.ErrPedidoNaoEncontrado
+	}
+	if atual.Versao != pedido.Versao {
+		return domain.ErrConflitoDeVersao
+	}
+
+	r.registrarHistorico(pedido)
+	pedido.Versao++
+	r.pedidos[pedido.ID] = *pedido
+	return nil
+}
+
+// FindHistorico implementa domain.PedidoRepository.
+func (r *PedidoRepositoryEmMemoria) FindHistorico(_ context.Context, pedidoID string) ([]*domain.MudancaStatus, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]*domain.MudancaStatus(nil), r.historico[pedidoID]...), nil
+}
+
+// TransferirCliente implementa domain.PedidoRepository.
+func (r *PedidoRepositoryEmMemoria) TransferirCliente(_ context.Context, deClienteID, paraClienteID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.mesclados[deClienteID]; !ok {
+		r.mesclados[deClienteID] = paraClienteID
+	}
+	absorvidos := map[string]bool{}
+	destino := deClienteID
+	for passo := 0; passo < limiteCadeiaDeMesclagens; passo++ {
+		proximo, ok := r.mesclados[destino]
+		if !ok {
+			break
+		}
+		absorvidos[destino] = true
+		destino = proximo
+	}
+
+	var transferidos int64
+	for id, pedido := range r.pedidos {
+		if !absorvidos[pedido.ClienteID] || pedido.ClienteID == destino {
+			continue
+		}
+		pedido.ClienteID = destino
+		pedido.AtualizadoEm = time.Now()
+		pedido.Versao++
+		r.pedidos[id] = pedido
+		transferidos++
+	}
+	return transferidos, nil
+}
+
+// registrarHistorico move os eventos pendentes do pedido para o histórico.
+func (r *PedidoRepositoryEmMemoria) registrarHistorico(pedido *domain.Pedido) {
+	for _, evento := range pedido.Eventos() {
+		evento.PedidoID = pedido.ID
+		r.historico[pedido.ID] = append(r.historico[pedido.ID], &evento)
+	}
+	pedido.LimparEventos()
+}
+
+// TransacaoEmMemoria implementa domain.Transacao para os fakes em memória:
+// executa fn direto, uma de cada vez. Nada é desfeito em caso de erro.
+type TransacaoEmMemoria struct {
+	mu sync.Mutex
+}
+
+// WithTx implementa domain.Transacao.
+func (t *TransacaoEmMemoria) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return fn(ctx)
+}
@@ -2,183 +2,429 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 
 	// Import CORRETO do domain, usando o nome do módulo definido no go.mod
 	"ecommerce/pedidos/internal/domain"
+	"ecommerce/pkg/db"
+	"ecommerce/pkg/money"
+	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	// Import do UUID
 	"github.com/google/uuid"
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type postgresPedidoRepository struct {
-	db *sql.DB
+	db *db.Pool
 }
 
-// O construtor recebe a conexão pronta
-func NewPostgresPedidoRepository(db *sql.DB) domain.PedidoRepository {
-	return &postgresPedidoRepository{db: db}
+// O construtor recebe o pool pronto
+func NewPostgresPedidoRepository(pool *db.Pool) domain.PedidoRepository {
+	return &postgresPedidoRepository{db: pool}
 }
 
-// Save persiste um pedido no banco de dados usando uma transação.
+// Save persiste o pedido, seu endereço de entrega, itens, histórico e o evento
+// pedido.criado na outbox em uma única transação (ou na transação já aberta em ctx).
 func (r *postgresPedidoRepository) Save(ctx context.Context, pedido *domain.Pedido) error {
 	pedido.ID = uuid.NewString()
 	pedido.AtualizadoEm = time.Now()
+	pedido.Versao = 1
 
-	tx, err := r.db.BeginTx(ctx, nil)
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		q := r.db.Querier(ctx)
+
+		// Valores monetários são gravados em unidades menores (BIGINT); a moeda fica no pedido
+		// e vale também para os itens.
+		pedidoQuery := `INSERT INTO pedidos (id, cliente_id, status, total, moeda, reserva_id, criado_em, atualizado_em, versao)
+						 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+		_, err := q.Exec(ctx, pedidoQuery, pedido.ID, pedido.ClienteID, pedido.Status, pedido.Total.Valor, pedido.Total.Moeda,
+			pgtype.Text{String: pedido.ReservaID, Valid: pedido.ReservaID != ""}, pedido.CriadoEm, pedido.AtualizadoEm, pedido.Versao)
+		if err != nil {
+			return err
+		}
+
+		// O endereço de entrega é uma cópia própria do pedido, em tabela separada.
+		enderecoQuery := `INSERT INTO pedido_enderecos (pedido_id, endereco_origem_id, rua, cidade, estado, cep)
+						  VALUES ($1, $2, $3, $4, $5, $6)`
+		entrega := pedido.Entrega
+		origemID := pgtype.Int8{Int64: entrega.EnderecoOrigemID, Valid: entrega.EnderecoOrigemID != 0}
+		_, err = q.Exec(ctx, enderecoQuery, pedido.ID, origemID, entrega.Rua, entrega.Cidade, entrega.Estado, entrega.CEP)
+		if err != nil {
+			return err
+		}
+
+		if err = inserirItens(ctx, q, pedido); err != nil {
+			return err
+		}
+
+		if err = inserirHistorico(ctx, q, pedido); err != nil {
+			return err
+		}
+
+		if err = gravarEventos(ctx, q, pedido, pedido.Versao); err != nil {
+			return err
+		}
+
+		// Os eventos só saem do pedido quando a transação mais externa é confirmada.
+		db.AposCommit(ctx, pedido.LimparEventos)
+		return nil
+	})
+}
+
+// Update persiste as alterações de estado de um pedido já existente,
+// junto com as mudanças de status registradas e seus eventos na outbox, na
+// mesma transação.
+// O UPDATE só acontece se o pedido ainda estiver na versão em que foi lido;
+// caso contrário, outra requisição o alterou antes e o resultado é
+// domain.ErrConflitoDeVersao. Em caso de sucesso, pedido.Versao avança.
+func (r *postgresPedidoRepository) Update(ctx context.Context, pedido *domain.Pedido) error {
+	return r.db.WithTx(ctx, func(ctx context.Context) error {
+		q := r.db.Querier(ctx)
+
+		query := `UPDATE pedidos SET status = $2, total = $3, moeda = $4, atualizado_em = $5, versao = versao + 1
+				  WHERE id = $1 AND versao = $6
+				  RETURNING versao`
+		var versao int
+		err := q.QueryRow(ctx, query, pedido.ID, pedido.Status, pedido.Total.Valor, pedido.Total.Moeda, pedido.AtualizadoEm, pedido.Versao).
+			Scan(&versao)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return r.conflitoOuInexistente(ctx, pedido.ID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = inserirHistorico(ctx, q, pedido); err != nil {
+			return err
+		}
+
+		if err = gravarEventos(ctx, q, pedido, versao); err != nil {
+			return err
+		}
+
+		db.AposCommit(ctx, func() {
+			pedido.Versao = versao
+			pedido.LimparEventos()
+		})
+		return nil
+	})
+}
+
+// limiteCadeiaDeMesclagens limita os passos seguidos de mesclagem em
+// mesclagem até o cliente que permaneceu; protege de um ciclo, que o serviço
+// de clientes não produz.
+const limiteCadeiaDeMesclagens = 100
+
+// TransferirCliente registra a mesclagem e troca o cliente dos pedidos,
+// avançando a versão de cada um: ETags anteriores à transferência deixam de
+// valer. O destino é o fim da cadeia de mesclagens já registradas (A→B com
+// B→C leva a C), e os pedidos que ainda estiverem em algum cliente da cadeia
+// também vão para ele.
+func (r *postgresPedidoRepository) TransferirCliente(ctx context.Context, deClienteID, paraClienteID string) (int64, error) {
+	var transferidos int64
+	err := r.db.WithTx(ctx, func(ctx context.Context) error {
+		q := r.db.Querier(ctx)
+
+		// Uma mesclagem por vez: sem isso, A→B e B→C simultâneas podem deixar
+		// os pedidos de A em B, cada uma sem ver o que a outra gravou.
+		if _, err := q.Exec(ctx, `LOCK TABLE clientes_mesclados IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+			return err
+		}
+		_, err := q.Exec(ctx,
+			`INSERT INTO clientes_mesclados (de_cliente_id, para_cliente_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			deClienteID, paraClienteID,
+		)
+		if err != nil {
+			return err
+		}
+
+		const cadeia = `
+			WITH RECURSIVE cadeia (cliente_id, passo) AS (
+				SELECT $1::text, 0
+				UNION ALL
+				SELECT m.para_cliente_id, c.passo + 1
+				FROM cadeia c
+				JOIN clientes_mesclados m ON m.de_cliente_id = c.cliente_id
+				WHERE c.passo < $2
+			)
+			SELECT array_agg(cliente_id ORDER BY passo) FROM cadeia`
+		var clientes []string
+		if err := q.QueryRow(ctx, cadeia, deClienteID, limiteCadeiaDeMesclagens).Scan(&clientes); err != nil {
+			return err
+		}
+		destino, absorvidos := clientes[len(clientes)-1], clientes[:len(clientes)-1]
+
+		tag, err := q.Exec(ctx,
+			`UPDATE pedidos SET cliente_id = $2, atualizado_em = $3, versao = versao + 1 WHERE cliente_id = ANY($1) AND cliente_id <> $2`,
+			absorvidos, destino, time.Now(),
+		)
+		if err != nil {
+			return err
+		}
+		transferidos = tag.RowsAffected()
+		return nil
+	})
+	return transferidos, err
+}
+
+// conflitoOuInexistente explica um UPDATE com versão que não encontrou linha:
+// o pedido existe em outra versão ou não existe.
+func (r *postgresPedidoRepository) conflitoOuInexistente(ctx context.Context, id string) error {
+	var existe bool
+	err := r.db.Querier(ctx).QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM pedidos WHERE id = $1)`, id).Scan(&existe)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback()
+	if existe {
+		return domain.ErrConflitoDeVersao
+	}
+	return domain.ErrPedidoNaoEncontrado
+}
+
+// FindHistorico retorna as mudanças de status de um pedido em ordem cronológica.
+func (r *postgresPedidoRepository) FindHistorico(ctx context.Context, pedidoID string) ([]*domain.MudancaStatus, error) {
+	const query = `
+		SELECT pedido_id, status_anterior, status_novo, autor, motivo, ocorrido_em
+		FROM pedido_historico
+		WHERE pedido_id = $1
+		ORDER BY ocorrido_em, id`
 
-	pedidoQuery := `INSERT INTO pedidos (id, cliente_id, status, total, criado_em, atualizado_em)
-					 VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err = tx.ExecContext(ctx, pedidoQuery, pedido.ID, pedido.ClienteID, pedido.Status, pedido.Total, pedido.CriadoEm, pedido.AtualizadoEm)
+	rows, err := r.db.Querier(ctx).Query(ctx, query, pedidoID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer rows.Close()
+
+	historico := []*domain.MudancaStatus{}
+	for rows.Next() {
+		var m domain.MudancaStatus
+		var statusAnterior pgtype.Text
+		if err := rows.Scan(&m.PedidoID, &statusAnterior, &m.StatusNovo, &m.Autor, &m.Motivo, &m.OcorridoEm); err != nil {
+			return nil, err
+		}
+		m.StatusAnterior = domain.Status(statusAnterior.String)
+		historico = append(historico, &m)
 	}
 
-	itemQuery := `INSERT INTO pedido_itens (pedido_id, produto_id, nome_produto, preco, quantidade)
-				  VALUES ($1, $2, $3, $4, $5)`
-	for _, item := range pedido.Itens {
-		_, err = tx.ExecContext(ctx, itemQuery, pedido.ID, item.ProdutoID, item.Nome, item.Preco, item.Quantidade)
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return historico, nil
+}
+
+// inserirItens grava todos os itens do pedido com um único COPY, em vez de um
+// INSERT por item: pedidos B2B com centenas de linhas ficam em uma ida ao banco.
+func inserirItens(ctx context.Context, q db.Querier, pedido *domain.Pedido) error {
+	colunas := []string{"pedido_id", "produto_id", "nome_produto", "preco", "quantidade"}
+	_, err := q.CopyFrom(ctx, pgx.Identifier{"pedido_itens"}, colunas,
+		pgx.CopyFromSlice(len(pedido.Itens), func(i int) ([]any, error) {
+			item := pedido.Itens[i]
+			return []any{pedido.ID, item.ProdutoID, item.Nome, item.Preco.Valor, item.Quantidade}, nil
+		}),
+	)
+	return err
+}
+
+// inserirHistorico grava os eventos pendentes do pedido dentro da transação recebida.
+func inserirHistorico(ctx context.Context, q db.Querier, pedido *domain.Pedido) error {
+	query := `INSERT INTO pedido_historico (pedido_id, status_anterior, status_novo, autor, motivo, ocorrido_em)
+			  VALUES ($1, $2, $3, $4, $5, $6)`
+	for _, evento := range pedido.Eventos() {
+		// O status anterior é NULL no evento de criação.
+		statusAnterior := pgtype.Text{String: string(evento.StatusAnterior), Valid: evento.StatusAnterior != ""}
+		_, err := q.Exec(ctx, query, pedido.ID, statusAnterior, evento.StatusNovo, evento.Autor, evento.Motivo, evento.OcorridoEm)
 		if err != nil {
 			return err
 		}
 	}
-
-	return tx.Commit()
+	return nil
 }
 
+// selectPedidos lê os pedidos com o endereço de entrega (1:1). Os itens vêm
+// depois, em uma única query para todos os pedidos lidos (ver carregarItens).
+const selectPedidos = `
+	SELECT
+		p.id, p.cliente_id, p.status, p.total, p.moeda, p.reserva_id, p.criado_em, p.atualizado_em, p.versao,
+		pe.endereco_origem_id, pe.rua, pe.cidade, pe.estado, pe.cep
+	FROM pedidos p
+	LEFT JOIN pedido_enderecos pe ON p.id = pe.pedido_id`
+
 // FindByID busca um pedido e seus itens pelo ID.
 func (r *postgresPedidoRepository) FindByID(ctx context.Context, id string) (*domain.Pedido, error) {
-	query := `SELECT
-				p.id, p.cliente_id, p.status, p.total, p.criado_em, p.atualizado_em,
-				i.id, i.produto_id, i.nome_produto, i.preco, i.quantidade
-			  FROM pedidos p
-			  LEFT JOIN pedido_itens i ON p.id = i.pedido_id
-			  WHERE p.id = $1`
-
-	rows, err := r.db.QueryContext(ctx, query, id)
+	pedidos, err := r.buscarPedidos(ctx, selectPedidos+` WHERE p.id = $1`, id)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	if len(pedidos) == 0 {
+		return nil, domain.ErrPedidoNaoEncontrado
+	}
+	return pedidos[0], nil
+}
 
-	var pedido *domain.Pedido
-	for rows.Next() {
-		var itemID sql.NullInt64
-		var item *domain.Item
-
-		if pedido == nil {
-			pedido = &domain.Pedido{}
-			err := rows.Scan(
-				&pedido.ID, &pedido.ClienteID, &pedido.Status, &pedido.Total, &pedido.CriadoEm, &pedido.AtualizadoEm,
-				&itemID, &item.ProdutoID, &item.Nome, &item.Preco, &item.Quantidade,
-			)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			err := rows.Scan(
-				&sql.RawBytes{}, &sql.RawBytes{}, &sql.RawBytes{}, &sql.RawBytes{}, &sql.RawBytes{}, &sql.RawBytes{},
-				&itemID, &item.ProdutoID, &item.Nome, &item.Preco, &item.Quantidade,
-			)
-			if err != nil {
-				return nil, err
-			}
-		}
+// List retorna uma página de pedidos, do mais novo para o mais antigo.
+// A paginação é por keyset em (criado_em, id): a página seguinte começa logo
+// depois do último pedido da anterior, sem OFFSET, e não pula nem repete
+// pedidos quando novos são criados entre uma página e outra.
+func (r *postgresPedidoRepository) List(ctx context.Context, filtro domain.FiltroPedidos) (*domain.PaginaPedidos, error) {
+	var condicoes []string
+	var args []any
+	// param registra o valor e devolve o placeholder correspondente ($1, $2, ...).
+	param := func(valor any) string {
+		args = append(args, valor)
+		return "$" + strconv.Itoa(len(args))
+	}
 
-		if itemID.Valid {
-			pedido.Itens = append(pedido.Itens, item)
-		}
+	if filtro.ClienteID != "" {
+		condicoes = append(condicoes, "p.cliente_id = "+param(filtro.ClienteID))
+	}
+	if filtro.Status != "" {
+		condicoes = append(condicoes, "p.status = "+param(filtro.Status))
+	}
+	if !filtro.CriadoDe.IsZero() {
+		condicoes = append(condicoes, "p.criado_em >= "+param(filtro.CriadoDe))
+	}
+	if !filtro.CriadoAte.IsZero() {
+		condicoes = append(condicoes, "p.criado_em < "+param(filtro.CriadoAte))
+	}
+	if filtro.TotalMin != nil {
+		condicoes = append(condicoes, "p.total >= "+param(*filtro.TotalMin))
+	}
+	if filtro.TotalMax != nil {
+		condicoes = append(condicoes, "p.total <= "+param(*filtro.TotalMax))
+	}
+	if filtro.Apos != nil {
+		condicoes = append(condicoes, "(p.criado_em, p.id) < ("+param(filtro.Apos.CriadoEm)+", "+param(filtro.Apos.ID)+")")
 	}
 
-	if err = rows.Err(); err != nil {
+	query := selectPedidos
+	if len(condicoes) > 0 {
+		query += " WHERE " + strings.Join(condicoes, " AND ")
+	}
+	// Um pedido a mais que o limite indica que existe próxima página.
+	query += " ORDER BY p.criado_em DESC, p.id DESC LIMIT " + param(filtro.Limite+1)
+
+	pedidos, err := r.buscarPedidos(ctx, query, args...)
+	if err != nil {
 		return nil, err
 	}
 
-	if pedido == nil {
-		return nil, sql.ErrNoRows
+	pagina := &domain.PaginaPedidos{Pedidos: pedidos}
+	if len(pedidos) > filtro.Limite {
+		pagina.Pedidos = pedidos[:filtro.Limite]
+		ultimo := pagina.Pedidos[len(pagina.Pedidos)-1]
+		pagina.Proximo = &domain.CursorPedido{CriadoEm: ultimo.CriadoEm, ID: ultimo.ID}
 	}
+	return pagina, nil
+}
 
-	return pedido, nil
+// FindByClienteID retorna uma página dos pedidos de um cliente, do mais novo
+// para o mais antigo. Usa o índice (cliente_id, criado_em, id), então não
+// percorre os pedidos dos demais clientes.
+func (r *postgresPedidoRepository) FindByClienteID(ctx context.Context, clienteID string, filtro domain.FiltroPedidos) (*domain.PaginaPedidos, error) {
+	filtro.ClienteID = clienteID
+	return r.List(ctx, filtro)
 }
 
-func (r *postgresPedidoRepository) ListAll(ctx context.Context) ([]*domain.Pedido, error) {
-	// 1. A QUERY: Busca todos os dados de uma vez, ordenando pelos pedidos
-	// para garantir que as linhas do mesmo pedido venham em sequência.
-	const query = `
-		SELECT
-			p.id, p.cliente_id, p.status, p.total, p.criado_em, p.atualizado_em,
-			i.id, i.produto_id, i.nome_produto, i.preco, i.quantidade
-		FROM pedidos p
-		LEFT JOIN pedido_itens i ON p.id = i.pedido_id
-		ORDER BY p.criado_em DESC, p.id` // Ordenação estável
-
-	rows, err := r.db.QueryContext(ctx, query)
+// buscarPedidos executa uma query baseada em selectPedidos e carrega os itens
+// dos pedidos encontrados, preservando a ordem da query.
+func (r *postgresPedidoRepository) buscarPedidos(ctx context.Context, query string, args ...any) ([]*domain.Pedido, error) {
+	rows, err := r.db.Querier(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	// 2. ESTRUTURAS DE APOIO:
-	// - O 'map' para evitar duplicar pedidos e agrupar itens rapidamente.
-	// - O 'slice' para manter a ordem original que veio do banco de dados.
-	pedidosMap := make(map[string]*domain.Pedido)
-	var pedidosOrdenados []*domain.Pedido
-
-	// 3. O LOOP DE PROCESSAMENTO
+	pedidos := []*domain.Pedido{}
 	for rows.Next() {
 		var p domain.Pedido
-		var item domain.Item
-		// Usamos tipos que aceitam NULL para as colunas de 'pedido_itens',
-		// pois um pedido pode não ter itens.
-		var itemID sql.NullInt64
-		var itemProdutoID sql.NullString
-		var itemNome sql.NullString
-		var itemPreco sql.NullFloat64
-		var itemQuantidade sql.NullInt32
+		var reservaID pgtype.Text
+		var entrega enderecoEntregaNulo
 
 		if err := rows.Scan(
-			&p.ID, &p.ClienteID, &p.Status, &p.Total, &p.CriadoEm, &p.AtualizadoEm,
-			&itemID, &itemProdutoID, &itemNome, &itemPreco, &itemQuantidade,
+			&p.ID, &p.ClienteID, &p.Status, &p.Total.Valor, &p.Total.Moeda, &reservaID, &p.CriadoEm, &p.AtualizadoEm, &p.Versao,
+			&entrega.origemID, &entrega.rua, &entrega.cidade, &entrega.estado, &entrega.cep,
 		); err != nil {
 			return nil, err
 		}
 
-		// 4. LÓGICA DE AGRUPAMENTO
-		// Se o pedido ainda não está no nosso map...
-		if _, existe := pedidosMap[p.ID]; !existe {
-			// ...é um novo pedido. Inicializamos sua lista de itens...
-			p.Itens = []*domain.Item{}
-			// ...adicionamos ao map para encontrá-lo nas próximas linhas...
-			pedidosMap[p.ID] = &p
-			// ...e adicionamos ao slice para preservar a ordem.
-			pedidosOrdenados = append(pedidosOrdenados, &p)
-		}
-
-		// 5. ADIÇÃO DO ITEM
-		// Se esta linha contém um item válido (itemID não é NULL)...
-		if itemID.Valid {
-			// ...criamos o struct do item...
-			item.ID = string(itemID.Int64)
-			item.ProdutoID = itemProdutoID.String
-			item.Nome = itemNome.String
-			item.Preco = itemPreco.Float64
-			item.Quantidade = int(itemQuantidade.Int32)
-
-			// ...e o adicionamos à lista de itens do pedido correto (que buscamos no map).
-			pedidosMap[p.ID].Itens = append(pedidosMap[p.ID].Itens, &item)
-		}
+		p.ReservaID = reservaID.String
+		p.Entrega = entrega.paraDominio()
+		p.Itens = []*domain.Item{}
+		pedidos = append(pedidos, &p)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	// 6. RETORNO
-	return pedidosOrdenados, nil
+	if err := r.carregarItens(ctx, pedidos); err != nil {
+		return nil, err
+	}
+	return pedidos, nil
+}
+
+// carregarItens busca os itens de todos os pedidos em uma única query,
+// em vez de uma por pedido.
+func (r *postgresPedidoRepository) carregarItens(ctx context.Context, pedidos []*domain.Pedido) error {
+	if len(pedidos) == 0 {
+		return nil
+	}
+
+	porID := make(map[string]*domain.Pedido, len(pedidos))
+	ids := make([]string, 0, len(pedidos))
+	for _, p := range pedidos {
+		porID[p.ID] = p
+		ids = append(ids, p.ID)
+	}
+
+	const query = `
+		SELECT pedido_id, id, produto_id, nome_produto, preco, quantidade
+		FROM pedido_itens
+		WHERE pedido_id = ANY($1)
+		ORDER BY pedido_id, id`
+
+	rows, err := r.db.Querier(ctx).Query(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pedidoID string
+		var itemID, preco int64
+		var item domain.Item
+		if err := rows.Scan(&pedidoID, &itemID, &item.ProdutoID, &item.Nome, &preco, &item.Quantidade); err != nil {
+			return err
+		}
+
+		pedido := porID[pedidoID]
+		item.ID = strconv.FormatInt(itemID, 10)
+		// A moeda dos itens é a do pedido.
+		item.Preco = money.New(preco, pedido.Total.Moeda)
+		pedido.Itens = append(pedido.Itens, &item)
+	}
+
+	return rows.Err()
+}
+
+// enderecoEntregaNulo recebe as colunas de pedido_enderecos, que vêm NULL
+// no LEFT JOIN para pedidos anteriores ao endereço de entrega.
+type enderecoEntregaNulo struct {
+	origemID                 pgtype.Int8
+	rua, cidade, estado, cep pgtype.Text
+}
+
+func (e enderecoEntregaNulo) paraDominio() domain.EnderecoEntrega {
+	return domain.EnderecoEntrega{
+		EnderecoOrigemID: e.origemID.Int64,
+		Rua:              e.rua.String,
+		Cidade:           e.cidade.String,
+		Estado:           e.estado.String,
+		CEP:              e.cep.String,
+	}
 }
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"ecommerce/pedidos/internal/domain"
+	"ecommerce/pedidos/migrations"
+	"ecommerce/pkg/db"
+	"ecommerce/pkg/money"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// errDesfazer encerra a transação de cada iteração do benchmark sem gravar nada.
+var errDesfazer = errors.New("desfazer")
+
+// poolDeBenchmark conecta no banco de DATABASE_URL e aplica as migrações.
+// Sem DATABASE_URL, o benchmark é pulado: use um banco descartável.
+func poolDeBenchmark(b *testing.B) *db.Pool {
+	b.Helper()
+	if _, ok := os.LookupEnv("DATABASE_URL"); !ok {
+		b.Skip("DATABASE_URL não definida: os benchmarks precisam de um Postgres")
+	}
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx, db.ComApplicationName("pedidos-benchmark"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(pool.Close)
+
+	migrador, err := db.NewMigrador(pool.SQLDB(), migrations.FS, "pedidos")
+	if err != nil {
+		b.Fatal(err)
+	}
+	if _, err := migrador.Up(ctx); err != nil {
+		b.Fatal(err)
+	}
+	return pool
+}
+
+// inserirItensPorLinha é a gravação anterior ao COPY, um INSERT por item,
+// mantida aqui como base de comparação.
+func inserirItensPorLinha(ctx context.Context, q db.Querier, pedido *domain.Pedido) error {
+	itemQuery := `INSERT INTO pedido_itens (pedido_id, produto_id, nome_produto, preco, quantidade)
+				  VALUES ($1, $2, $3, $4, $5)`
+	for _, item := range pedido.Itens {
+		if _, err := q.Exec(ctx, itemQuery, pedido.ID, item.ProdutoID, item.Nome, item.Preco.Valor, item.Quantidade); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BenchmarkInserirItens compara o INSERT por item com o COPY de inserirItens
+// em pedidos de tamanhos diferentes:
+//
+//	DATABASE_URL=postgres://... go test -run '^$' -bench InserirItens ./internal/infra/repository/
+func BenchmarkInserirItens(b *testing.B) {
+	pool := poolDeBenchmark(b)
+	estrategias := []struct {
+		nome    string
+		inserir func(context.Context, db.Querier, *domain.Pedido) error
+	}{
+		{"por_linha", inserirItensPorLinha},
+		{"copy", inserirItens},
+	}
+
+	for _, quantidade := range []int{1, 10, 100, 500} {
+		pedido := &domain.Pedido{ClienteID: uuid.NewString()}
+		for i := range quantidade {
+			pedido.Itens = append(pedido.Itens, &domain.Item{
+				ProdutoID:  "produto-" + strconv.Itoa(i),
+				Nome:       "Produto " + strconv.Itoa(i),
+				Preco:      money.New(1990, "BRL"),
+				Quantidade: 1,
+			})
+		}
+
+		for _, estrategia := range estrategias {
+			b.Run(estrategia.nome+"/itens="+strconv.Itoa(quantidade), func(b *testing.B) {
+				for b.Loop() {
+					pedido.ID = uuid.NewString()
+					err := pool.WithTx(context.Background(), func(ctx context.Context) error {
+						q := pool.Querier(ctx)
+						b.StopTimer()
+						_, err := q.Exec(ctx,
+							`INSERT INTO pedidos (id, cliente_id, status, total, moeda, criado_em, atualizado_em)
+							 VALUES ($1, $2, 'aguardando_pagamento', 0, 'BRL', now(), now())`,
+							pedido.ID, pedido.ClienteID,
+						)
+						b.StartTimer()
+						if err != nil {
+							return err
+						}
+						if err := estrategia.inserir(ctx, q, pedido); err != nil {
+							return err
+						}
+						return errDesfazer
+					})
+					if !errors.Is(err, errDesfazer) {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
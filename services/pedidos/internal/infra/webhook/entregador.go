@@ -0,0 +1,277 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"ecommerce/pedidos/internal/domain"
+	"ecommerce/pkg/common/assinatura"
+	"ecommerce/pkg/db"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Cabeçalhos enviados em cada entrega. O destino valida a origem recalculando
+// HMAC-SHA256(segredo, timestamp + "." + corpo) e comparando com a assinatura,
+// e descarta repetições pelo ID do evento que vem no corpo.
+const (
+	CabecalhoAssinatura = "X-Webhook-Signature" // "sha256=" + HMAC em hexadecimal.
+	CabecalhoTimestamp  = "X-Webhook-Timestamp" // Segundos Unix do envio.
+	CabecalhoEvento     = "X-Webhook-Event"     // Tipo do evento, ex.: pedido.pago.
+	CabecalhoEntrega    = "X-Webhook-Delivery"  // ID da entrega.
+)
+
+// Política de tentativas: a espera dobra a cada falha, de esperaInicial até
+// esperaMaxima; após maxTentativas a entrega fica como falhou (cerca de 4
+// horas de tentativas com os valores abaixo).
+const (
+	maxTentativas = 10
+	esperaInicial = 30 * time.Second
+	esperaMaxima  = time.Hour
+)
+
+// Limites do Entregador.
+const (
+	timeoutEntrega = 10 * time.Second
+	loteEntregas   = 20
+	prazoReserva   = time.Minute // Tempo para concluir uma entrega antes de ela voltar à fila.
+)
+
+// errDestinoInterno é o erro de uma entrega cujo destino resolveu para um IP
+// interno: o webhook não pode ser usado para alcançar a rede do serviço.
+var errDestinoInterno = errors.New("destino resolvido para um endereço interno, entrega bloqueada")
+
+// Entregador envia as entregas pendentes aos destinos das assinaturas.
+type Entregador struct {
+	db      *db.Pool
+	cliente *http.Client
+}
+
+// NewEntregador cria o Entregador com um cliente HTTP com timeout, que só
+// conecta em IPs públicos (domain.IPPublico) e não segue redirecionamentos.
+func NewEntregador(pool *db.Pool) *Entregador {
+	return &Entregador{
+		db:      pool,
+		cliente: novoClienteHTTP(domain.IPPublicoDoEndereco),
+	}
+}
+
+// novoClienteHTTP cria o cliente das entregas. permitido confere cada conexão
+// já com o IP resolvido, o que também cobre um nome público que passe a
+// apontar para a rede interna depois do cadastro. Sem proxy, a conexão vai
+// direto ao IP conferido. Um redirecionamento é devolvido como resposta (3xx,
+// falha da entrega), nunca seguido.
+func novoClienteHTTP(permitido func(endereco string) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeoutEntrega,
+		Control: func(_, endereco string, _ syscall.RawConn) error {
+			if !permitido(endereco) {
+				return errDestinoInterno
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeoutEntrega,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeoutEntrega,
+			MaxIdleConnsPerHost: 2,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// IniciarEntregas envia as entregas pendentes periodicamente até o contexto
+// ser cancelado.
+func (e *Entregador) IniciarEntregas(ctx context.Context, intervalo time.Duration) {
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := e.EntregarPendentes(ctx); err != nil {
+				log.Printf("Erro ao entregar webhooks: %v", err)
+			}
+		}
+	}
+}
+
+// entregaReservada é uma entrega pronta para envio, com o destino e o segredo.
+type entregaReservada struct {
+	id, tipo, url, segredo string
+	payload                []byte
+	tentativas             int
+}
+
+// EntregarPendentes envia um lote de entregas prontas e retorna quantas foram tentadas.
+//
+// As entregas são reservadas adiando proxima_tentativa_em por prazoReserva, e
+// as requisições são feitas fora de transação: uma instância que caia no meio
+// do envio não trava a fila, e a entrega volta a ser tentada depois do prazo.
+func (e *Entregador) EntregarPendentes(ctx context.Context) (int, error) {
+	entregas, err := e.reservar(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, entrega := range entregas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := e.entregar(ctx, entrega); err != nil {
+				log.Printf("Erro ao registrar a entrega de webhook %s: %v", entrega.id, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return len(entregas), nil
+}
+
+func (e *Entregador) reservar(ctx context.Context) ([]entregaReservada, error) {
+	const query = `
+		UPDATE webhook_entregas en SET proxima_tentativa_em = now() + $2::interval
+		FROM webhook_assinaturas a
+		WHERE a.id = en.assinatura_id AND en.id IN (
+			SELECT id FROM webhook_entregas
+			WHERE status = $3 AND proxima_tentativa_em <= now()
+			ORDER BY proxima_tentativa_em
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING en.id, en.tipo, en.payload, en.tentativas, a.url, a.segredo`
+
+	rows, err := e.db.Querier(ctx).Query(ctx, query, loteEntregas, prazoReserva, domain.EntregaPendente)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entregas []entregaReservada
+	for rows.Next() {
+		var r entregaReservada
+		if err := rows.Scan(&r.id, &r.tipo, &r.payload, &r.tentativas, &r.url, &r.segredo); err != nil {
+			return nil, err
+		}
+		entregas = append(entregas, r)
+	}
+	return entregas, rows.Err()
+}
+
+// entregar faz a requisição e registra o resultado. O erro retornado é só o
+// de gravação; falhas do destino viram uma nova tentativa.
+func (e *Entregador) entregar(ctx context.Context, entrega entregaReservada) error {
+	inicio := time.Now()
+	statusHTTP, errEnvio := e.enviar(ctx, entrega)
+	duracao := time.Since(inicio)
+
+	return e.db.WithTx(ctx, func(ctx context.Context) error {
+		q := e.db.Querier(ctx)
+
+		var erro pgtype.Text
+		if errEnvio != nil {
+			erro = pgtype.Text{String: errEnvio.Error(), Valid: true}
+		}
+		_, err := q.Exec(ctx,
+			`INSERT INTO webhook_tentativas (entrega_id, status_http, erro, duracao_ms, ocorrido_em)
+			 VALUES ($1, $2, $3, $4, $5)`,
+			entrega.id, pgtype.Int4{Int32: int32(statusHTTP), Valid: statusHTTP != 0}, erro, duracao.Milliseconds(), inicio,
+		)
+		if err != nil {
+			return err
+		}
+
+		if errEnvio == nil {
+			_, err = q.Exec(ctx,
+				`UPDATE webhook_entregas SET status = $2, entregue_em = now(), proxima_tentativa_em = NULL WHERE id = $1`,
+				entrega.id, domain.EntregaEntregue,
+			)
+			return err
+		}
+
+		tentativas := entrega.tentativas + 1
+		status, proxima := aposFalha(tentativas, time.Now())
+		if status == domain.EntregaFalhou {
+			log.Printf("Entrega de webhook %s (%s) falhou após %d tentativas: %v", entrega.id, entrega.tipo, tentativas, errEnvio)
+		}
+		_, err = q.Exec(ctx,
+			`UPDATE webhook_entregas SET status = $2, tentativas = $3, proxima_tentativa_em = $4 WHERE id = $1`,
+			entrega.id, status, tentativas, proxima,
+		)
+		return err
+	})
+}
+
+// enviar faz o POST assinado. Respostas fora de 2xx são erro. Do destino só
+// o status é guardado: o corpo da resposta é descartado.
+func (e *Entregador) enviar(ctx context.Context, entrega entregaReservada) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, entrega.url, bytes.NewReader(entrega.payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(CabecalhoEvento, entrega.tipo)
+	req.Header.Set(CabecalhoEntrega, entrega.id)
+	req.Header.Set(CabecalhoTimestamp, timestamp)
+	req.Header.Set(CabecalhoAssinatura, "sha256="+Assinar(entrega.segredo, timestamp, entrega.payload))
+
+	resp, err := e.cliente.Do(req)
+	if errors.Is(err, errDestinoInterno) {
+		return 0, errDestinoInterno // Sem o IP interno resolvido, que iria para o histórico.
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Lê o corpo para que a conexão possa ser reaproveitada.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("destino respondeu %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Assinar calcula a assinatura HMAC-SHA256, em hexadecimal, de uma entrega.
+// Os parceiros fazem a mesma conta para validar o cabeçalho X-Webhook-Signature.
+func Assinar(segredo, timestamp string, corpo []byte) string {
+	return assinatura.Calcular([]byte(segredo), timestamp, corpo)
+}
+
+// aposFalha decide a situação da entrega depois da falha de número
+// tentativas: pendente, com a próxima tentativa marcada, ou falhou (sem
+// próxima tentativa) quando as tentativas se esgotam.
+func aposFalha(tentativas int, agora time.Time) (domain.StatusEntrega, *time.Time) {
+	if tentativas >= maxTentativas {
+		return domain.EntregaFalhou, nil
+	}
+	proxima := agora.Add(espera(tentativas))
+	return domain.EntregaPendente, &proxima
+}
+
+// espera é o intervalo até a próxima tentativa após a falha de número tentativas.
+func espera(tentativas int) time.Duration {
+	d := esperaInicial
+	for i := 1; i < tentativas && d < esperaMaxima; i++ {
+		d *= 2
+	}
+	return min(d, esperaMaxima)
+}
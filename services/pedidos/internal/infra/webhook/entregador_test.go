@@ -0,0 +1,193 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"ecommerce/pedidos/internal/domain"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// entregadorDeTeste envia para os receptores httptest, que escutam em
+// 127.0.0.1: só nos testes o loopback é permitido.
+func entregadorDeTeste() *Entregador {
+	return &Entregador{cliente: novoClienteHTTP(func(string) bool { return true })}
+}
+
+func entregaDeTeste(url string) entregaReservada {
+	return entregaReservada{
+		id:      "entrega-1",
+		tipo:    domain.EventoPedidoPago,
+		url:     url,
+		segredo: "segredo-de-teste-123",
+		payload: []byte(`{"id":"evento-1","tipo":"pedido.pago"}`),
+	}
+}
+
+func TestEnviarAssinaAEntrega(t *testing.T) {
+	entrega := entregaDeTeste("")
+	receptor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		corpo, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get(CabecalhoTimestamp)
+		esperada := "sha256=" + Assinar(entrega.segredo, timestamp, corpo)
+
+		if !hmac.Equal([]byte(r.Header.Get(CabecalhoAssinatura)), []byte(esperada)) {
+			t.Errorf("%s = %q, esperado %q", CabecalhoAssinatura, r.Header.Get(CabecalhoAssinatura), esperada)
+		}
+		if got := r.Header.Get(CabecalhoEvento); got != entrega.tipo {
+			t.Errorf("%s = %q, esperado %q", CabecalhoEvento, got, entrega.tipo)
+		}
+		if got := r.Header.Get(CabecalhoEntrega); got != entrega.id {
+			t.Errorf("%s = %q, esperado %q", CabecalhoEntrega, got, entrega.id)
+		}
+		if string(corpo) != string(entrega.payload) {
+			t.Errorf("corpo = %s, esperado %s", corpo, entrega.payload)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receptor.Close()
+	entrega.url = receptor.URL
+
+	status, err := entregadorDeTeste().enviar(context.Background(), entrega)
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("enviar = %d, %v; esperado 204 sem erro", status, err)
+	}
+}
+
+func TestAssinarComSegredoErradoNaoConfere(t *testing.T) {
+	corpo := []byte(`{"id":"evento-1"}`)
+	if Assinar("segredo-de-teste-123", "1700000000", corpo) == Assinar("outro-segredo-de-teste", "1700000000", corpo) {
+		t.Error("segredos diferentes geraram a mesma assinatura")
+	}
+	if Assinar("segredo-de-teste-123", "1700000000", corpo) == Assinar("segredo-de-teste-123", "1700000001", corpo) {
+		t.Error("timestamps diferentes geraram a mesma assinatura")
+	}
+}
+
+func TestEnviarGuardaSoOStatusDaResposta(t *testing.T) {
+	receptor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		io.WriteString(w, `{"token":"dado-interno"}`)
+	}))
+	defer receptor.Close()
+
+	status, err := entregadorDeTeste().enviar(context.Background(), entregaDeTeste(receptor.URL))
+	if status != http.StatusInternalServerError || err == nil {
+		t.Fatalf("enviar = %d, %v; esperado 500 com erro", status, err)
+	}
+	if strings.Contains(err.Error(), "dado-interno") {
+		t.Errorf("o erro guardado contém o corpo da resposta: %v", err)
+	}
+}
+
+func TestEnviarNaoSegueRedirecionamento(t *testing.T) {
+	var alcancado atomic.Bool
+	interno := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		alcancado.Store(true)
+	}))
+	defer interno.Close()
+	receptor := httptest.NewServer(http.RedirectHandler(interno.URL, http.StatusFound))
+	defer receptor.Close()
+
+	status, err := entregadorDeTeste().enviar(context.Background(), entregaDeTeste(receptor.URL))
+	if status != http.StatusFound || err == nil {
+		t.Errorf("enviar = %d, %v; esperado 302 com erro", status, err)
+	}
+	if alcancado.Load() {
+		t.Error("o redirecionamento foi seguido")
+	}
+}
+
+func TestEnviarRecusaDestinoInterno(t *testing.T) {
+	var alcancado atomic.Bool
+	receptor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		alcancado.Store(true)
+	}))
+	defer receptor.Close()
+
+	// O cliente de produção: 127.0.0.1 é conferido depois da resolução e recusado.
+	e := &Entregador{cliente: novoClienteHTTP(domain.IPPublicoDoEndereco)}
+	status, err := e.enviar(context.Background(), entregaDeTeste(receptor.URL))
+	if !errors.Is(err, errDestinoInterno) || status != 0 {
+		t.Errorf("enviar = %d, %v; esperado errDestinoInterno", status, err)
+	}
+	if alcancado.Load() {
+		t.Error("a requisição chegou ao destino interno")
+	}
+}
+
+func TestEspera(t *testing.T) {
+	casos := []struct {
+		tentativas int
+		espera     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{6, 16 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{9, time.Hour},
+	}
+	for _, c := range casos {
+		if got := espera(c.tentativas); got != c.espera {
+			t.Errorf("espera(%d) = %s, esperado %s", c.tentativas, got, c.espera)
+		}
+	}
+}
+
+// TestTentativasAteODeadLetter repete as entregas como o Entregador faz,
+// com aposFalha decidindo o que acontece depois de cada falha.
+func TestTentativasAteODeadLetter(t *testing.T) {
+	casos := []struct {
+		nome       string
+		falhas     int // Falhas do receptor antes de ele responder 2xx.
+		tentativas int
+		status     domain.StatusEntrega
+	}{
+		{"entregue na primeira", 0, 1, domain.EntregaEntregue},
+		{"entregue depois de falhas", 3, 4, domain.EntregaEntregue},
+		{"falhou depois de esgotar as tentativas", 1000, maxTentativas, domain.EntregaFalhou},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			var recebidas atomic.Int32
+			receptor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if int(recebidas.Add(1)) <= c.falhas {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer receptor.Close()
+
+			e := entregadorDeTeste()
+			entrega := entregaDeTeste(receptor.URL)
+			agora := time.Now()
+			status := domain.EntregaPendente
+			for status == domain.EntregaPendente {
+				if _, err := e.enviar(context.Background(), entrega); err == nil {
+					status = domain.EntregaEntregue
+					break
+				}
+				entrega.tentativas++
+				var proxima *time.Time
+				status, proxima = aposFalha(entrega.tentativas, agora)
+				if status == domain.EntregaPendente && !proxima.Equal(agora.Add(espera(entrega.tentativas))) {
+					t.Fatalf("tentativa %d: próxima em %s, esperado %s", entrega.tentativas, proxima, agora.Add(espera(entrega.tentativas)))
+				}
+				if status == domain.EntregaFalhou && proxima != nil {
+					t.Fatal("entrega que falhou não deveria ter próxima tentativa")
+				}
+			}
+
+			if status != c.status || int(recebidas.Load()) != c.tentativas {
+				t.Errorf("status = %s após %d requisições, esperado %s após %d", status, recebidas.Load(), c.status, c.tentativas)
+			}
+		})
+	}
+}
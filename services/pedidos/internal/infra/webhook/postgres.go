@@ -0,0 +1,246 @@
+package webhook
+
+import (
+	"context"
+	"ecommerce/pedidos/internal/domain"
+	"ecommerce/pkg/db"
+	"ecommerce/pkg/outbox"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PostgresWebhooks guarda assinaturas e entregas nas tabelas webhook_*.
+// Também é um outbox.Publisher: cada evento publicado pelo relay vira uma
+// entrega pendente para cada assinatura interessada, que o Entregador envia.
+type PostgresWebhooks struct {
+	db *db.Pool
+}
+
+// NewPostgresWebhooks cria o repositório de webhooks.
+func NewPostgresWebhooks(pool *db.Pool) *PostgresWebhooks {
+	return &PostgresWebhooks{db: pool}
+}
+
+// Publicar implementa outbox.Publisher. As entregas são gravadas antes de o
+// relay marcar o evento como publicado; se a marcação falhar, o evento é
+// publicado de novo, e republicar o mesmo evento não duplica entregas.
+func (w *PostgresWebhooks) Publicar(ctx context.Context, evento outbox.Evento) error {
+	payload, err := json.Marshal(evento)
+	if err != nil {
+		return err
+	}
+
+	assinaturas, err := w.ListAssinaturas(ctx)
+	if err != nil {
+		return err
+	}
+
+	agora := time.Now()
+	for _, a := range assinaturas {
+		if !a.Recebe(evento.Tipo) {
+			continue
+		}
+		_, err := w.db.Querier(ctx).Exec(ctx,
+			`INSERT INTO webhook_entregas (id, assinatura_id, evento_id, tipo, payload, status, proxima_tentativa_em, criado_em)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+			 ON CONFLICT (assinatura_id, evento_id) DO NOTHING`,
+			uuid.NewString(), a.ID, evento.ID, evento.Tipo, payload, domain.EntregaPendente, agora,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveAssinatura grava uma nova assinatura, gerando seu ID.
+func (w *PostgresWebhooks) SaveAssinatura(ctx context.Context, a *domain.Assinatura) error {
+	a.ID = uuid.NewString()
+	_, err := w.db.Querier(ctx).Exec(ctx,
+		`INSERT INTO webhook_assinaturas (id, url, eventos, segredo, ativa, criado_em, atualizado_em)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		a.ID, a.URL, a.Eventos, a.Segredo, a.Ativa, a.CriadoEm, a.AtualizadoEm,
+	)
+	return err
+}
+
+const selectAssinaturas = `SELECT id, url, eventos, segredo, ativa, criado_em, atualizado_em FROM webhook_assinaturas`
+
+// FindAssinatura busca uma assinatura pelo ID.
+func (w *PostgresWebhooks) FindAssinatura(ctx context.Context, id string) (*domain.Assinatura, error) {
+	if uuid.Validate(id) != nil {
+		return nil, domain.ErrAssinaturaNaoEncontrada
+	}
+	assinaturas, err := w.buscarAssinaturas(ctx, selectAssinaturas+` WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(assinaturas) == 0 {
+		return nil, domain.ErrAssinaturaNaoEncontrada
+	}
+	return assinaturas[0], nil
+}
+
+// ListAssinaturas retorna todas as assinaturas, das mais antigas para as mais novas.
+func (w *PostgresWebhooks) ListAssinaturas(ctx context.Context) ([]*domain.Assinatura, error) {
+	return w.buscarAssinaturas(ctx, selectAssinaturas+` ORDER BY criado_em, id`)
+}
+
+func (w *PostgresWebhooks) buscarAssinaturas(ctx context.Context, query string, args ...any) ([]*domain.Assinatura, error) {
+	rows, err := w.db.Querier(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assinaturas := []*domain.Assinatura{}
+	for rows.Next() {
+		var a domain.Assinatura
+		if err := rows.Scan(&a.ID, &a.URL, &a.Eventos, &a.Segredo, &a.Ativa, &a.CriadoEm, &a.AtualizadoEm); err != nil {
+			return nil, err
+		}
+		assinaturas = append(assinaturas, &a)
+	}
+	return assinaturas, rows.Err()
+}
+
+// UpdateAssinatura persiste destino, eventos, situação e segredo da assinatura.
+func (w *PostgresWebhooks) UpdateAssinatura(ctx context.Context, a *domain.Assinatura) error {
+	tag, err := w.db.Querier(ctx).Exec(ctx,
+		`UPDATE webhook_assinaturas SET url = $2, eventos = $3, segredo = $4, ativa = $5, atualizado_em = $6
+		 WHERE id = $1`,
+		a.ID, a.URL, a.Eventos, a.Segredo, a.Ativa, a.AtualizadoEm,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrAssinaturaNaoEncontrada
+	}
+	return nil
+}
+
+// DeleteAssinatura remove a assinatura com suas entregas e tentativas.
+func (w *PostgresWebhooks) DeleteAssinatura(ctx context.Context, id string) error {
+	if uuid.Validate(id) != nil {
+		return domain.ErrAssinaturaNaoEncontrada
+	}
+	tag, err := w.db.Querier(ctx).Exec(ctx, `DELETE FROM webhook_assinaturas WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrAssinaturaNaoEncontrada
+	}
+	return nil
+}
+
+const selectEntregas = `
+	SELECT id, assinatura_id, evento_id, tipo, payload, status, tentativas, proxima_tentativa_em, entregue_em, criado_em
+	FROM webhook_entregas`
+
+// ListEntregas implementa domain.WebhookRepository.
+func (w *PostgresWebhooks) ListEntregas(ctx context.Context, assinaturaID string, limite int) ([]*domain.Entrega, error) {
+	if _, err := w.FindAssinatura(ctx, assinaturaID); err != nil {
+		return nil, err
+	}
+
+	rows, err := w.db.Querier(ctx).Query(ctx,
+		selectEntregas+` WHERE assinatura_id = $1 ORDER BY criado_em DESC, id DESC LIMIT $2`,
+		assinaturaID, limite,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entregas := []*domain.Entrega{}
+	for rows.Next() {
+		e, err := lerEntrega(rows)
+		if err != nil {
+			return nil, err
+		}
+		entregas = append(entregas, e)
+	}
+	return entregas, rows.Err()
+}
+
+// FindEntrega implementa domain.WebhookRepository.
+func (w *PostgresWebhooks) FindEntrega(ctx context.Context, assinaturaID, entregaID string) (*domain.Entrega, error) {
+	if uuid.Validate(assinaturaID) != nil || uuid.Validate(entregaID) != nil {
+		return nil, domain.ErrEntregaNaoEncontrada
+	}
+
+	q := w.db.Querier(ctx)
+	entrega, err := lerEntrega(q.QueryRow(ctx, selectEntregas+` WHERE id = $1 AND assinatura_id = $2`, entregaID, assinaturaID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrEntregaNaoEncontrada
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(ctx,
+		`SELECT status_http, erro, duracao_ms, ocorrido_em FROM webhook_tentativas WHERE entrega_id = $1 ORDER BY id`,
+		entregaID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entrega.Historico = []domain.TentativaEntrega{}
+	for rows.Next() {
+		var t domain.TentativaEntrega
+		var statusHTTP pgtype.Int4
+		var erro pgtype.Text
+		if err := rows.Scan(&statusHTTP, &erro, &t.DuracaoMs, &t.OcorridoEm); err != nil {
+			return nil, err
+		}
+		t.StatusHTTP = int(statusHTTP.Int32)
+		t.Erro = erro.String
+		entrega.Historico = append(entrega.Historico, t)
+	}
+	return entrega, rows.Err()
+}
+
+// Reenviar implementa domain.WebhookRepository.
+func (w *PostgresWebhooks) Reenviar(ctx context.Context, assinaturaID, entregaID string) error {
+	if uuid.Validate(assinaturaID) != nil || uuid.Validate(entregaID) != nil {
+		return domain.ErrEntregaNaoEncontrada
+	}
+	tag, err := w.db.Querier(ctx).Exec(ctx,
+		`UPDATE webhook_entregas SET status = $3, tentativas = 0, proxima_tentativa_em = now()
+		 WHERE id = $1 AND assinatura_id = $2`,
+		entregaID, assinaturaID, domain.EntregaPendente,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrEntregaNaoEncontrada
+	}
+	return nil
+}
+
+// lerEntrega lê uma linha no formato de selectEntregas.
+func lerEntrega(row pgx.Row) (*domain.Entrega, error) {
+	var e domain.Entrega
+	var proxima, entregue pgtype.Timestamptz
+	if err := row.Scan(&e.ID, &e.AssinaturaID, &e.EventoID, &e.Tipo, &e.Payload, &e.Status, &e.Tentativas,
+		&proxima, &entregue, &e.CriadoEm); err != nil {
+		return nil, err
+	}
+	if proxima.Valid {
+		e.ProximaTentativaEm = &proxima.Time
+	}
+	if entregue.Valid {
+		e.EntregueEm = &entregue.Time
+	}
+	return &e, nil
+}
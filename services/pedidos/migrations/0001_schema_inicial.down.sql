@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS pedido_itens;
+DROP TABLE IF EXISTS pedidos;
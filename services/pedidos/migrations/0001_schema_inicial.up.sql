@@ -0,0 +1,21 @@
+-- Tabelas que existiam antes do controle de migrações. IF NOT EXISTS permite
+-- registrar esta versão em bancos criados manualmente sem recriá-las.
+CREATE TABLE IF NOT EXISTS pedidos (
+    id            UUID PRIMARY KEY,
+    cliente_id    TEXT NOT NULL,
+    status        TEXT NOT NULL,
+    total         NUMERIC(12, 2) NOT NULL,
+    criado_em     TIMESTAMPTZ NOT NULL,
+    atualizado_em TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS pedido_itens (
+    id           BIGSERIAL PRIMARY KEY,
+    pedido_id    UUID NOT NULL REFERENCES pedidos (id) ON DELETE CASCADE,
+    produto_id   TEXT NOT NULL,
+    nome_produto TEXT NOT NULL,
+    preco        NUMERIC(12, 2) NOT NULL,
+    quantidade   INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pedido_itens_pedido_id ON pedido_itens (pedido_id);
@@ -0,0 +1,11 @@
+CREATE TABLE pedido_historico (
+    id              BIGSERIAL PRIMARY KEY,
+    pedido_id       UUID NOT NULL REFERENCES pedidos (id) ON DELETE CASCADE,
+    status_anterior TEXT,
+    status_novo     TEXT NOT NULL,
+    autor           TEXT NOT NULL,
+    motivo          TEXT NOT NULL DEFAULT '',
+    ocorrido_em     TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_pedido_historico_pedido_id ON pedido_historico (pedido_id, ocorrido_em);
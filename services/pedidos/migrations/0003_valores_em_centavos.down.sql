@@ -0,0 +1,6 @@
+ALTER TABLE pedido_itens
+    ALTER COLUMN preco TYPE NUMERIC(12, 2) USING preco / 100.0;
+
+ALTER TABLE pedidos
+    DROP COLUMN moeda,
+    ALTER COLUMN total TYPE NUMERIC(12, 2) USING total / 100.0;
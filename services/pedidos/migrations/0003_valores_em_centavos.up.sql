@@ -0,0 +1,8 @@
+-- Valores monetários passam a ser inteiros em unidades menores (centavos).
+-- A moeda fica no pedido e vale para todos os seus itens.
+ALTER TABLE pedidos
+    ALTER COLUMN total TYPE BIGINT USING round(total * 100)::BIGINT,
+    ADD COLUMN moeda CHAR(3) NOT NULL DEFAULT 'BRL';
+
+ALTER TABLE pedido_itens
+    ALTER COLUMN preco TYPE BIGINT USING round(preco * 100)::BIGINT;
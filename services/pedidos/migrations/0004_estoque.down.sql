@@ -0,0 +1,4 @@
+ALTER TABLE pedidos DROP COLUMN IF EXISTS reserva_id;
+DROP TABLE IF EXISTS estoque_reserva_itens;
+DROP TABLE IF EXISTS estoque_reservas;
+DROP TABLE IF EXISTS estoque;
@@ -0,0 +1,25 @@
+CREATE TABLE estoque (
+    produto_id    TEXT PRIMARY KEY,
+    disponivel    INTEGER NOT NULL CHECK (disponivel >= 0),
+    atualizado_em TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE estoque_reservas (
+    id        UUID PRIMARY KEY,
+    status    TEXT NOT NULL,
+    criado_em TIMESTAMPTZ NOT NULL,
+    expira_em TIMESTAMPTZ NOT NULL
+);
+
+-- Usado pela liberação periódica das reservas pendentes expiradas.
+CREATE INDEX idx_estoque_reservas_pendentes ON estoque_reservas (expira_em) WHERE status = 'pendente';
+
+CREATE TABLE estoque_reserva_itens (
+    reserva_id UUID NOT NULL REFERENCES estoque_reservas (id) ON DELETE CASCADE,
+    produto_id TEXT NOT NULL,
+    quantidade INTEGER NOT NULL CHECK (quantidade > 0),
+    PRIMARY KEY (reserva_id, produto_id)
+);
+
+-- Sem FK: o estoque pode ser movido para um serviço separado.
+ALTER TABLE pedidos ADD COLUMN reserva_id TEXT;
@@ -0,0 +1,10 @@
+-- Cópia do endereço de entrega no momento do pedido. endereco_origem_id aponta
+-- para o endereço no serviço de clientes (outro banco), por isso não é FK.
+CREATE TABLE pedido_enderecos (
+    pedido_id          UUID PRIMARY KEY REFERENCES pedidos (id) ON DELETE CASCADE,
+    endereco_origem_id BIGINT,
+    rua                TEXT NOT NULL,
+    cidade             TEXT NOT NULL,
+    estado             TEXT NOT NULL,
+    cep                TEXT NOT NULL
+);
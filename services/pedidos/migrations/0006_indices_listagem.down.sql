@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pedidos_status_criado_em_id;
+DROP INDEX IF EXISTS idx_pedidos_cliente_criado_em_id;
+DROP INDEX IF EXISTS idx_pedidos_criado_em_id;
@@ -0,0 +1,5 @@
+-- Índices para a listagem paginada por (criado_em, id), do mais novo para o
+-- mais antigo, com ou sem filtro por cliente ou status.
+CREATE INDEX idx_pedidos_criado_em_id ON pedidos (criado_em DESC, id DESC);
+CREATE INDEX idx_pedidos_cliente_criado_em_id ON pedidos (cliente_id, criado_em DESC, id DESC);
+CREATE INDEX idx_pedidos_status_criado_em_id ON pedidos (status, criado_em DESC, id DESC);
@@ -0,0 +1,2 @@
+-- Controle de concorrência otimista: incrementada a cada alteração do pedido.
+ALTER TABLE pedidos ADD COLUMN versao INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS webhook_tentativas;
+DROP TABLE IF EXISTS webhook_entregas;
+DROP TABLE IF EXISTS webhook_assinaturas;
@@ -0,0 +1,41 @@
+-- Webhooks: assinaturas de parceiros, uma entrega por evento e assinatura, e o
+-- registro de cada tentativa de entrega.
+CREATE TABLE webhook_assinaturas (
+    id            UUID PRIMARY KEY,
+    url           TEXT NOT NULL,
+    eventos       TEXT[] NOT NULL,
+    segredo       TEXT NOT NULL,
+    ativa         BOOLEAN NOT NULL DEFAULT true,
+    criado_em     TIMESTAMPTZ NOT NULL,
+    atualizado_em TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE webhook_entregas (
+    id                   UUID PRIMARY KEY,
+    assinatura_id        UUID NOT NULL REFERENCES webhook_assinaturas (id) ON DELETE CASCADE,
+    evento_id            UUID NOT NULL,
+    tipo                 TEXT NOT NULL,
+    payload              JSONB NOT NULL,
+    status               TEXT NOT NULL,
+    tentativas           INTEGER NOT NULL DEFAULT 0,
+    proxima_tentativa_em TIMESTAMPTZ,
+    entregue_em          TIMESTAMPTZ,
+    criado_em            TIMESTAMPTZ NOT NULL,
+    -- O relay da outbox pode publicar o mesmo evento mais de uma vez.
+    UNIQUE (assinatura_id, evento_id)
+);
+
+CREATE INDEX idx_webhook_entregas_pendentes ON webhook_entregas (proxima_tentativa_em)
+    WHERE status = 'pendente';
+CREATE INDEX idx_webhook_entregas_assinatura ON webhook_entregas (assinatura_id, criado_em DESC, id DESC);
+
+CREATE TABLE webhook_tentativas (
+    id          BIGSERIAL PRIMARY KEY,
+    entrega_id  UUID NOT NULL REFERENCES webhook_entregas (id) ON DELETE CASCADE,
+    status_http INTEGER,
+    erro        TEXT,
+    duracao_ms  BIGINT NOT NULL,
+    ocorrido_em TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_webhook_tentativas_entrega ON webhook_tentativas (entrega_id, id);
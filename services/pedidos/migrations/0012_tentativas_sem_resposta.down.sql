@@ -0,0 +1,2 @@
+-- Os corpos de resposta apagados não são restaurados.
+SELECT 1;
@@ -0,0 +1,6 @@
+-- As tentativas de entrega de webhook guardavam até 500 caracteres do corpo da
+-- resposta do destino no erro, devolvidos pela API de histórico. Agora só o
+-- status é guardado; os corpos já gravados são apagados.
+UPDATE webhook_tentativas
+SET erro = 'destino respondeu ' || status_http
+WHERE status_http IS NOT NULL AND erro LIKE 'destino respondeu %';
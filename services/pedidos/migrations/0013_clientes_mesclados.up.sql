@@ -0,0 +1,9 @@
+-- Mesclagens recebidas do serviço de clientes (cliente.mesclado): o cliente
+-- de_cliente_id foi absorvido por para_cliente_id. Os eventos de clientes
+-- diferentes podem chegar fora de ordem; com o registro, A→B recebido depois
+-- de B→C ainda leva os pedidos de A até C. Um cliente é absorvido uma vez só.
+CREATE TABLE clientes_mesclados (
+    de_cliente_id   TEXT PRIMARY KEY,
+    para_cliente_id TEXT NOT NULL,
+    registrado_em   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,9 @@
+// Package migrations embute os scripts SQL do schema do serviço de pedidos.
+package migrations
+
+import "embed"
+
+// FS contém os arquivos <versao>_<nome>.up.sql e .down.sql aplicados por db.Migrador.
+//
+//go:embed *.sql
+var FS embed.FS